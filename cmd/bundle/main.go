@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/bundle"
+	"github.com/instill-ai/connector-backend/pkg/constant"
+	"github.com/instill-ai/connector-backend/pkg/external"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/connector-backend/pkg/service"
+
+	database "github.com/instill-ai/connector-backend/pkg/db"
+)
+
+const usage = `Usage:
+  bundle export [-owner users/{id}] [-format yaml|json] [-credentials omit|placeholder] [-out FILE]
+  bundle import [-owner users/{id}] [-format yaml|json] [-conflict fail|skip|overwrite|rename] [-dry-run] -in FILE
+`
+
+func main() {
+
+	if len(os.Args) < 2 || (os.Args[1] != "export" && os.Args[1] != "import") {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	ownerName := fs.String("owner", "users/"+constant.DefaultOwnerID, "owner of the connectors")
+	formatName := fs.String("format", "", "bundle format, guessed from the file extension if not set")
+	credentials := fs.String("credentials", string(bundle.CredentialOmit), "export credential fields as omit or placeholder")
+	conflict := fs.String("conflict", string(bundle.ConflictFail), "import strategy for existing connector ids")
+	dryRun := fs.Bool("dry-run", false, "validate the bundle without importing it")
+	in := fs.String("in", "", "bundle file to import")
+	out := fs.String("out", "", "bundle file to export to, stdout if not set")
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	if err := config.Init(); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, span := otel.Tracer("bundle-tracer").Start(ctx,
+		"main",
+	)
+	defer span.End()
+	defer cancel()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	db := database.GetConnection()
+	defer database.Close(db)
+
+	mgmtPrivateServiceClient, mgmtPrivateServiceClientConn := external.InitMgmtPrivateServiceClient(ctx)
+	if mgmtPrivateServiceClientConn != nil {
+		defer mgmtPrivateServiceClientConn.Close()
+	}
+
+	pipelinePublicServiceClient, pipelinePublicServiceClientConn := external.InitPipelinePublicServiceClient(ctx)
+	if pipelinePublicServiceClientConn != nil {
+		defer pipelinePublicServiceClientConn.Close()
+	}
+
+	controllerClient, controllerClientConn := external.InitControllerPrivateServiceClient(ctx)
+	if controllerClientConn != nil {
+		defer controllerClientConn.Close()
+	}
+
+	s := service.NewService(
+		ctx,
+		repository.NewRepository(db),
+		mgmtPrivateServiceClient,
+		pipelinePublicServiceClient,
+		controllerClient,
+	)
+
+	// The connectors of the builtin definitions are known once these are loaded
+	if err := s.LoadBuiltinConnectorDefinitions(ctx); err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	owner, err := resource.GetOwnerByName(ctx, mgmtPrivateServiceClient, *ownerName)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	path := *out
+	if command == "import" {
+		path = *in
+	}
+	if *formatName == "" {
+		*formatName = filepath.Ext(path)
+	}
+	format, err := bundle.ParseFormat(*formatName)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	switch command {
+	case "export":
+		mode, err := bundle.ParseCredentialMode(*credentials)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		b, err := s.ExportConnectors(ctx, owner, mode)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		data, err := bundle.Marshal(b, format)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		if path == "" {
+			_, err = os.Stdout.Write(data)
+		} else {
+			err = os.WriteFile(path, data, 0600)
+		}
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+
+	case "import":
+		if path == "" {
+			fs.Usage()
+			os.Exit(2)
+		}
+		strategy, err := bundle.ParseConflictStrategy(*conflict)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		b, err := bundle.Unmarshal(data, format)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		results, applied, err := s.ImportConnectors(ctx, owner, b, strategy, *dryRun)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tCONNECTOR ID\tACTION\tCONFLICT\tSTATUS")
+		for _, result := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", result.ID, result.ConnectorID, result.Action, result.Conflict, status.Convert(result.Err).Message())
+		}
+		_ = w.Flush()
+
+		if !applied {
+			fmt.Println("bundle not applied")
+			if !*dryRun {
+				os.Exit(1)
+			}
+		}
+	}
+}
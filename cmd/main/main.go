@@ -10,9 +10,9 @@ import (
 	"regexp"
 	"strings"
 	"syscall"
-	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
@@ -27,10 +27,16 @@ import (
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/audit"
+	"github.com/instill-ai/connector-backend/pkg/connector"
 	"github.com/instill-ai/connector-backend/pkg/external"
 	"github.com/instill-ai/connector-backend/pkg/handler"
+	"github.com/instill-ai/connector-backend/pkg/health"
+	"github.com/instill-ai/connector-backend/pkg/lifecycle"
 	"github.com/instill-ai/connector-backend/pkg/logger"
 	"github.com/instill-ai/connector-backend/pkg/middleware"
 	"github.com/instill-ai/connector-backend/pkg/repository"
@@ -39,6 +45,7 @@ import (
 
 	database "github.com/instill-ai/connector-backend/pkg/db"
 	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+	connectorExtPB "github.com/instill-ai/connector-backend/pkg/protogen/vdp/connector/v1alpha"
 	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
 )
 
@@ -69,12 +76,19 @@ func main() {
 	// setup tracing and metrics
 	ctx, cancel := context.WithCancel(context.Background())
 
+	// The lifecycle manager flushes the telemetry and the logs last at shutdown
+	lc := lifecycle.NewManager(ctx, config.Config.Server.Shutdown.DrainPeriod, config.Config.Server.Shutdown.Timeout)
+
 	if tp, err := custom_otel.SetupTracing(ctx, "connector-backend"); err != nil {
 		panic(err)
 	} else {
-		defer func() {
-			err = tp.Shutdown(ctx)
-		}()
+		lc.OnFlush("tracer provider", tp.Shutdown)
+	}
+
+	if mp, err := custom_otel.SetupMetrics(ctx, "connector-backend"); err != nil {
+		panic(err)
+	} else {
+		lc.OnFlush("meter provider", mp.Shutdown)
 	}
 
 	ctx, span := otel.Tracer("main-tracer").Start(ctx,
@@ -83,10 +97,11 @@ func main() {
 	defer cancel()
 
 	logger, _ := logger.GetZapLogger(ctx)
-	defer func() {
+	lc.OnFlush("logger", func(ctx context.Context) error {
 		// can't handle the error due to https://github.com/uber-go/zap/issues/880
 		_ = logger.Sync()
-	}()
+		return nil
+	})
 	grpc_zap.ReplaceGrpcLoggerV2(logger)
 
 	db := database.GetConnection()
@@ -105,25 +120,39 @@ func main() {
 	// Shared options for the logger, with a custom gRPC code to log level function.
 	opts := []grpc_zap.Option{
 		grpc_zap.WithDecider(func(fullMethodName string, err error) bool {
-			// will not log gRPC calls if it was a call to liveness or readiness and no error was raised
+			// will not log gRPC calls if it was a call to liveness, readiness or health and no error was raised
 			if err == nil {
 				if match, _ := regexp.MatchString("vdp.connector.v1alpha.ConnectorPublicService/.*ness$", fullMethodName); match {
 					return false
 				}
+				if match, _ := regexp.MatchString("grpc.health.v1.Health/", fullMethodName); match {
+					return false
+				}
 			}
 			// by default everything will be logged
 			return true
 		}),
 	}
 
+	mgmtPrivateServiceClient, mgmtPrivateServiceClientConn := external.InitMgmtPrivateServiceClient(ctx)
+	if mgmtPrivateServiceClientConn != nil {
+		defer mgmtPrivateServiceClientConn.Close()
+	}
+
 	grpcServerOpts := []grpc.ServerOption{
 		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
+			otelgrpc.StreamServerInterceptor(),
 			middleware.StreamAppendMetadataInterceptor,
+			middleware.StreamRequestContextInterceptor(mgmtPrivateServiceClient),
+			custom_otel.StreamServerInterceptor,
 			grpc_zap.StreamServerInterceptor(logger, opts...),
 			grpc_recovery.StreamServerInterceptor(middleware.RecoveryInterceptorOpt()),
 		)),
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+			otelgrpc.UnaryServerInterceptor(),
 			middleware.UnaryAppendMetadataInterceptor,
+			middleware.UnaryRequestContextInterceptor(mgmtPrivateServiceClient),
+			custom_otel.UnaryServerInterceptor,
 			grpc_zap.UnaryServerInterceptor(logger, opts...),
 			grpc_recovery.UnaryServerInterceptor(middleware.RecoveryInterceptorOpt()),
 		)),
@@ -132,11 +161,6 @@ func main() {
 		grpcServerOpts = append(grpcServerOpts, grpc.Creds(creds))
 	}
 
-	mgmtPrivateServiceClient, mgmtPrivateServiceClientConn := external.InitMgmtPrivateServiceClient(ctx)
-	if mgmtPrivateServiceClientConn != nil {
-		defer mgmtPrivateServiceClientConn.Close()
-	}
-
 	pipelinePublicServiceClient, pipelinePublicServiceClientConn := external.InitPipelinePublicServiceClient(ctx)
 	if pipelinePublicServiceClientConn != nil {
 		defer pipelinePublicServiceClientConn.Close()
@@ -149,12 +173,42 @@ func main() {
 
 	repository := repository.NewRepository(db)
 
+	if err := custom_otel.RegisterConnectorStateGauge(func(ctx context.Context) ([]*custom_otel.ConnectorStateCount, error) {
+		counts, err := repository.AggregateConnectorUsage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		byState := map[custom_otel.ConnectorStateCount]int64{}
+		for _, c := range counts {
+			byState[custom_otel.ConnectorStateCount{
+				ConnectorType: connectorPB.ConnectorType(c.ConnectorType).String(),
+				State:         connectorPB.Connector_State(c.State).String(),
+			}] += c.Count
+		}
+		stateCounts := make([]*custom_otel.ConnectorStateCount, 0, len(byState))
+		for key, count := range byState {
+			key.Count = count
+			stateCounts = append(stateCounts, &key)
+		}
+		return stateCounts, nil
+	}); err != nil {
+		logger.Fatal(err.Error())
+	}
+
 	privateGrpcS := grpc.NewServer(grpcServerOpts...)
 	reflection.Register(privateGrpcS)
 
 	publicGrpcS := grpc.NewServer(grpcServerOpts...)
 	reflection.Register(publicGrpcS)
 
+	// The standard health service reports the readiness of the dependencies
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(privateGrpcS, healthServer)
+	healthpb.RegisterHealthServer(publicGrpcS, healthServer)
+
+	// The connector registry is shared by the services and the usage reporter
+	connectorAll := connector.InitConnectorAll(logger)
+
 	connectorPB.RegisterConnectorPrivateServiceServer(
 		privateGrpcS,
 		handler.NewPrivateHandler(
@@ -167,17 +221,74 @@ func main() {
 				controllerClient,
 			)))
 
+	publicHandler := handler.NewPublicHandler(
+		ctx,
+		service.NewService(
+			ctx,
+			repository,
+			mgmtPrivateServiceClient,
+			pipelinePublicServiceClient,
+			controllerClient,
+		))
+
+	auditSink, err := audit.NewSink(repository, logger)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	publicHandler.SetAuditSink(auditSink)
+
+	healthChecker := health.NewChecker(
+		config.Config.Server.Readiness.CacheTTL,
+		config.Config.Server.Readiness.Timeout,
+		health.Dependency{Name: "database", Check: func(ctx context.Context) error {
+			return database.Ping(ctx, db)
+		}},
+		health.Dependency{Name: "controller", Check: health.ConnectionCheck(controllerClientConn)},
+		health.Dependency{Name: "mgmt-backend", Check: health.ConnectionCheck(mgmtPrivateServiceClientConn)},
+	)
+	publicHandler.SetHealth(healthChecker)
+	config.Subscribe(func(prev *config.AppConfig, next *config.AppConfig) {
+		healthChecker.Configure(next.Server.Readiness.CacheTTL, next.Server.Readiness.Timeout)
+	})
+	lc.Go(func(ctx context.Context) {
+		healthChecker.Watch(ctx, healthServer)
+	})
+
+	// The builtin definitions are shared by the services, they are loaded before
+	// the policies which may refer to them
+	if err := publicHandler.GetService().LoadBuiltinConnectorDefinitions(ctx); err != nil {
+		logger.Fatal(err.Error())
+	}
+	if interval := config.Config.Server.BuiltinConnector.RefreshInterval; interval > 0 {
+		lc.Go(func(ctx context.Context) {
+			publicHandler.GetService().WatchBuiltinConnectorDefinitions(ctx, interval)
+		})
+	}
+
+	if err := publicHandler.GetService().SeedConnectorDefinitionPolicies(ctx, config.Config.Server.DefinitionPolicies); err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	if config.Config.Server.ConfigurationCheck.Enabled {
+		lc.Go(func(ctx context.Context) {
+			if _, err := publicHandler.GetService().CheckConnectorConfigurations(ctx, config.Config.Server.ConfigurationCheck.DryRun); err != nil {
+				logger.Error(fmt.Sprintf("check connector configurations: %s", err.Error()))
+			}
+		})
+	}
+
 	connectorPB.RegisterConnectorPublicServiceServer(
 		publicGrpcS,
-		handler.NewPublicHandler(
-			ctx,
-			service.NewService(
-				ctx,
-				repository,
-				mgmtPrivateServiceClient,
-				pipelinePublicServiceClient,
-				controllerClient,
-			)))
+		publicHandler,
+	)
+	connectorExtPB.RegisterConnectorCloneServiceServer(
+		publicGrpcS,
+		publicHandler,
+	)
+	connectorExtPB.RegisterConnectorBatchServiceServer(
+		publicGrpcS,
+		publicHandler,
+	)
 
 	privateServeMux := runtime.NewServeMux(
 		runtime.WithForwardResponseOption(middleware.HttpResponseModifier),
@@ -217,22 +328,16 @@ func main() {
 		usageServiceClient, usageServiceClientConn := external.InitUsageServiceClient(ctx)
 		if usageServiceClientConn != nil {
 			defer usageServiceClientConn.Close()
-			logger.Info("try to start usage reporter")
-			go func() {
-				for {
-					usg = usage.NewUsage(ctx, repository, mgmtPrivateServiceClient, usageServiceClient)
-					if usg != nil {
-						usg.StartReporter(ctx)
-						logger.Info("usage reporter started")
-						break
-					}
-					logger.Warn("retry to start usage reporter after 5 minutes")
-					time.Sleep(5 * time.Minute)
-				}
-			}()
+			// The reports are spooled until the usage server is reachable
+			usg = usage.NewUsage(ctx, repository, mgmtPrivateServiceClient, usageServiceClient, connectorAll)
+			if usg != nil {
+				lc.Go(usg.RunReporter)
+				logger.Info("usage reporter started")
+			}
 		}
 
 	}
+	publicHandler.SetUsage(usg)
 
 	var dialOpts []grpc.DialOption
 	if config.Config.Server.HTTPS.Cert != "" && config.Config.Server.HTTPS.Key != "" {
@@ -249,6 +354,23 @@ func main() {
 		logger.Fatal(err.Error())
 	}
 
+	if err := handler.RegisterPublicCustomHandlers(publicServeMux, publicHandler); err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	// Prometheus scrapes the metrics on the private port
+	if metricsHandler, ok := custom_otel.PrometheusHandler(); ok {
+		if err := privateServeMux.HandlePath("GET", "/metrics", func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			metricsHandler.ServeHTTP(w, r)
+		}); err != nil {
+			logger.Fatal(err.Error())
+		}
+	}
+
+	if err := handler.RegisterPrivateCustomHandlers(privateServeMux, publicHandler); err != nil {
+		logger.Fatal(err.Error())
+	}
+
 	privateHTTPServer := &http.Server{
 		Addr:    fmt.Sprintf(":%v", config.Config.Server.PrivatePort),
 		Handler: grpcHandlerFunc(privateGrpcS, privateServeMux),
@@ -259,32 +381,69 @@ func main() {
 		Handler: grpcHandlerFunc(publicGrpcS, publicServeMux),
 	}
 
-	// Wait for interrupt signal to gracefully shutdown the server with a timeout of 5 seconds.
+	// On shutdown, the readiness is NOT_SERVING during the drain period, then
+	// the HTTP servers stop before the gRPC servers, which serve the gateway
+	// calls in flight, and the workers
+	lc.OnDrain(healthChecker.Shutdown)
+	lc.OnDrain(healthServer.Shutdown)
+	lc.OnStop("private HTTP server", privateHTTPServer.Shutdown)
+	lc.OnStop("public HTTP server", publicHTTPServer.Shutdown)
+	lc.OnStop("private gRPC server", lifecycle.StopGRPC(privateGrpcS))
+	lc.OnStop("public gRPC server", lifecycle.StopGRPC(publicGrpcS))
+	if usg != nil {
+		lc.OnStop("usage reporter", func(ctx context.Context) error {
+			if config.Current().Server.Usage.Enabled {
+				usg.TriggerSingleReporter(ctx)
+			}
+			return nil
+		})
+	}
+
 	quitSig := make(chan os.Signal, 1)
-	errSig := make(chan error)
+	errSig := make(chan error, 2)
+	serve := func(listenAndServe func() error) {
+		if err := listenAndServe(); err != nil && err != http.ErrServerClosed {
+			errSig <- err
+		}
+	}
 	if config.Config.Server.HTTPS.Cert != "" && config.Config.Server.HTTPS.Key != "" {
-		go func() {
-			if err := privateHTTPServer.ListenAndServeTLS(config.Config.Server.HTTPS.Cert, config.Config.Server.HTTPS.Key); err != nil {
-				errSig <- err
-			}
-		}()
-		go func() {
-			if err := publicHTTPServer.ListenAndServeTLS(config.Config.Server.HTTPS.Cert, config.Config.Server.HTTPS.Key); err != nil {
-				errSig <- err
-			}
-		}()
+		go serve(func() error {
+			return privateHTTPServer.ListenAndServeTLS(config.Config.Server.HTTPS.Cert, config.Config.Server.HTTPS.Key)
+		})
+		go serve(func() error {
+			return publicHTTPServer.ListenAndServeTLS(config.Config.Server.HTTPS.Cert, config.Config.Server.HTTPS.Key)
+		})
 	} else {
-		go func() {
-			if err := privateHTTPServer.ListenAndServe(); err != nil {
-				errSig <- err
-			}
-		}()
-		go func() {
-			if err := publicHTTPServer.ListenAndServe(); err != nil {
-				errSig <- err
-			}
-		}()
+		go serve(privateHTTPServer.ListenAndServe)
+		go serve(publicHTTPServer.ListenAndServe)
+	}
+	// The configuration is reloaded when its file changes or on SIGHUP
+	onReload := func(rejected []string, err error) {
+		if err != nil {
+			logger.Error(fmt.Sprintf("reload the configuration: %s", err.Error()))
+			return
+		}
+		if len(rejected) > 0 {
+			logger.Warn(fmt.Sprintf("configuration fields not reloadable, applied at the next restart: %s", strings.Join(rejected, ", ")))
+		}
+		logger.Info("configuration reloaded")
 	}
+	if err := config.Watch(onReload); err != nil {
+		logger.Error(fmt.Sprintf("watch the configuration file: %s", err.Error()))
+	}
+	hupSig := make(chan os.Signal, 1)
+	signal.Notify(hupSig, syscall.SIGHUP)
+	lc.Go(func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hupSig:
+				onReload(config.Reload())
+			}
+		}
+	})
+
 	span.End()
 	logger.Info("gRPC server is running.")
 
@@ -297,11 +456,7 @@ func main() {
 	case err := <-errSig:
 		logger.Error(fmt.Sprintf("Fatal error: %v\n", err))
 	case <-quitSig:
-		if config.Config.Server.Usage.Enabled && usg != nil {
-			usg.TriggerSingleReporter(ctx)
-		}
-		logger.Info("Shutting down server...")
-		privateGrpcS.GracefulStop()
-		publicGrpcS.GracefulStop()
 	}
+	logger.Info("Shutting down server...")
+	lc.Shutdown()
 }
@@ -1,140 +1,177 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
+	"log"
 	"os"
+	"strconv"
+	"text/tabwriter"
 
-	"github.com/golang-migrate/migrate/v4"
 	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/db/migration"
 
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	database "github.com/instill-ai/connector-backend/pkg/db"
 )
 
-func checkExist(databaseConfig config.DatabaseConfig) error {
-	db, err := sql.Open(
-		"postgres",
-		fmt.Sprintf("host=%s user=%s password=%s dbname=postgres port=%d sslmode=disable TimeZone=%s",
-			databaseConfig.Host,
-			databaseConfig.Username,
-			databaseConfig.Password,
-			databaseConfig.Port,
-			databaseConfig.TimeZone,
-		),
-	)
+const usage = `Usage:
+  migration [-dry-run]             apply the pending migrations up to the version of the database configuration
+  migration status                 print the status of the migrations
+  migration [-dry-run] up [N]      apply the next N pending migrations, all by default
+  migration [-dry-run] down N      revert the last N applied migrations
+
+Only the down command reverts migrations, migrating to a version below the
+applied one fails. With -dry-run, the SQL to be run is printed and nothing is
+applied.
+`
 
+func openPostgres(databaseConfig config.DatabaseConfig, name string) (*sql.DB, error) {
+	return database.OpenPostgres(&databaseConfig, name)
+}
+
+func checkExist(databaseConfig config.DatabaseConfig) error {
+	db, err := openPostgres(databaseConfig, "postgres")
 	if err != nil {
-		panic(err)
+		return err
 	}
-
 	defer db.Close()
 
 	// Open() may just validate its arguments without creating a connection to the database.
 	// To verify that the data source name is valid, call Ping().
 	if err = db.Ping(); err != nil {
-		panic(err)
-	}
-
-	var rows *sql.Rows
-	rows, err = db.Query(fmt.Sprintf("SELECT datname FROM pg_catalog.pg_database WHERE lower(datname) = lower('%s');", databaseConfig.Name))
-
-	if err != nil {
-		panic(err)
+		return err
 	}
 
-	dbExist := false
-	defer rows.Close()
-	for rows.Next() {
-		var databaseName string
-		if err := rows.Scan(&databaseName); err != nil {
-			panic(err)
-		}
-
-		if databaseConfig.Name == databaseName {
-			dbExist = true
-			fmt.Printf("Database %s exist\n", databaseName)
-		}
+	var dbExist bool
+	if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_database WHERE lower(datname) = lower($1))", databaseConfig.Name).Scan(&dbExist); err != nil {
+		return err
 	}
 
-	if err := rows.Err(); err != nil {
-		panic(err)
+	if dbExist {
+		fmt.Printf("Database %s exist\n", databaseConfig.Name)
+		return nil
 	}
 
-	if !dbExist {
-		fmt.Printf("Create database %s\n", databaseConfig.Name)
-		if _, err := db.Exec(fmt.Sprintf("CREATE DATABASE %s;", databaseConfig.Name)); err != nil {
-			return err
-		}
+	fmt.Printf("Create database %s\n", databaseConfig.Name)
+	if _, err := db.Exec(fmt.Sprintf("CREATE DATABASE %s;", databaseConfig.Name)); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 func main() {
-	migrateFolder, _ := os.Getwd()
 
+	fs := flag.NewFlagSet("migration", flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	dryRun := fs.Bool("dry-run", false, "print the SQL to be run without applying it")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		log.Fatal(err.Error())
+	}
+	args := fs.Args()
+
+	// config.Init parses the global command line flags, which know nothing of the migration flags
+	os.Args = os.Args[:1]
 	if err := config.Init(); err != nil {
-		panic(err)
+		log.Fatal(err.Error())
 	}
 
 	databaseConfig := config.Config.Database
-
-	if err := checkExist(databaseConfig); err != nil {
-		panic(err)
+	if databaseConfig.Driver == database.DriverSQLite {
+		fmt.Println("The sqlite schema is created when the service starts, there is nothing to migrate")
+		return
 	}
 
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?%s",
-		databaseConfig.Username,
-		databaseConfig.Password,
-		databaseConfig.Host,
-		databaseConfig.Port,
-		databaseConfig.Name,
-		"sslmode=disable",
-	)
+	command := ""
+	if len(args) > 0 {
+		command = args[0]
+	}
+	n := -1
+	switch {
+	case command == "status" && len(args) == 1:
+	case command == "" || (command == "up" && len(args) == 1):
+	case (command == "up" || command == "down") && len(args) == 2:
+		var err error
+		if n, err = strconv.Atoi(args[1]); err != nil || n < 0 {
+			fs.Usage()
+			os.Exit(2)
+		}
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
 
-	m, err := migrate.New(fmt.Sprintf("file:///%s/pkg/db/migration", migrateFolder), dsn)
+	if command != "status" && !*dryRun {
+		if err := checkExist(databaseConfig); err != nil {
+			log.Fatal(err.Error())
+		}
+	}
 
+	db, err := openPostgres(databaseConfig, databaseConfig.Name)
 	if err != nil {
-		panic(err)
+		log.Fatal(err.Error())
 	}
+	defer db.Close()
 
-	curVersion, dirty, err := m.Version()
-
-	if err != nil && curVersion != 0 {
-		panic(err)
+	steps, err := migration.Steps()
+	if err != nil {
+		log.Fatal(err.Error())
 	}
+	m := migration.NewMigrator(db, steps)
+	m.DryRun = *dryRun
+	m.Out = os.Stdout
 
-	ExpectedVersion := databaseConfig.Version
+	ctx := context.Background()
 
-	fmt.Printf("Expected migration version is %d\n", ExpectedVersion)
-	fmt.Printf("The current schema version is %d, and dirty flag is %t\n", curVersion, dirty)
-	if dirty {
-		panic("The database has dirty flag, please fix it")
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		log.Fatal(err.Error())
 	}
 
-	step := curVersion
-	for {
-		if ExpectedVersion <= step {
-			fmt.Printf("Migration to version %d complete\n", ExpectedVersion)
-			break
-		} else {
-			if step == 3 {
-				err := Migrate000003()
-				if err != nil {
-					panic(err)
+	var target uint
+	revert := false
+	switch command {
+	case "status":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tKIND\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt := "pending", ""
+			if s.Applied {
+				state = "applied"
+				if !s.AppliedAt.IsZero() {
+					appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
 				}
 			}
-			fmt.Printf("Step up to version %d\n", step+1)
-			if err := m.Steps(1); err != nil {
-				panic(err)
-			}
+			fmt.Fprintf(w, "%06d\t%s\t%s\t%s\t%s\n", s.Step.Version, s.Step.Name, s.Step.Kind, state, appliedAt)
 		}
-
-		step, _, err = m.Version()
-
-		if err != nil {
-			panic(err)
+		_ = w.Flush()
+		return
+	case "":
+		target = databaseConfig.Version
+		fmt.Printf("Expected migration version is %d\n", target)
+	case "up":
+		if n < 0 {
+			target = m.Latest()
+		} else {
+			target = migration.UpTarget(statuses, n)
 		}
+	case "down":
+		target = migration.DownTarget(statuses, n)
+		revert = true
+	}
+
+	// Only the down command reverts migrations, a target below the version of
+	// the database fails
+	migrate := m.Migrate
+	if revert {
+		migrate = m.Revert
+	}
+	if err := migrate(ctx, target); err != nil {
+		log.Fatal(err.Error())
+	}
+	if !*dryRun {
+		fmt.Printf("Migration to version %d complete\n", target)
 	}
 }
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gofrs/uuid"
+	"go.einride.tech/aip/filtering"
+	"go.opentelemetry.io/otel"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/connector"
+	"github.com/instill-ai/connector-backend/pkg/constant"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/external"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/connector-backend/pkg/service"
+
+	database "github.com/instill-ai/connector-backend/pkg/db"
+	connectorBase "github.com/instill-ai/connector/pkg/base"
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+const usage = `Usage:
+  reconcile -dir DIR [-owner users/{id}] [-apply]
+
+Converges the connectors of the owner on the manifests of DIR. The plan is
+printed and only applied with -apply.
+`
+
+func main() {
+
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	dir := fs.String("dir", "", "directory of the connector manifests")
+	ownerName := fs.String("owner", "users/"+constant.DefaultOwnerID, "owner of the connectors")
+	apply := fs.Bool("apply", false, "apply the plan")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		log.Fatal(err.Error())
+	}
+	if *dir == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	// config.Init parses the global command line flags, which know nothing of the reconcile flags
+	os.Args = os.Args[:1]
+	if err := config.Init(); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, span := otel.Tracer("reconcile-tracer").Start(ctx,
+		"main",
+	)
+	defer span.End()
+	defer cancel()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	desired, err := loadManifests(*dir)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	db := database.GetConnection()
+	defer database.Close(db)
+
+	mgmtPrivateServiceClient, mgmtPrivateServiceClientConn := external.InitMgmtPrivateServiceClient(ctx)
+	if mgmtPrivateServiceClientConn != nil {
+		defer mgmtPrivateServiceClientConn.Close()
+	}
+
+	pipelinePublicServiceClient, pipelinePublicServiceClientConn := external.InitPipelinePublicServiceClient(ctx)
+	if pipelinePublicServiceClientConn != nil {
+		defer pipelinePublicServiceClientConn.Close()
+	}
+
+	controllerClient, controllerClientConn := external.InitControllerPrivateServiceClient(ctx)
+	if controllerClientConn != nil {
+		defer controllerClientConn.Close()
+	}
+
+	repo := repository.NewRepository(db)
+
+	s := service.NewService(
+		ctx,
+		repo,
+		mgmtPrivateServiceClient,
+		pipelinePublicServiceClient,
+		controllerClient,
+	)
+
+	// The connectors of the builtin definitions are known once these are loaded
+	if err := s.LoadBuiltinConnectorDefinitions(ctx); err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	connectorAll := connector.InitConnectorAll(logger)
+
+	owner, err := resource.GetOwnerByName(ctx, mgmtPrivateServiceClient, *ownerName)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	ownerPermalink := service.GenOwnerPermalink(owner)
+
+	// List the connectors of the owner, the public connectors of other owners are left out
+	var current []*datamodel.Connector
+	pageToken := ""
+	for {
+		conns, _, nextPageToken, err := repo.ListConnectors(ctx, ownerPermalink, repository.MaxPageSize, pageToken, false, filtering.Filter{})
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		for _, conn := range conns {
+			if conn.Owner == ownerPermalink {
+				current = append(current, conn)
+			}
+		}
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
+	plan, err := makePlan(connectorAll, desired, current)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	if len(plan) == 0 {
+		fmt.Println("No changes, the connectors match the manifests.")
+		return
+	}
+
+	counts := map[actionType]int{}
+	for _, a := range plan {
+		fmt.Println(a)
+		counts[a.kind]++
+	}
+	fmt.Printf("\nPlan: %d to create, %d to update, %d to delete, %d to connect, %d to disconnect.\n",
+		counts[actionCreate], counts[actionUpdate], counts[actionDelete], counts[actionConnect], counts[actionDisconnect])
+
+	if !*apply {
+		fmt.Println("Run with -apply to apply the plan.")
+		return
+	}
+
+	for _, a := range plan {
+		if err := applyAction(ctx, s, connectorAll, owner, a); err != nil {
+			logger.Fatal(fmt.Sprintf("%s: %s", a, err.Error()))
+		}
+		fmt.Printf("applied %s\n", a)
+	}
+}
+
+// applyAction performs a plan action through the service, so that the
+// controller resource states are kept in sync
+func applyAction(ctx context.Context, s service.Service, connectorAll connectorBase.IConnector, owner *mgmtPB.User, a *action) error {
+
+	ownerPermalink := service.GenOwnerPermalink(owner)
+
+	switch a.kind {
+	case actionCreate:
+		connDef, err := connectorAll.GetConnectorDefinitionById(a.desired.ConnectorDefinition)
+		if err != nil {
+			return err
+		}
+		configuration, err := json.Marshal(a.desired.Configuration)
+		if err != nil {
+			return err
+		}
+		visibility := connectorPB.Connector_VISIBILITY_PRIVATE
+		if a.desired.Visibility != "" {
+			visibility = connectorPB.Connector_Visibility(connectorPB.Connector_Visibility_value[a.desired.Visibility])
+		}
+		_, err = s.CreateConnector(ctx, owner, &datamodel.Connector{
+			ID:                     a.id,
+			Owner:                  ownerPermalink,
+			ConnectorDefinitionUID: uuid.FromStringOrNil(connDef.GetUid()),
+			Description:            sql.NullString{String: a.desired.Description, Valid: len(a.desired.Description) > 0},
+			Tombstone:              false,
+			Configuration:          configuration,
+			ConnectorType:          datamodel.ConnectorType(connDef.GetConnectorType()),
+			Visibility:             datamodel.ConnectorVisibility(visibility),
+			Task:                   "TASK_UNSPECIFIED",
+		})
+		return err
+
+	case actionUpdate:
+		configuration, err := json.Marshal(a.desired.Configuration)
+		if err != nil {
+			return err
+		}
+		updated := &datamodel.Connector{
+			BaseDynamic:   datamodel.BaseDynamic{UID: a.current.UID},
+			ID:            a.id,
+			Description:   sql.NullString{String: a.desired.Description, Valid: len(a.desired.Description) > 0},
+			Configuration: configuration,
+		}
+		if a.desired.Visibility != "" {
+			updated.Visibility = datamodel.ConnectorVisibility(connectorPB.Connector_Visibility_value[a.desired.Visibility])
+		}
+		_, err = s.UpdateConnector(ctx, a.id, owner, updated)
+		return err
+
+	case actionDelete:
+		return s.DeleteConnector(ctx, a.id, owner)
+
+	case actionConnect:
+		results, err := s.BatchUpdateConnectorState(ctx, owner, []string{a.id}, datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED), true)
+		if err != nil {
+			return err
+		}
+		return results[0].Err
+
+	case actionDisconnect:
+		_, err := s.UpdateConnectorState(ctx, a.id, ownerPermalink, datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/instill-ai/connector-backend/pkg/bundle"
+	"github.com/instill-ai/connector-backend/pkg/connector"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+
+	connectorBase "github.com/instill-ai/connector/pkg/base"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+type actionType string
+
+const (
+	actionCreate     actionType = "create"
+	actionUpdate     actionType = "update"
+	actionDelete     actionType = "delete"
+	actionConnect    actionType = "connect"
+	actionDisconnect actionType = "disconnect"
+)
+
+// action is a step of the plan converging the connectors on the manifests
+type action struct {
+	kind    actionType
+	id      string
+	desired *bundle.Connector
+	current *datamodel.Connector
+	// changes lists the updated fields
+	changes []string
+}
+
+func (a *action) String() string {
+	symbol := map[actionType]string{
+		actionCreate:     "+",
+		actionUpdate:     "~",
+		actionDelete:     "-",
+		actionConnect:    ">",
+		actionDisconnect: "<",
+	}[a.kind]
+	s := fmt.Sprintf("%s %-10s %s", symbol, a.kind, a.id)
+	if len(a.changes) > 0 {
+		s += fmt.Sprintf(" (%s)", strings.Join(a.changes, ", "))
+	}
+	return s
+}
+
+// loadManifests reads the connector manifests of a directory tree. Each
+// manifest is a YAML or JSON connector bundle whose ${ENV} references are
+// substituted with the environment variables.
+func loadManifests(dir string) (map[string]*bundle.Connector, error) {
+	desired := map[string]*bundle.Connector{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if d.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			return nil
+		}
+
+		format, err := bundle.ParseFormat(ext)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		b, err := bundle.Unmarshal(data, format)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := bundle.Expand(b, os.LookupEnv); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, c := range b.Connectors {
+			if _, ok := desired[c.ID]; ok {
+				return fmt.Errorf("%s: connector %s is declared more than once", path, c.ID)
+			}
+			if c.Configuration == nil {
+				c.Configuration = map[string]interface{}{}
+			}
+			desired[c.ID] = c
+		}
+		return nil
+	})
+	return desired, err
+}
+
+// makePlan returns the actions converging the current connectors on the
+// desired ones. Connectors prefixed with instill- are managed by the init
+// command and never deleted.
+func makePlan(connectorAll connectorBase.IConnector, desired map[string]*bundle.Connector, current []*datamodel.Connector) ([]*action, error) {
+
+	currentByID := map[string]*datamodel.Connector{}
+	for _, c := range current {
+		currentByID[c.ID] = c
+	}
+
+	ids := make([]string, 0, len(desired))
+	for id := range desired {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var plan []*action
+	for _, id := range ids {
+		d := desired[id]
+
+		connDef, err := connectorAll.GetConnectorDefinitionById(d.ConnectorDefinition)
+		if err != nil {
+			return nil, fmt.Errorf("connector %s: connector definition %s not found", id, d.ConnectorDefinition)
+		}
+		if d.State != "" && d.State != connectorPB.Connector_STATE_CONNECTED.String() && d.State != connectorPB.Connector_STATE_DISCONNECTED.String() {
+			return nil, fmt.Errorf("connector %s: invalid state %s", id, d.State)
+		}
+		if d.Visibility != "" {
+			if _, ok := connectorPB.Connector_Visibility_value[d.Visibility]; !ok {
+				return nil, fmt.Errorf("connector %s: invalid visibility %s", id, d.Visibility)
+			}
+		}
+
+		c, ok := currentByID[id]
+		if !ok {
+			plan = append(plan, &action{kind: actionCreate, id: id, desired: d})
+			if d.State == connectorPB.Connector_STATE_CONNECTED.String() {
+				plan = append(plan, &action{kind: actionConnect, id: id, desired: d})
+			}
+			continue
+		}
+
+		if c.ConnectorDefinitionUID.String() != connDef.GetUid() {
+			return nil, fmt.Errorf("connector %s: the connector definition can not be changed from %s to %s, delete the connector first", id, c.ConnectorDefinitionUID, connDef.GetUid())
+		}
+
+		var changes []string
+		if d.Description != c.Description.String {
+			changes = append(changes, "description")
+		}
+		if d.Visibility != "" && d.Visibility != connectorPB.Connector_Visibility(c.Visibility).String() {
+			changes = append(changes, "visibility")
+		}
+		currentConfiguration := map[string]interface{}{}
+		if c.Configuration != nil {
+			if err := json.Unmarshal(c.Configuration, &currentConfiguration); err != nil {
+				return nil, fmt.Errorf("connector %s: %w", id, err)
+			}
+		}
+		// The stored configurations have the defaults of their definition
+		desiredConfiguration, err := withDefaults(connDef, d.Configuration)
+		if err != nil {
+			return nil, fmt.Errorf("connector %s: %w", id, err)
+		}
+		if !equalJSON(desiredConfiguration, currentConfiguration) {
+			changes = append(changes, "configuration")
+		}
+
+		currentState := connectorPB.Connector_State(c.State).String()
+		desiredState := d.State
+		if desiredState == "" {
+			desiredState = currentState
+		}
+
+		if len(changes) > 0 {
+			plan = append(plan, &action{kind: actionUpdate, id: id, desired: d, current: c, changes: changes})
+			// An updated connector is disconnected
+			currentState = connectorPB.Connector_STATE_DISCONNECTED.String()
+		}
+
+		switch {
+		case desiredState == connectorPB.Connector_STATE_CONNECTED.String() && currentState != desiredState:
+			plan = append(plan, &action{kind: actionConnect, id: id, desired: d, current: c})
+		case desiredState == connectorPB.Connector_STATE_DISCONNECTED.String() && currentState != desiredState:
+			plan = append(plan, &action{kind: actionDisconnect, id: id, desired: d, current: c})
+		}
+	}
+
+	var deleted []*action
+	for _, c := range current {
+		if _, ok := desired[c.ID]; ok || strings.HasPrefix(c.ID, "instill-") {
+			continue
+		}
+		deleted = append(deleted, &action{kind: actionDelete, id: c.ID, current: c})
+	}
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i].id < deleted[j].id })
+
+	return append(plan, deleted...), nil
+}
+
+// withDefaults returns a copy of the configuration with the defaults of the
+// connection specification, as they are set on create and update
+func withDefaults(connDef *connectorPB.ConnectorDefinition, configuration map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, err
+	}
+	c := map[string]interface{}{}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	connector.ApplyConfigurationDefaults(connDef, c)
+	return c, nil
+}
+
+// equalJSON compares two values by their JSON representation, so that
+// numbers decoded from YAML and JSON compare equal
+func equalJSON(a interface{}, b interface{}) bool {
+	normalize := func(v interface{}) interface{} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var n interface{}
+		if err := json.Unmarshal(data, &n); err != nil {
+			return v
+		}
+		return n
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/bundle"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+
+	connectorBase "github.com/instill-ai/connector/pkg/base"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// testConnectors only serves the connector definitions used by makePlan
+type testConnectors struct {
+	connectorBase.IConnector
+	definitions map[string]*connectorPB.ConnectorDefinition
+}
+
+func (c *testConnectors) GetConnectorDefinitionById(defID string) (*connectorPB.ConnectorDefinition, error) {
+	if def, ok := c.definitions[defID]; ok {
+		return def, nil
+	}
+	return nil, fmt.Errorf("connector definition %s not found", defID)
+}
+
+func newTestConnectors(defs ...*connectorPB.ConnectorDefinition) *testConnectors {
+	c := &testConnectors{definitions: map[string]*connectorPB.ConnectorDefinition{}}
+	for _, def := range defs {
+		c.definitions[def.GetId()] = def
+	}
+	return c
+}
+
+func newTestDefinition(id string) *connectorPB.ConnectorDefinition {
+	return &connectorPB.ConnectorDefinition{Uid: uuid.Must(uuid.NewV4()).String(), Id: id}
+}
+
+func newCurrent(t *testing.T, connDef *connectorPB.ConnectorDefinition, id string, state connectorPB.Connector_State, configuration map[string]interface{}) *datamodel.Connector {
+	t.Helper()
+	b, err := json.Marshal(configuration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &datamodel.Connector{
+		ID:                     id,
+		ConnectorDefinitionUID: uuid.FromStringOrNil(connDef.GetUid()),
+		Configuration:          b,
+		State:                  datamodel.ConnectorState(state),
+		Visibility:             datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PRIVATE),
+	}
+}
+
+// applyPlan applies the actions to the current connectors as the reconcile
+// command does through the service, which stores the configurations with
+// their defaults
+func applyPlan(t *testing.T, connectorAll connectorBase.IConnector, current []*datamodel.Connector, plan []*action) []*datamodel.Connector {
+	t.Helper()
+	byID := map[string]*datamodel.Connector{}
+	var ids []string
+	for _, c := range current {
+		byID[c.ID] = c
+		ids = append(ids, c.ID)
+	}
+	for _, a := range plan {
+		switch a.kind {
+		case actionCreate, actionUpdate:
+			connDef, err := connectorAll.GetConnectorDefinitionById(a.desired.ConnectorDefinition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			configuration, err := withDefaults(connDef, a.desired.Configuration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := newCurrent(t, connDef, a.id, connectorPB.Connector_STATE_DISCONNECTED, configuration)
+			c.Description.String, c.Description.Valid = a.desired.Description, a.desired.Description != ""
+			if a.desired.Visibility != "" {
+				c.Visibility = datamodel.ConnectorVisibility(connectorPB.Connector_Visibility_value[a.desired.Visibility])
+			}
+			if _, ok := byID[a.id]; !ok {
+				ids = append(ids, a.id)
+			}
+			byID[a.id] = c
+		case actionConnect:
+			byID[a.id].State = datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED)
+		case actionDisconnect:
+			byID[a.id].State = datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED)
+		case actionDelete:
+			delete(byID, a.id)
+		}
+	}
+	var next []*datamodel.Connector
+	for _, id := range ids {
+		if c, ok := byID[id]; ok {
+			next = append(next, c)
+		}
+	}
+	return next
+}
+
+func planStrings(plan []*action) []string {
+	s := make([]string, len(plan))
+	for idx, a := range plan {
+		s[idx] = a.String()
+	}
+	return s
+}
+
+func TestMakePlan(t *testing.T) {
+	connDef := newTestDefinition("destination-test")
+	connectorAll := newTestConnectors(connDef)
+
+	desired := map[string]*bundle.Connector{
+		"created":      {ConnectorDefinition: connDef.GetId(), ID: "created", State: "STATE_CONNECTED", Configuration: map[string]interface{}{"port": 1}},
+		"updated":      {ConnectorDefinition: connDef.GetId(), ID: "updated", Description: "new", Configuration: map[string]interface{}{"port": 2}},
+		"connected":    {ConnectorDefinition: connDef.GetId(), ID: "connected", State: "STATE_CONNECTED", Configuration: map[string]interface{}{}},
+		"disconnected": {ConnectorDefinition: connDef.GetId(), ID: "disconnected", State: "STATE_DISCONNECTED", Configuration: map[string]interface{}{}},
+		"unchanged":    {ConnectorDefinition: connDef.GetId(), ID: "unchanged", Configuration: map[string]interface{}{"port": 3}},
+	}
+	current := []*datamodel.Connector{
+		newCurrent(t, connDef, "updated", connectorPB.Connector_STATE_CONNECTED, map[string]interface{}{"port": 1}),
+		newCurrent(t, connDef, "connected", connectorPB.Connector_STATE_DISCONNECTED, map[string]interface{}{}),
+		newCurrent(t, connDef, "disconnected", connectorPB.Connector_STATE_CONNECTED, map[string]interface{}{}),
+		newCurrent(t, connDef, "unchanged", connectorPB.Connector_STATE_CONNECTED, map[string]interface{}{"port": 3.0}),
+		newCurrent(t, connDef, "removed", connectorPB.Connector_STATE_CONNECTED, map[string]interface{}{}),
+		newCurrent(t, connDef, "instill-managed", connectorPB.Connector_STATE_CONNECTED, map[string]interface{}{}),
+	}
+
+	plan, err := makePlan(connectorAll, desired, current)
+	if err != nil {
+		t.Fatalf("make plan: %v", err)
+	}
+	// The updated connector without a desired state is connected again
+	want := []string{
+		"> connect    connected",
+		"+ create     created",
+		"> connect    created",
+		"< disconnect disconnected",
+		"~ update     updated (description, configuration)",
+		"> connect    updated",
+		"- delete     removed",
+	}
+	if got := planStrings(plan); !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan:\ngot  %q\nwant %q", got, want)
+	}
+
+	// The plan converges, a second run does nothing
+	current = applyPlan(t, connectorAll, current, plan)
+	plan, err = makePlan(connectorAll, desired, current)
+	if err != nil {
+		t.Fatalf("make plan again: %v", err)
+	}
+	if len(plan) != 0 {
+		t.Fatalf("plan of the second run: got %q, want none", planStrings(plan))
+	}
+}
+
+func TestMakePlanDefaults(t *testing.T) {
+	spec, err := structpb.NewStruct(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"host": map[string]interface{}{"type": "string"},
+			"port": map[string]interface{}{"type": "integer", "default": 5432},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	connDef := newTestDefinition("destination-test")
+	connDef.Spec = &connectorPB.Spec{ConnectionSpecification: spec}
+	connectorAll := newTestConnectors(connDef)
+
+	desired := map[string]*bundle.Connector{
+		"defaults": {ConnectorDefinition: connDef.GetId(), ID: "defaults", Configuration: map[string]interface{}{"host": "localhost"}},
+	}
+	plan, err := makePlan(connectorAll, desired, nil)
+	if err != nil {
+		t.Fatalf("make plan: %v", err)
+	}
+	if got, want := planStrings(plan), []string{"+ create     defaults"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan: got %q, want %q", got, want)
+	}
+
+	// The stored default is not an update of the configuration
+	current := applyPlan(t, connectorAll, nil, plan)
+	if string(current[0].Configuration) != `{"host":"localhost","port":5432}` {
+		t.Fatalf("stored configuration: got %s", current[0].Configuration)
+	}
+	plan, err = makePlan(connectorAll, desired, current)
+	if err != nil {
+		t.Fatalf("make plan again: %v", err)
+	}
+	if len(plan) != 0 {
+		t.Fatalf("plan of the second run: got %q, want none", planStrings(plan))
+	}
+
+	// A configuration set to another value than the default is updated
+	desired["defaults"].Configuration["port"] = 5433
+	plan, err = makePlan(connectorAll, desired, current)
+	if err != nil {
+		t.Fatalf("make plan of a changed port: %v", err)
+	}
+	if got, want := planStrings(plan), []string{"~ update     defaults (configuration)"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("plan of a changed port: got %q, want %q", got, want)
+	}
+}
+
+func TestMakePlanErrors(t *testing.T) {
+	connDef := newTestDefinition("destination-test")
+	other := newTestDefinition("destination-other")
+	connectorAll := newTestConnectors(connDef, other)
+
+	for name, c := range map[string]struct {
+		desired *bundle.Connector
+		current []*datamodel.Connector
+	}{
+		"unknown definition": {
+			desired: &bundle.Connector{ConnectorDefinition: "destination-unknown", ID: "c"},
+		},
+		"invalid state": {
+			desired: &bundle.Connector{ConnectorDefinition: connDef.GetId(), ID: "c", State: "STATE_ERROR"},
+		},
+		"invalid visibility": {
+			desired: &bundle.Connector{ConnectorDefinition: connDef.GetId(), ID: "c", Visibility: "VISIBILITY_SHARED"},
+		},
+		"changed definition": {
+			desired: &bundle.Connector{ConnectorDefinition: other.GetId(), ID: "c", Configuration: map[string]interface{}{}},
+			current: []*datamodel.Connector{newCurrent(t, connDef, "c", connectorPB.Connector_STATE_CONNECTED, map[string]interface{}{})},
+		},
+	} {
+		if _, err := makePlan(connectorAll, map[string]*bundle.Connector{"c": c.desired}, c.current); err == nil {
+			t.Errorf("%s: got no error", name)
+		}
+	}
+}
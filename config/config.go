@@ -2,8 +2,10 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
@@ -13,7 +15,8 @@ import (
 	"github.com/knadh/koanf/providers/file"
 )
 
-// Config - Global variable to export
+// Config - Global variable to export. It is the configuration at startup,
+// the reloadable fields are read from Current.
 var Config AppConfig
 
 // AppConfig defines
@@ -37,15 +40,107 @@ type ServerConfig struct {
 	}
 	Edition string `koanf:"edition"`
 	Usage   struct {
-		Enabled    bool   `koanf:"enabled"`
-		TLSEnabled bool   `koanf:"tlsenabled"`
-		Host       string `koanf:"host"`
-		Port       int    `koanf:"port"`
+		Enabled bool      `koanf:"enabled"`
+		TLS     TLSConfig `koanf:"tls"`
+		Host    string    `koanf:"host"`
+		Port    int       `koanf:"port"`
+		// Spool keeps the usage reports on disk until they are sent
+		Spool struct {
+			Dir string `koanf:"dir"`
+			// MaxSize in bytes, the oldest reports are dropped beyond
+			MaxSize int64 `koanf:"maxsize"`
+			// SegmentSize in bytes of the spool files
+			SegmentSize int64 `koanf:"segmentsize"`
+		}
 	}
-	Debug             bool `koanf:"debug"`
+	Debug             bool     `koanf:"debug"`
+	Admins            []string `koanf:"admins"`
 	PrebuiltConnector struct {
 		Enabled bool `koanf:"enabled"`
 	}
+	// BuiltinConnector reloads the builtin connector definitions from the
+	// database, so that the definitions uploaded through another replica are
+	// served
+	BuiltinConnector struct {
+		// RefreshInterval between the reloads, e.g., 30s, 0 disables them
+		RefreshInterval time.Duration `koanf:"refreshinterval"`
+	}
+	// DefinitionPolicies seed the connector definition policies at startup,
+	// the policies already stored, e.g., set by an admin, are left unchanged
+	DefinitionPolicies []DefinitionPolicyConfig `koanf:"definitionpolicies"`
+	// ConfigurationCheck validates the stored connector configurations against
+	// the current connection specifications at startup
+	ConfigurationCheck struct {
+		Enabled bool `koanf:"enabled"`
+		// DryRun only reports the connectors to migrate or move to STATE_ERROR
+		DryRun bool `koanf:"dryrun"`
+	}
+	// Metering counts the connector executions per owner and enforces the
+	// execution quotas
+	Metering struct {
+		Enabled bool `koanf:"enabled"`
+		// Window is the time window of the stored counters, e.g., 1h
+		Window time.Duration `koanf:"window"`
+		// DefaultPlan is the quota plan of the owners not listed in Owners
+		DefaultPlan string             `koanf:"defaultplan"`
+		Plans       []QuotaPlanConfig  `koanf:"plans"`
+		Owners      []OwnerQuotaConfig `koanf:"owners"`
+	}
+	// Audit records the connector changes and executions
+	Audit struct {
+		Enabled bool `koanf:"enabled"`
+		// Sinks the audit events are written to, database and/or log. The
+		// events can only be listed from the database.
+		Sinks []string `koanf:"sinks"`
+	}
+	// Readiness checks the database, the controller and the management backend
+	Readiness struct {
+		// CacheTTL is the time the results of the checks are reused
+		CacheTTL time.Duration `koanf:"cachettl"`
+		// Timeout of each check
+		Timeout time.Duration `koanf:"timeout"`
+	}
+	// Shutdown drains the traffic before the servers and the workers stop
+	Shutdown struct {
+		// DrainPeriod is the time the readiness is NOT_SERVING before the
+		// servers stop
+		DrainPeriod time.Duration `koanf:"drainperiod"`
+		// Timeout to stop the servers and the workers, and then to flush the
+		// telemetry
+		Timeout time.Duration `koanf:"timeout"`
+	}
+}
+
+// QuotaPlanConfig defines the execution quota of a plan over a period, a
+// limit of 0 or less is unlimited
+type QuotaPlanConfig struct {
+	Name string `koanf:"name"`
+	// Period of the quota, e.g., 24h, a multiple of the metering window
+	Period       time.Duration `koanf:"period"`
+	Executions   int64         `koanf:"executions"`
+	InputItems   int64         `koanf:"inputitems"`
+	PayloadBytes int64         `koanf:"payloadbytes"`
+}
+
+// OwnerQuotaConfig assigns a quota plan to an owner and optionally overrides
+// its limits, a limit of 0 keeps the limit of the plan and a negative limit
+// is unlimited
+type OwnerQuotaConfig struct {
+	// ID is the user id
+	ID           string `koanf:"id"`
+	Plan         string `koanf:"plan"`
+	Executions   int64  `koanf:"executions"`
+	InputItems   int64  `koanf:"inputitems"`
+	PayloadBytes int64  `koanf:"payloadbytes"`
+}
+
+// DefinitionPolicyConfig defines the policy of a connector definition
+type DefinitionPolicyConfig struct {
+	// ID is the connector definition id
+	ID string `koanf:"id"`
+	// State is enabled, deprecated or hidden
+	State   string `koanf:"state"`
+	Message string `koanf:"message"`
 }
 
 // ContainerConfig defines the container configurations
@@ -62,48 +157,62 @@ type ContainerConfig struct {
 
 // DatabaseConfig related to database
 type DatabaseConfig struct {
+	// Driver is either postgres (default) or sqlite
+	Driver   string `koanf:"driver"`
 	Username string `koanf:"username"`
-	Password string `koanf:"password"`
+	Password string `koanf:"password" secret:"true"`
 	Host     string `koanf:"host"`
 	Port     int    `koanf:"port"`
 	Name     string `koanf:"name"`
 	Version  uint   `koanf:"version"`
 	TimeZone string `koanf:"timezone"`
-	Pool     struct {
+	// Path is the database file of the sqlite driver
+	Path string `koanf:"path"`
+	Pool struct {
 		IdleConnections int           `koanf:"idleconnections"`
 		MaxConnections  int           `koanf:"maxconnections"`
 		ConnLifeTime    time.Duration `koanf:"connlifetime"`
 	}
+	// TLS of the postgres connections, whose server certificate is verified
+	// as with sslmode verify-full
+	TLS TLSConfig `koanf:"tls"`
 }
 
 // MgmtBackendConfig related to mgmt-backend
 type MgmtBackendConfig struct {
-	Host        string `koanf:"host"`
-	PrivatePort int    `koanf:"privateport"`
-	HTTPS       struct {
-		Cert string `koanf:"cert"`
-		Key  string `koanf:"key"`
-	}
+	Host        string    `koanf:"host"`
+	PrivatePort int       `koanf:"privateport"`
+	TLS         TLSConfig `koanf:"tls"`
 }
 
 // PipelineBackendConfig related to pipeline-backend
 type PipelineBackendConfig struct {
-	Host       string `koanf:"host"`
-	PublicPort int    `koanf:"publicport"`
-	HTTPS      struct {
-		Cert string `koanf:"cert"`
-		Key  string `koanf:"key"`
-	}
+	Host       string    `koanf:"host"`
+	PublicPort int       `koanf:"publicport"`
+	TLS        TLSConfig `koanf:"tls"`
 }
 
 // ControllerConfig related to controller
 type ControllerConfig struct {
-	Host        string `koanf:"host"`
-	PrivatePort int    `koanf:"privateport"`
-	HTTPS       struct {
-		Cert string `koanf:"cert"`
-		Key  string `koanf:"key"`
-	}
+	Host        string    `koanf:"host"`
+	PrivatePort int       `koanf:"privateport"`
+	TLS         TLSConfig `koanf:"tls"`
+}
+
+// TLSConfig configures the TLS of an outbound connection, the certificates
+// are reloaded when their files change
+type TLSConfig struct {
+	Enabled bool `koanf:"enabled"`
+	// CA is the CA bundle verifying the server certificate, the system roots
+	// if empty
+	CA string `koanf:"ca"`
+	// Cert and Key are the client certificate and key of the mutual TLS
+	Cert string `koanf:"cert"`
+	Key  string `koanf:"key"`
+	// ServerName is verified by the server certificate, the host if empty
+	ServerName string `koanf:"servername"`
+	// MinVersion is 1.2, the default, or 1.3
+	MinVersion string `koanf:"minversion"`
 }
 
 // LogConfig related to logging
@@ -113,21 +222,60 @@ type LogConfig struct {
 		Host string `koanf:"host"`
 		Port string `koanf:"port"`
 	}
+	// Metrics exporters, OTLP and Prometheus can be enabled together
+	Metrics struct {
+		// OTLP exports the metrics to the collector if the logs are external
+		OTLP bool `koanf:"otlp"`
+		// Prometheus serves the metrics on /metrics of the private port
+		Prometheus bool `koanf:"prometheus"`
+		// Stdout prints the metrics to the standard output if the logs are not external
+		Stdout bool `koanf:"stdout"`
+	}
 }
 
 // Init - Assign global config to decoded config struct
 func Init() error {
 
-	k := koanf.New(".")
-	parser := yaml.Parser()
-
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	fileRelativePath := fs.String("file", "config/config.yaml", "configuration file")
-	flag.Parse()
+	checkConfig := fs.Bool("check-config", false, "validate the configuration, print it with the secrets redacted and exit")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		log.Fatal(err.Error())
+	}
+	filePath = *fileRelativePath
 
-	if err := k.Load(file.Provider(*fileRelativePath), parser); err != nil {
+	cfg, err := load(filePath)
+	if err != nil {
 		log.Fatal(err.Error())
 	}
+	Config = *cfg
+	current.Store(cfg)
+
+	if *checkConfig {
+		if err := ValidateConfig(&Config); err != nil {
+			log.Fatal(err.Error())
+		}
+		b, err := yaml.Parser().Marshal(configValue(reflect.ValueOf(&Config).Elem(), true).(map[string]interface{}))
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		fmt.Print(string(b))
+		os.Exit(0)
+	}
+
+	return ValidateConfig(&Config)
+}
+
+// load decodes the configuration file, overridden by the CFG_ environment
+// variables
+func load(path string) (*AppConfig, error) {
+
+	k := koanf.New(".")
+	parser := yaml.Parser()
+
+	if err := k.Load(file.Provider(path), parser); err != nil {
+		return nil, err
+	}
 
 	if err := k.Load(env.ProviderWithValue("CFG_", ".", func(s string, v string) (string, interface{}) {
 		key := strings.Replace(strings.ToLower(strings.TrimPrefix(s, "CFG_")), "_", ".", -1)
@@ -136,17 +284,48 @@ func Init() error {
 		}
 		return key, v
 	}), nil); err != nil {
-		log.Fatal(err.Error())
+		return nil, err
 	}
 
-	if err := k.Unmarshal("", &Config); err != nil {
-		log.Fatal(err.Error())
+	if err := applyDeprecatedKeys(k); err != nil {
+		return nil, err
 	}
 
-	return ValidateConfig(&Config)
+	cfg := &AppConfig{}
+	if err := k.Unmarshal("", cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
-// ValidateConfig is for custom validation rules for the configuration
-func ValidateConfig(cfg *AppConfig) error {
+// applyDeprecatedKeys reads the keys replaced by the TLS configurations, from
+// the file or the CFG_ environment variables, into their replacement with a
+// deprecation warning. The HTTPS certificate and key of a backend enabled its
+// TLS, they are the client certificate and key of the mutual TLS.
+func applyDeprecatedKeys(k *koanf.Koanf) error {
+
+	if k.Exists("server.usage.tlsenabled") {
+		log.Printf("server.usage.tlsenabled is deprecated, use server.usage.tls.enabled")
+		if err := k.Set("server.usage.tls.enabled", k.Get("server.usage.tlsenabled")); err != nil {
+			return err
+		}
+	}
+
+	for _, backend := range []string{"pipelinebackend", "mgmtbackend", "controller"} {
+		cert, key := k.String(backend+".https.cert"), k.String(backend+".https.key")
+		if cert == "" && key == "" {
+			continue
+		}
+		log.Printf("%s.https is deprecated, use %s.tls", backend, backend)
+		if cert == "" || key == "" {
+			return fmt.Errorf("%s.https: both the certificate and the key are required, use %s.tls", backend, backend)
+		}
+		for name, value := range map[string]interface{}{"enabled": true, "cert": cert, "key": key} {
+			if err := k.Set(fmt.Sprintf("%s.tls.%s", backend, name), value); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
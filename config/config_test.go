@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDeprecatedKeys(t *testing.T) {
+	cfg, err := load(writeConfig(t, `
+server:
+  usage:
+    tlsenabled: true
+mgmtbackend:
+  https:
+    cert: /etc/tls/mgmt.crt
+    key: /etc/tls/mgmt.key
+controller:
+  tls:
+    enabled: false
+`))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !cfg.Server.Usage.TLS.Enabled {
+		t.Errorf("server.usage.tlsenabled is not read into server.usage.tls.enabled")
+	}
+	if tls := cfg.MgmtBackend.TLS; !tls.Enabled || tls.Cert != "/etc/tls/mgmt.crt" || tls.Key != "/etc/tls/mgmt.key" {
+		t.Errorf("mgmtbackend.https is not read into mgmtbackend.tls: got %+v", tls)
+	}
+	if cfg.Controller.TLS.Enabled {
+		t.Errorf("controller.tls is enabled without any deprecated key")
+	}
+}
+
+func TestLoadDeprecatedEnvironmentKeys(t *testing.T) {
+	t.Setenv("CFG_SERVER_USAGE_TLSENABLED", "true")
+	t.Setenv("CFG_CONTROLLER_HTTPS_CERT", "/etc/tls/controller.crt")
+	t.Setenv("CFG_CONTROLLER_HTTPS_KEY", "/etc/tls/controller.key")
+	cfg, err := load(writeConfig(t, `
+server:
+  usage:
+    tls:
+      enabled: false
+`))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !cfg.Server.Usage.TLS.Enabled {
+		t.Errorf("CFG_SERVER_USAGE_TLSENABLED is not read into server.usage.tls.enabled")
+	}
+	if tls := cfg.Controller.TLS; !tls.Enabled || tls.Cert != "/etc/tls/controller.crt" || tls.Key != "/etc/tls/controller.key" {
+		t.Errorf("CFG_CONTROLLER_HTTPS_* are not read into controller.tls: got %+v", tls)
+	}
+}
+
+func TestLoadDeprecatedKeysWithoutKey(t *testing.T) {
+	if _, err := load(writeConfig(t, `
+pipelinebackend:
+  https:
+    cert: /etc/tls/pipeline.crt
+`)); err == nil {
+		t.Fatalf("load pipelinebackend.https without a key: got no error")
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/knadh/koanf/providers/file"
+)
+
+// filePath is the configuration file, which is reloaded
+var filePath string
+
+// current is the configuration with the reloadable fields applied last
+var current atomic.Pointer[AppConfig]
+
+var reloadMu sync.Mutex
+var subscribers []func(prev *AppConfig, next *AppConfig)
+
+// Current returns the current configuration, whose reloadable fields may
+// differ from Config after a reload. The configuration must not be modified.
+func Current() *AppConfig {
+	if cfg := current.Load(); cfg != nil {
+		return cfg
+	}
+	return &Config
+}
+
+// Subscribe registers a function called with the previous and the next
+// configuration after a reload, in order of registration
+func Subscribe(fn func(prev *AppConfig, next *AppConfig)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+// applyReloadable copies the reloadable fields, the usage reporting is only
+// paused or resumed if it is enabled at startup
+func applyReloadable(dst *AppConfig, src *AppConfig) {
+	dst.Server.Debug = src.Server.Debug
+	dst.Server.Admins = src.Server.Admins
+	if Config.Server.Usage.Enabled {
+		dst.Server.Usage.Enabled = src.Server.Usage.Enabled
+	}
+	dst.Server.Metering.Enabled = src.Server.Metering.Enabled
+	dst.Server.Metering.DefaultPlan = src.Server.Metering.DefaultPlan
+	dst.Server.Metering.Plans = src.Server.Metering.Plans
+	dst.Server.Metering.Owners = src.Server.Metering.Owners
+	dst.Server.Readiness = src.Server.Readiness
+	dst.Database.Pool = src.Database.Pool
+}
+
+// Reload loads the configuration file and applies its reloadable fields at
+// once, if the configuration is valid. The changed fields which are not
+// reloadable are kept and returned, they are applied at the next restart.
+func Reload() (rejected []string, err error) {
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	loaded, err := load(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateConfig(loaded); err != nil {
+		return nil, err
+	}
+
+	prev := Current()
+	next := *prev
+	applyReloadable(&next, loaded)
+
+	rejected = changedFields("", configValue(reflect.ValueOf(&next).Elem(), false), configValue(reflect.ValueOf(loaded).Elem(), false))
+	sort.Strings(rejected)
+
+	if reflect.DeepEqual(prev, &next) {
+		return rejected, nil
+	}
+	current.Store(&next)
+	for _, fn := range subscribers {
+		fn(prev, &next)
+	}
+
+	return rejected, nil
+}
+
+// changedFields returns the paths of the fields which differ
+func changedFields(path string, a interface{}, b interface{}) []string {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if !aok || !bok {
+		if reflect.DeepEqual(a, b) {
+			return nil
+		}
+		return []string{path}
+	}
+	changed := []string{}
+	for key := range am {
+		changed = append(changed, changedFields(strings.TrimPrefix(path+"."+key, "."), am[key], bm[key])...)
+	}
+	return changed
+}
+
+// Watch reloads the configuration when the file changes, onReload is called
+// with the result of every reload
+func Watch(onReload func(rejected []string, err error)) error {
+	return file.Provider(filePath).Watch(func(event interface{}, err error) {
+		if err != nil {
+			onReload(nil, fmt.Errorf("watch %s: %w", filePath, err))
+			return
+		}
+		onReload(Reload())
+	})
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// initReload loads a copy of the repository configuration as Init does, the
+// environment variables of the tests change it on reload
+func initReload(t *testing.T) {
+	t.Helper()
+	b, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	prevFilePath, prevConfig, prevCurrent, prevSubscribers := filePath, Config, current.Load(), subscribers
+	t.Cleanup(func() {
+		filePath, Config, subscribers = prevFilePath, prevConfig, prevSubscribers
+		current.Store(prevCurrent)
+	})
+
+	filePath = path
+	cfg, err := load(filePath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	Config = *cfg
+	current.Store(cfg)
+}
+
+func TestReload(t *testing.T) {
+	initReload(t)
+	started := Current()
+
+	var calls int
+	Subscribe(func(prev *AppConfig, next *AppConfig) {
+		calls++
+		if prev != started || next.Server.Debug {
+			t.Errorf("reload subscriber: got debug %v and %v, want the started configuration and false", prev.Server.Debug, next.Server.Debug)
+		}
+	})
+
+	t.Setenv("CFG_SERVER_DEBUG", "false")
+	t.Setenv("CFG_SERVER_ADMINS", "admin,other-admin")
+	t.Setenv("CFG_SERVER_PUBLICPORT", "9082")
+	t.Setenv("CFG_DATABASE_POOL_MAXCONNECTIONS", "20")
+
+	rejected, err := Reload()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if !reflect.DeepEqual(rejected, []string{"server.publicport"}) {
+		t.Errorf("fields not reloadable: got %v, want [server.publicport]", rejected)
+	}
+	cfg := Current()
+	if cfg.Server.Debug || !reflect.DeepEqual(cfg.Server.Admins, []string{"admin", "other-admin"}) || cfg.Database.Pool.MaxConnections != 20 {
+		t.Errorf("reloaded fields: got debug %v, admins %v and %d max connections", cfg.Server.Debug, cfg.Server.Admins, cfg.Database.Pool.MaxConnections)
+	}
+	if cfg.Server.PublicPort != started.Server.PublicPort {
+		t.Errorf("server.publicport is reloaded: got %d, want %d", cfg.Server.PublicPort, started.Server.PublicPort)
+	}
+	// Config keeps the configuration of the startup
+	if !Config.Server.Debug {
+		t.Errorf("Config is modified by the reload")
+	}
+
+	// The subscribers are only called on change
+	if _, err := Reload(); err != nil {
+		t.Fatalf("reload again: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("reload subscriber: got %d calls, want 1", calls)
+	}
+}
+
+func TestReloadInvalid(t *testing.T) {
+	initReload(t)
+	started := Current()
+
+	t.Setenv("CFG_SERVER_DEBUG", "false")
+	t.Setenv("CFG_DATABASE_POOL_IDLECONNECTIONS", "50")
+	if _, err := Reload(); err == nil {
+		t.Fatalf("reload an invalid configuration: got no error")
+	}
+	if Current() != started {
+		t.Errorf("an invalid configuration is applied")
+	}
+}
+
+func TestReloadUsage(t *testing.T) {
+	initReload(t)
+
+	// The usage reporting can be paused, but not started if disabled at startup
+	t.Setenv("CFG_SERVER_USAGE_ENABLED", "false")
+	if _, err := Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if Current().Server.Usage.Enabled {
+		t.Errorf("the usage reporting is not paused")
+	}
+
+	Config.Server.Usage.Enabled = false
+	next := *Current()
+	loaded := &AppConfig{}
+	loaded.Server.Usage.Enabled = true
+	applyReloadable(&next, loaded)
+	if next.Server.Usage.Enabled {
+		t.Errorf("the usage reporting disabled at startup is started")
+	}
+}
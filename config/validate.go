@@ -0,0 +1,196 @@
+package config
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// redacted replaces the values of the secret fields in the printed config
+const redacted = "[REDACTED]"
+
+//go:embed schema.json
+var schemaJSON string
+
+// quotedNames matches the property names of a missing properties message
+var quotedNames = regexp.MustCompile(`'([^']*)'`)
+
+// ValidationError lists all the invalid fields of a configuration
+type ValidationError struct {
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration:\n  %s", strings.Join(e.Violations, "\n  "))
+}
+
+// ValidateConfig validates the configuration against the configuration schema
+// and checks the fields which depend on each other or on the file system. All
+// the violations are reported in a ValidationError.
+func ValidateConfig(cfg *AppConfig) error {
+
+	schema, err := jsonschema.CompileString("config:///schema.json", schemaJSON)
+	if err != nil {
+		return err
+	}
+
+	violations := []string{}
+	if err := schema.Validate(configValue(reflect.ValueOf(cfg).Elem(), false)); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if !errors.As(err, &validationErr) {
+			return err
+		}
+		violations = append(violations, schemaViolations(validationErr)...)
+	}
+
+	checkCertKey := func(field string, cert string, key string) {
+		switch {
+		case cert == "" && key == "":
+			return
+		case cert == "":
+			violations = append(violations, fmt.Sprintf("%s.cert: missing certificate of the key %s", field, key))
+		case key == "":
+			violations = append(violations, fmt.Sprintf("%s.key: missing key of the certificate %s", field, cert))
+		}
+		for name, path := range map[string]string{"cert": cert, "key": key} {
+			if path == "" {
+				continue
+			}
+			if _, err := os.Stat(path); err != nil {
+				violations = append(violations, fmt.Sprintf("%s.%s: %s", field, name, err.Error()))
+			}
+		}
+	}
+	checkCertKey("server.https", cfg.Server.HTTPS.Cert, cfg.Server.HTTPS.Key)
+
+	checkTLS := func(field string, tls TLSConfig) {
+		if !tls.Enabled {
+			return
+		}
+		checkCertKey(field, tls.Cert, tls.Key)
+		if tls.CA != "" {
+			if _, err := os.Stat(tls.CA); err != nil {
+				violations = append(violations, fmt.Sprintf("%s.ca: %s", field, err.Error()))
+			}
+		}
+	}
+	checkTLS("server.usage.tls", cfg.Server.Usage.TLS)
+	checkTLS("database.tls", cfg.Database.TLS)
+	checkTLS("pipelinebackend.tls", cfg.PipelineBackend.TLS)
+	checkTLS("mgmtbackend.tls", cfg.MgmtBackend.TLS)
+	checkTLS("controller.tls", cfg.Controller.TLS)
+
+	if cfg.Server.PrivatePort == cfg.Server.PublicPort {
+		violations = append(violations, fmt.Sprintf("server.publicport: same port %d as server.privateport", cfg.Server.PublicPort))
+	}
+	if pool := cfg.Database.Pool; pool.IdleConnections > pool.MaxConnections {
+		violations = append(violations, fmt.Sprintf("database.pool.idleconnections: %d idle connections exceed the %d max connections", pool.IdleConnections, pool.MaxConnections))
+	}
+	if metering := cfg.Server.Metering; metering.Enabled {
+		// The unset window and periods have defaults
+		if metering.Window < 0 {
+			violations = append(violations, fmt.Sprintf("server.metering.window: %v is negative", metering.Window))
+		}
+		plans := map[string]bool{}
+		for idx, plan := range metering.Plans {
+			plans[plan.Name] = true
+			if plan.Period < 0 {
+				violations = append(violations, fmt.Sprintf("server.metering.plans.%d.period: %v is negative", idx, plan.Period))
+			} else if plan.Period > 0 && metering.Window > 0 && plan.Period%metering.Window != 0 {
+				violations = append(violations, fmt.Sprintf("server.metering.plans.%d.period: %v is not a multiple of the window %v", idx, plan.Period, metering.Window))
+			}
+		}
+		if metering.DefaultPlan != "" && !plans[metering.DefaultPlan] {
+			violations = append(violations, fmt.Sprintf("server.metering.defaultplan: unknown plan %q", metering.DefaultPlan))
+		}
+		for idx, owner := range metering.Owners {
+			if owner.Plan != "" && !plans[owner.Plan] {
+				violations = append(violations, fmt.Sprintf("server.metering.owners.%d.plan: unknown plan %q", idx, owner.Plan))
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		sort.Strings(violations)
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// schemaViolations returns the violations of the innermost schema keywords
+func schemaViolations(e *jsonschema.ValidationError) []string {
+	if len(e.Causes) == 0 {
+		// A violation per missing property, so that each names its field
+		if strings.HasSuffix(e.KeywordLocation, "/required") {
+			violations := []string{}
+			for _, name := range quotedNames.FindAllStringSubmatch(e.Message, -1) {
+				violations = append(violations, fmt.Sprintf("%s: missing required field", fieldPath(e.InstanceLocation+"/"+name[1])))
+			}
+			if len(violations) > 0 {
+				return violations
+			}
+		}
+		return []string{fmt.Sprintf("%s: %s", fieldPath(e.InstanceLocation), e.Message)}
+	}
+	violations := []string{}
+	for _, cause := range e.Causes {
+		violations = append(violations, schemaViolations(cause)...)
+	}
+	return violations
+}
+
+// fieldPath turns a JSON pointer into the dot-separated path of the field,
+// e.g., /database/pool into database.pool
+func fieldPath(pointer string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(pointer, "/"), "/", ".")
+}
+
+// configValue converts a configuration value to its JSON value keyed as in
+// the configuration file, the durations are strings. The secret fields are
+// redacted if redact is set.
+func configValue(v reflect.Value, redact bool) interface{} {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		m := map[string]interface{}{}
+		for idx := 0; idx < v.NumField(); idx++ {
+			field := v.Type().Field(idx)
+			key := field.Tag.Get("koanf")
+			if key == "" {
+				key = strings.ToLower(field.Name)
+			}
+			if redact && field.Tag.Get("secret") == "true" && !v.Field(idx).IsZero() {
+				m[key] = redacted
+				continue
+			}
+			m[key] = configValue(v.Field(idx), redact)
+		}
+		return m
+	case reflect.Slice:
+		s := make([]interface{}, v.Len())
+		for idx := range s {
+			s[idx] = configValue(v.Index(idx), redact)
+		}
+		return s
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	default:
+		return v.String()
+	}
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// loadRepositoryConfig loads the configuration of the repository, which is valid
+func loadRepositoryConfig(t *testing.T) *AppConfig {
+	t.Helper()
+	cfg, err := load("config.yaml")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("validate the repository configuration: %v", err)
+	}
+	return cfg
+}
+
+func validationViolations(t *testing.T, cfg *AppConfig) []string {
+	t.Helper()
+	err := ValidateConfig(cfg)
+	if err == nil {
+		return nil
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("validate: got %v, want a ValidationError", err)
+	}
+	return validationErr.Violations
+}
+
+func TestValidateConfigSchema(t *testing.T) {
+	cfg := loadRepositoryConfig(t)
+	cfg.Server.PublicPort = 0
+	cfg.Server.Edition = "unknown"
+	cfg.Database.Host = ""
+	cfg.Server.HTTPS.Cert = "/missing/server.crt"
+	cfg.Database.Pool.IdleConnections = cfg.Database.Pool.MaxConnections + 1
+
+	violations := validationViolations(t, cfg)
+
+	// All the violations are reported, sorted and prefixed by their field
+	for _, prefix := range []string{
+		"database.host: ",
+		"database.pool.idleconnections: ",
+		"server.edition: does not match pattern",
+		"server.https.cert: ",
+		"server.https.key: missing key of the certificate /missing/server.crt",
+		"server.publicport: must be >= 1 but found 0",
+	} {
+		found := false
+		for _, v := range violations {
+			found = found || strings.HasPrefix(v, prefix)
+		}
+		if !found {
+			t.Errorf("violations: no %q in %q", prefix, violations)
+		}
+	}
+	sorted := append([]string{}, violations...)
+	if sort.Strings(sorted); !reflect.DeepEqual(sorted, violations) {
+		t.Errorf("violations are not sorted: %q", violations)
+	}
+
+	err := ValidateConfig(cfg)
+	if !strings.HasPrefix(err.Error(), "invalid configuration:\n  database.host: ") {
+		t.Errorf("validation error: got %q", err.Error())
+	}
+}
+
+func TestValidateConfigPorts(t *testing.T) {
+	cfg := loadRepositoryConfig(t)
+	cfg.Server.PublicPort = cfg.Server.PrivatePort
+
+	want := []string{fmt.Sprintf("server.publicport: same port %d as server.privateport", cfg.Server.PublicPort)}
+	if got := validationViolations(t, cfg); !reflect.DeepEqual(got, want) {
+		t.Fatalf("violations: got %q, want %q", got, want)
+	}
+}
+
+func TestValidateConfigMetering(t *testing.T) {
+	cfg := loadRepositoryConfig(t)
+	cfg.Server.Metering.Enabled = true
+	cfg.Server.Metering.Window = -1
+	cfg.Server.Metering.DefaultPlan = "missing"
+	cfg.Server.Metering.Plans = []QuotaPlanConfig{{Name: "free", Period: -1}}
+	cfg.Server.Metering.Owners = []OwnerQuotaConfig{{ID: "owner", Plan: "unknown"}}
+
+	want := []string{
+		`server.metering.defaultplan: unknown plan "missing"`,
+		`server.metering.owners.0.plan: unknown plan "unknown"`,
+		"server.metering.plans.0.period: -1ns is negative",
+		"server.metering.window: -1ns is negative",
+	}
+	if got := validationViolations(t, cfg); !reflect.DeepEqual(got, want) {
+		t.Fatalf("violations:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestFieldPath(t *testing.T) {
+	for pointer, want := range map[string]string{
+		"":                    "",
+		"/server/publicport":  "server.publicport",
+		"/database/pool/idle": "database.pool.idle",
+	} {
+		if got := fieldPath(pointer); got != want {
+			t.Errorf("field path of %q: got %q, want %q", pointer, got, want)
+		}
+	}
+}
@@ -12,7 +12,7 @@ import * as constant from "./const.js"
 import * as helper from "./helper.js"
 
 const client = new grpc.Client();
-client.load(['proto/vdp/connector/v1alpha'], 'connector_public_service.proto');
+client.load(['proto/vdp/connector/v1alpha'], 'connector_public_service.proto', 'connector_clone_service.proto', 'connector_batch_service.proto');
 
 export function CheckCreate() {
 
@@ -650,6 +650,133 @@ export function CheckRename() {
     });
 }
 
+export function CheckClone() {
+
+    group("Connector API: Clone destination connectors", () => {
+
+        client.connect(constant.connectorGRPCPublicHost, {
+            plaintext: true
+        });
+
+        var csvDstConnector = {
+            "id": randomString(10),
+            "connector_definition_name": constant.csvDstDefRscName,
+            "description": randomString(50),
+            "configuration": constant.csvDstConfig,
+            "visibility": "VISIBILITY_PUBLIC"
+        }
+
+        var resCSVDst = client.invoke('vdp.connector.v1alpha.ConnectorPublicService/CreateConnector', {
+            connector: csvDstConnector
+        })
+
+        var cloneID = `clone-of-${resCSVDst.message.connector.id}`
+        var privateCloneID = `private-clone-of-${resCSVDst.message.connector.id}`
+
+        check(client.invoke('vdp.connector.v1alpha.ConnectorCloneService/CloneConnector', {
+            name: `connectors/${resCSVDst.message.connector.id}`,
+            new_connector_id: cloneID
+        }), {
+            [`vdp.connector.v1alpha.ConnectorCloneService/CloneConnector ${resCSVDst.message.connector.id} response StatusOK`]: (r) => r.status === grpc.StatusOK,
+            [`vdp.connector.v1alpha.ConnectorCloneService/CloneConnector ${resCSVDst.message.connector.id} response id is ${cloneID}`]: (r) => r.message.connector.id === cloneID,
+            [`vdp.connector.v1alpha.ConnectorCloneService/CloneConnector ${resCSVDst.message.connector.id} response visibility is the source visibility`]: (r) => r.message.connector.visibility === "VISIBILITY_PUBLIC",
+            [`vdp.connector.v1alpha.ConnectorCloneService/CloneConnector ${resCSVDst.message.connector.id} response state STATE_DISCONNECTED`]: (r) => r.message.connector.state === "STATE_DISCONNECTED",
+        });
+
+        check(client.invoke('vdp.connector.v1alpha.ConnectorCloneService/CloneConnector', {
+            name: `connectors/${resCSVDst.message.connector.id}`,
+            new_connector_id: privateCloneID,
+            visibility: "VISIBILITY_PRIVATE"
+        }), {
+            [`vdp.connector.v1alpha.ConnectorCloneService/CloneConnector ${resCSVDst.message.connector.id} with visibility response StatusOK`]: (r) => r.status === grpc.StatusOK,
+            [`vdp.connector.v1alpha.ConnectorCloneService/CloneConnector ${resCSVDst.message.connector.id} with visibility response visibility VISIBILITY_PRIVATE`]: (r) => r.message.connector.visibility === "VISIBILITY_PRIVATE",
+        });
+
+        check(client.invoke('vdp.connector.v1alpha.ConnectorCloneService/CloneConnector', {
+            name: `connectors/${resCSVDst.message.connector.id}`,
+            new_connector_id: cloneID
+        }), {
+            [`vdp.connector.v1alpha.ConnectorCloneService/CloneConnector ${resCSVDst.message.connector.id} duplicate id response StatusAlreadyExists`]: (r) => r.status === grpc.StatusAlreadyExists,
+        });
+
+        for (const id of [cloneID, privateCloneID, resCSVDst.message.connector.id]) {
+            check(client.invoke(`vdp.connector.v1alpha.ConnectorPublicService/DeleteConnector`, {
+                name: `connectors/${id}`
+            }), {
+                [`vdp.connector.v1alpha.ConnectorPublicService/DeleteConnector ${id} response StatusOK`]: (r) => r.status === grpc.StatusOK,
+            });
+        }
+
+        client.close();
+    });
+}
+
+export function CheckBatch() {
+
+    group("Connector API: Batch create, connect, disconnect and delete destination connectors", () => {
+
+        client.connect(constant.connectorGRPCPublicHost, {
+            plaintext: true
+        });
+
+        var ids = [randomString(10), randomString(10)]
+        var connectors = ids.map((id) => ({
+            "id": id,
+            "connector_definition_name": constant.csvDstDefRscName,
+            "description": randomString(50),
+            "configuration": constant.csvDstConfig
+        }))
+
+        check(client.invoke('vdp.connector.v1alpha.ConnectorBatchService/BatchCreateConnectors', {
+            connectors: connectors,
+            all_or_nothing: true
+        }), {
+            [`vdp.connector.v1alpha.ConnectorBatchService/BatchCreateConnectors response StatusOK`]: (r) => r.status === grpc.StatusOK,
+            [`vdp.connector.v1alpha.ConnectorBatchService/BatchCreateConnectors response results in input order`]: (r) => r.message.results.every((result, idx) => result.connector.id === ids[idx]),
+            [`vdp.connector.v1alpha.ConnectorBatchService/BatchCreateConnectors response connectors STATE_DISCONNECTED`]: (r) => r.message.results.every((result) => result.connector.state === "STATE_DISCONNECTED"),
+        });
+
+        // All-or-nothing: an existing id fails the whole batch and nothing is created
+        var newID = randomString(10)
+        check(client.invoke('vdp.connector.v1alpha.ConnectorBatchService/BatchCreateConnectors', {
+            connectors: [Object.assign({}, connectors[0], { "id": newID }), connectors[1]],
+            all_or_nothing: true
+        }), {
+            [`vdp.connector.v1alpha.ConnectorBatchService/BatchCreateConnectors with an existing id response StatusAlreadyExists (all-or-nothing)`]: (r) => r.status === grpc.StatusAlreadyExists,
+        });
+        check(client.invoke('vdp.connector.v1alpha.ConnectorPublicService/GetConnector', {
+            name: `connectors/${newID}`
+        }), {
+            [`vdp.connector.v1alpha.ConnectorPublicService/GetConnector ${newID} response StatusNotFound after a failed all-or-nothing batch`]: (r) => r.status === grpc.StatusNotFound,
+        });
+
+        check(client.invoke('vdp.connector.v1alpha.ConnectorBatchService/BatchDisconnectConnectors', {
+            names: ids.map((id) => `connectors/${id}`).concat(["connectors/non-existing"]),
+            all_or_nothing: false
+        }), {
+            [`vdp.connector.v1alpha.ConnectorBatchService/BatchDisconnectConnectors response StatusOK (best-effort)`]: (r) => r.status === grpc.StatusOK,
+            [`vdp.connector.v1alpha.ConnectorBatchService/BatchDisconnectConnectors response non-existing connector not found`]: (r) => r.message.results[ids.length].status.code === 5,
+        });
+
+        check(client.invoke('vdp.connector.v1alpha.ConnectorBatchService/BatchDeleteConnectors', {
+            names: ids.map((id) => `connectors/${id}`),
+            all_or_nothing: true
+        }), {
+            [`vdp.connector.v1alpha.ConnectorBatchService/BatchDeleteConnectors response StatusOK`]: (r) => r.status === grpc.StatusOK,
+        });
+
+        for (const id of ids) {
+            check(client.invoke('vdp.connector.v1alpha.ConnectorPublicService/GetConnector', {
+                name: `connectors/${id}`
+            }), {
+                [`vdp.connector.v1alpha.ConnectorPublicService/GetConnector ${id} response StatusNotFound after batch delete`]: (r) => r.status === grpc.StatusNotFound,
+            });
+        }
+
+        client.close();
+    });
+}
+
 export function CheckExecute() {
 
     group("Connector API: Write destination connectors", () => {
@@ -122,6 +122,8 @@ export default function (data) {
   destinationConnectorPublic.CheckLookUp()
   destinationConnectorPublic.CheckState()
   destinationConnectorPublic.CheckRename()
+  destinationConnectorPublic.CheckClone()
+  destinationConnectorPublic.CheckBatch()
   destinationConnectorPublic.CheckExecute()
   destinationConnectorPublic.CheckTest()
 
@@ -461,6 +461,226 @@ export function CheckRename() {
     });
 }
 
+export function CheckClone() {
+
+    group("Connector API: Clone destination connectors", () => {
+
+        var csvDstConnector = {
+            "id": randomString(10),
+            "connector_definition_name": constant.csvDstDefRscName,
+            "description": randomString(50),
+            "configuration": constant.csvDstConfig
+
+        }
+
+        var resCSVDst = http.request("POST", `${connectorPublicHost}/v1alpha/connectors`,
+            JSON.stringify(csvDstConnector), constant.params)
+
+        var cloneID = `clone-of-${resCSVDst.json().connector.id}`
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/${resCSVDst.json().connector.id}/clone`,
+            JSON.stringify({
+                "new_connector_id": cloneID
+            }), constant.params), {
+            [`POST /v1alpha/connectors/${resCSVDst.json().connector.id}/clone response status 201`]: (r) => r.status === 201,
+            [`POST /v1alpha/connectors/${resCSVDst.json().connector.id}/clone response id is ${cloneID}`]: (r) => r.json().connector.id === cloneID,
+            [`POST /v1alpha/connectors/${resCSVDst.json().connector.id}/clone response uid is a new uid`]: (r) => r.json().connector.uid !== resCSVDst.json().connector.uid,
+            [`POST /v1alpha/connectors/${resCSVDst.json().connector.id}/clone response description`]: (r) => r.json().connector.description === csvDstConnector.description,
+            [`POST /v1alpha/connectors/${resCSVDst.json().connector.id}/clone response state STATE_DISCONNECTED`]: (r) => r.json().connector.state === "STATE_DISCONNECTED",
+            [`POST /v1alpha/connectors/${resCSVDst.json().connector.id}/clone response visibility is the source visibility`]: (r) => r.json().connector.visibility === resCSVDst.json().connector.visibility,
+        });
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/${resCSVDst.json().connector.id}/clone`,
+            JSON.stringify({
+                "new_connector_id": cloneID
+            }), constant.params), {
+            [`POST /v1alpha/connectors/${resCSVDst.json().connector.id}/clone response duplicate id status 409`]: (r) => r.status === 409,
+        });
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/${resCSVDst.json().connector.id}/clone`,
+            JSON.stringify({}), constant.params), {
+            [`POST /v1alpha/connectors/${resCSVDst.json().connector.id}/clone response status 400 without new_connector_id`]: (r) => r.status === 400,
+        });
+
+        check(http.request("GET", `${connectorPublicHost}/v1alpha/connectors/${resCSVDst.json().connector.id}`), {
+            [`GET /v1alpha/connectors/${resCSVDst.json().connector.id} response source connector is untouched`]: (r) => r.json().connector.uid === resCSVDst.json().connector.uid,
+        });
+
+        check(http.request("DELETE", `${connectorPublicHost}/v1alpha/connectors/${cloneID}`), {
+            [`DELETE /v1alpha/connectors/${cloneID} response status 204`]: (r) => r.status === 204,
+        });
+        check(http.request("DELETE", `${connectorPublicHost}/v1alpha/connectors/${resCSVDst.json().connector.id}`), {
+            [`DELETE /v1alpha/connectors/${resCSVDst.json().connector.id} response status 204`]: (r) => r.status === 204,
+        });
+    });
+}
+
+export function CheckBatch() {
+
+    group("Connector API: Batch create, connect, disconnect and delete destination connectors", () => {
+
+        var ids = [randomString(10), randomString(10), randomString(10)]
+        var connectors = ids.map((id) => ({
+            "id": id,
+            "connector_definition_name": constant.csvDstDefRscName,
+            "description": randomString(50),
+            "configuration": constant.csvDstConfig
+        }))
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/batchCreate`,
+            JSON.stringify({
+                "connectors": connectors,
+                "all_or_nothing": true
+            }), constant.params), {
+            [`POST /v1alpha/connectors/batchCreate response status 200`]: (r) => r.status === 200,
+            [`POST /v1alpha/connectors/batchCreate response results in input order`]: (r) => r.json().results.every((result, idx) => result.connector.id === ids[idx]),
+            [`POST /v1alpha/connectors/batchCreate response connectors STATE_DISCONNECTED`]: (r) => r.json().results.every((result) => result.connector.state === "STATE_DISCONNECTED"),
+        });
+
+        // All-or-nothing: an existing id fails the whole batch and nothing is created
+        var newID = randomString(10)
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/batchCreate`,
+            JSON.stringify({
+                "connectors": [Object.assign({}, connectors[0], { "id": newID }), connectors[1]],
+                "all_or_nothing": true
+            }), constant.params), {
+            [`POST /v1alpha/connectors/batchCreate response status 409 with an existing id (all-or-nothing)`]: (r) => r.status === 409,
+        });
+        check(http.request("GET", `${connectorPublicHost}/v1alpha/connectors/${newID}`), {
+            [`GET /v1alpha/connectors/${newID} response status 404 after a failed all-or-nothing batch`]: (r) => r.status === 404,
+        });
+
+        // Best-effort: per-item status
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/batchCreate`,
+            JSON.stringify({
+                "connectors": [Object.assign({}, connectors[0], { "id": newID }), connectors[1]],
+                "all_or_nothing": false
+            }), constant.params), {
+            [`POST /v1alpha/connectors/batchCreate response status 200 (best-effort)`]: (r) => r.status === 200,
+            [`POST /v1alpha/connectors/batchCreate response first item created (best-effort)`]: (r) => r.json().results[0].connector.id === newID,
+            [`POST /v1alpha/connectors/batchCreate response second item already exists (best-effort)`]: (r) => r.json().results[1].status.code === 6,
+        });
+        ids.push(newID)
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/batchCreate`,
+            JSON.stringify({
+                "connectors": []
+            }), constant.params), {
+            [`POST /v1alpha/connectors/batchCreate response status 400 with an empty batch`]: (r) => r.status === 400,
+        });
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/batchConnect`,
+            JSON.stringify({
+                "names": ids.map((id) => `connectors/${id}`),
+                "all_or_nothing": true
+            }), constant.params), {
+            [`POST /v1alpha/connectors/batchConnect response status 200`]: (r) => r.status === 200,
+            [`POST /v1alpha/connectors/batchConnect response connectors STATE_CONNECTED`]: (r) => r.json().results.every((result) => result.connector.state === "STATE_CONNECTED"),
+        });
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/batchDisconnect`,
+            JSON.stringify({
+                "names": ids.map((id) => `connectors/${id}`).concat(["connectors/non-existing"]),
+                "all_or_nothing": false
+            }), constant.params), {
+            [`POST /v1alpha/connectors/batchDisconnect response status 200 (best-effort)`]: (r) => r.status === 200,
+            [`POST /v1alpha/connectors/batchDisconnect response connectors STATE_DISCONNECTED`]: (r) => r.json().results.slice(0, ids.length).every((result) => result.connector.state === "STATE_DISCONNECTED"),
+            [`POST /v1alpha/connectors/batchDisconnect response non-existing connector not found`]: (r) => r.json().results[ids.length].status.code === 5,
+        });
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/batchDelete`,
+            JSON.stringify({
+                "names": ids.map((id) => `connectors/${id}`),
+                "all_or_nothing": true
+            }), constant.params), {
+            [`POST /v1alpha/connectors/batchDelete response status 200`]: (r) => r.status === 200,
+            [`POST /v1alpha/connectors/batchDelete response all items succeeded`]: (r) => r.json().results.every((result) => result.status.code === 0),
+        });
+
+        for (const id of ids) {
+            check(http.request("GET", `${connectorPublicHost}/v1alpha/connectors/${id}`), {
+                [`GET /v1alpha/connectors/${id} response status 404 after batch delete`]: (r) => r.status === 404,
+            });
+        }
+    });
+}
+
+export function CheckExportImport() {
+
+    group("Connector API: Export and import destination connectors", () => {
+
+        var csvDstConnector = {
+            "id": randomString(10),
+            "connector_definition_name": constant.csvDstDefRscName,
+            "description": randomString(50),
+            "configuration": constant.csvDstConfig
+        }
+
+        var resCSVDst = http.request("POST", `${connectorPublicHost}/v1alpha/connectors`,
+            JSON.stringify(csvDstConnector), constant.params)
+
+        var resExport = http.request("POST", `${connectorPublicHost}/v1alpha/connectors/export?format=json&credentials=placeholder`,
+            null, constant.params)
+
+        check(resExport, {
+            [`POST /v1alpha/connectors/export response status 200`]: (r) => r.status === 200,
+            [`POST /v1alpha/connectors/export response version`]: (r) => r.json().version === "v1alpha",
+            [`POST /v1alpha/connectors/export response contains the connector`]: (r) => r.json().connectors.some((c) => c.id === csvDstConnector.id && c.description === csvDstConnector.description),
+        });
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/export?format=xml`,
+            null, constant.params), {
+            [`POST /v1alpha/connectors/export response status 400 with an unsupported format`]: (r) => r.status === 400,
+        });
+
+        var bundle = {
+            "version": "v1alpha",
+            "connectors": resExport.json().connectors.filter((c) => c.id === csvDstConnector.id)
+        }
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/import?dry_run=true`,
+            JSON.stringify(bundle), constant.params), {
+            [`POST /v1alpha/connectors/import response status 200 (conflict)`]: (r) => r.status === 200,
+            [`POST /v1alpha/connectors/import response not applied (conflict)`]: (r) => r.json().applied === false,
+            [`POST /v1alpha/connectors/import response conflict reported`]: (r) => r.json().results[0].conflict === true && r.json().results[0].status.code === 6,
+        });
+
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/import?conflict=skip`,
+            JSON.stringify(bundle), constant.params), {
+            [`POST /v1alpha/connectors/import?conflict=skip response action skip`]: (r) => r.json().applied === true && r.json().results[0].action === "skip",
+        });
+
+        var resRename = http.request("POST", `${connectorPublicHost}/v1alpha/connectors/import?conflict=rename`,
+            JSON.stringify(bundle), constant.params)
+        check(resRename, {
+            [`POST /v1alpha/connectors/import?conflict=rename response action rename`]: (r) => r.json().applied === true && r.json().results[0].action === "rename",
+            [`POST /v1alpha/connectors/import?conflict=rename response new connector id`]: (r) => r.json().results[0].connector_id === `${csvDstConnector.id}-1`,
+            [`POST /v1alpha/connectors/import?conflict=rename response connector description`]: (r) => r.json().results[0].connector.description === csvDstConnector.description,
+        });
+
+        bundle.connectors[0].description = randomString(50)
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/import?conflict=overwrite`,
+            JSON.stringify(bundle), constant.params), {
+            [`POST /v1alpha/connectors/import?conflict=overwrite response action overwrite`]: (r) => r.json().applied === true && r.json().results[0].action === "overwrite",
+            [`POST /v1alpha/connectors/import?conflict=overwrite response connector description`]: (r) => r.json().results[0].connector.description === bundle.connectors[0].description,
+        });
+
+        bundle.connectors[0].connector_definition = "non-existing"
+        bundle.connectors[0].id = randomString(10)
+        check(http.request("POST", `${connectorPublicHost}/v1alpha/connectors/import`,
+            JSON.stringify(bundle), constant.params), {
+            [`POST /v1alpha/connectors/import response invalid definition reported`]: (r) => r.json().applied === false && r.json().results[0].status.code === 3,
+        });
+
+        check(http.request("DELETE", `${connectorPublicHost}/v1alpha/connectors/${csvDstConnector.id}-1`), {
+            [`DELETE /v1alpha/connectors/${csvDstConnector.id}-1 response status 204`]: (r) => r.status === 204,
+        });
+        check(http.request("DELETE", `${connectorPublicHost}/v1alpha/connectors/${resCSVDst.json().connector.id}`), {
+            [`DELETE /v1alpha/connectors/${resCSVDst.json().connector.id} response status 204`]: (r) => r.status === 204,
+        });
+    });
+}
+
 export function CheckExecute() {
 
     group("Connector API: Write destination connectors", () => {
@@ -106,6 +106,9 @@ export default function (data) {
   destinationConnectorPublic.CheckLookUp()
   destinationConnectorPublic.CheckState()
   destinationConnectorPublic.CheckRename()
+  destinationConnectorPublic.CheckClone()
+  destinationConnectorPublic.CheckBatch()
+  destinationConnectorPublic.CheckExportImport()
   destinationConnectorPublic.CheckExecute()
   destinationConnectorPublic.CheckTest()
 
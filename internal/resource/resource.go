@@ -12,10 +12,15 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"github.com/instill-ai/connector-backend/pkg/constant"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/utils"
 
 	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
 )
 
+// mgmtTimeout bounds the mgmt-backend calls of the callers without deadline
+const mgmtTimeout = 5 * time.Second
+
 // ExtractFromMetadata extracts context metadata given a key
 func ExtractFromMetadata(ctx context.Context, key string) ([]string, bool) {
 	data, ok := metadata.FromIncomingContext(ctx)
@@ -75,13 +80,14 @@ func GetOwner(ctx context.Context, client mgmtPB.MgmtPrivateServiceClient) (*mgm
 		}
 		ownerPermalink := "users/" + headerOwnerUId
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := utils.WithDefaultTimeout(ctx, mgmtTimeout)
 		defer cancel()
 		resp, err := client.LookUpUserAdmin(ctx, &mgmtPB.LookUpUserAdminRequest{Permalink: ownerPermalink})
 		if err != nil {
 			return nil, status.Errorf(codes.NotFound, "Not found")
 		}
 
+		logger.SetOwner(ctx, ownerPermalink)
 		return resp.User, nil
 	}
 
@@ -92,11 +98,31 @@ func GetOwner(ctx context.Context, client mgmtPB.MgmtPrivateServiceClient) (*mgm
 	}
 
 	// Get the permalink from management backend from resource name
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := utils.WithDefaultTimeout(ctx, mgmtTimeout)
 	defer cancel()
 	resp, err := client.GetUserAdmin(ctx, &mgmtPB.GetUserAdminRequest{Name: "users/" + headerOwnerId})
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "Not found")
 	}
+	logger.SetOwner(ctx, "users/"+resp.User.GetUid())
+	return resp.User, nil
+}
+
+// GetOwnerByName returns the owner given its resource name, e.g., users/{id}
+func GetOwnerByName(ctx context.Context, client mgmtPB.MgmtPrivateServiceClient, name string) (*mgmtPB.User, error) {
+	if !strings.HasPrefix(name, "users/") {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid owner name %s", name)
+	}
+
+	ctx, cancel := utils.WithDefaultTimeout(ctx, mgmtTimeout)
+	defer cancel()
+	resp, err := client.GetUserAdmin(ctx, &mgmtPB.GetUserAdminRequest{Name: name})
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Errorf(codes.NotFound, "Owner %s not found", name)
+	}
+	if err != nil {
+		// The management backend being unavailable is not a missing owner
+		return nil, err
+	}
 	return resp.User, nil
 }
@@ -0,0 +1,44 @@
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+)
+
+// fakeMgmtClient fails the user lookups with err
+type fakeMgmtClient struct {
+	mgmtPB.MgmtPrivateServiceClient
+	err error
+}
+
+func (c *fakeMgmtClient) GetUserAdmin(ctx context.Context, in *mgmtPB.GetUserAdminRequest, opts ...grpc.CallOption) (*mgmtPB.GetUserAdminResponse, error) {
+	return nil, c.err
+}
+
+func TestGetOwnerByNameErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"not found", status.Error(codes.NotFound, "user not found"), codes.NotFound},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), codes.Unavailable},
+		{"deadline exceeded", status.Error(codes.DeadlineExceeded, "context deadline exceeded"), codes.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		_, err := GetOwnerByName(context.Background(), &fakeMgmtClient{err: tt.err}, "users/admin")
+		if status.Code(err) != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	if _, err := GetOwnerByName(context.Background(), &fakeMgmtClient{}, "orgs/admin"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid name: got %v, want InvalidArgument", err)
+	}
+}
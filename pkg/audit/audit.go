@@ -0,0 +1,127 @@
+// Package audit records the connector changes and executions as audit
+// events, which are written to the configured sinks: the append-only
+// audit_event table, from which they are listed, and/or the log.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+)
+
+// Sink names of the configuration
+const (
+	SinkDatabase = "database"
+	SinkLog      = "log"
+)
+
+// Sink is a destination of the audit events
+type Sink interface {
+	Write(ctx context.Context, event *datamodel.AuditEvent) error
+}
+
+// NewSink returns the sink writing to the configured sinks, nil if the audit
+// is disabled
+func NewSink(r repository.Repository, logger *zap.Logger) (Sink, error) {
+
+	auditConfig := config.Config.Server.Audit
+	if !auditConfig.Enabled {
+		return nil, nil
+	}
+
+	var sinks multiSink
+	for _, name := range auditConfig.Sinks {
+		switch name {
+		case SinkDatabase:
+			sinks = append(sinks, NewDatabaseSink(r))
+		case SinkLog:
+			sinks = append(sinks, NewLogSink(logger))
+		default:
+			return nil, fmt.Errorf("unknown audit sink %q, expected %s or %s", name, SinkDatabase, SinkLog)
+		}
+	}
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	return sinks, nil
+}
+
+// IsStored reports whether the audit events are written to the database and
+// can be listed
+func IsStored() bool {
+	auditConfig := config.Config.Server.Audit
+	if !auditConfig.Enabled {
+		return false
+	}
+	for _, name := range auditConfig.Sinks {
+		if name == SinkDatabase {
+			return true
+		}
+	}
+	return false
+}
+
+type databaseSink struct {
+	repository repository.Repository
+}
+
+// NewDatabaseSink returns the sink appending the events to the audit_event table
+func NewDatabaseSink(r repository.Repository) Sink {
+	return &databaseSink{repository: r}
+}
+
+func (s *databaseSink) Write(ctx context.Context, event *datamodel.AuditEvent) error {
+	return s.repository.CreateAuditEvent(ctx, event)
+}
+
+type logSink struct {
+	logger *zap.Logger
+}
+
+// NewLogSink returns the sink logging the events as JSON, apart from the
+// other logs by the audit logger name
+func NewLogSink(logger *zap.Logger) Sink {
+	return &logSink{logger: logger.Named("audit")}
+}
+
+func (s *logSink) Write(ctx context.Context, event *datamodel.AuditEvent) error {
+	fields := []zap.Field{
+		zap.String("uid", event.UID.String()),
+		zap.Time("createTime", event.CreateTime),
+		zap.String("eventName", event.EventName),
+		zap.String("actor", event.Actor),
+		zap.String("owner", event.Owner),
+		zap.String("connectorID", event.ConnectorID),
+		zap.String("requestID", event.RequestID),
+		zap.String("traceID", event.TraceID),
+		zap.String("outcome", string(event.Outcome)),
+		zap.String("message", event.Message),
+	}
+	if event.ConnectorUID.Valid {
+		fields = append(fields, zap.String("connectorUID", event.ConnectorUID.UUID.String()))
+	}
+	if event.Diff != nil {
+		fields = append(fields, zap.Any("diff", json.RawMessage(event.Diff)))
+	}
+	s.logger.Info("audit event", fields...)
+	return nil
+}
+
+// multiSink writes the events to every sink, it returns the first error
+type multiSink []Sink
+
+func (s multiSink) Write(ctx context.Context, event *datamodel.AuditEvent) error {
+	var firstErr error
+	for _, sink := range s {
+		if err := sink.Write(ctx, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"gorm.io/datatypes"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// Redacted replaces the values of the credential fields in the diffs
+const Redacted = "[REDACTED]"
+
+// Change is the value of a field before and after an operation, nil if the
+// field was absent
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Diff returns the fields which differ between the before and after states
+// of a connector, either of which is nil for a creation or a deletion. The
+// configuration fields are flattened to configuration.<key path>, the values
+// of the keys for which isCredential is true are redacted, so that a changed
+// credential shows up without its value. A nil isCredential redacts every
+// configuration value. Diff returns nil if nothing changed.
+func Diff(before *datamodel.Connector, after *datamodel.Connector, isCredential func(key string) bool) datatypes.JSON {
+
+	beforeFields := connectorFields(before, isCredential)
+	afterFields := connectorFields(after, isCredential)
+
+	changes := map[string]*Change{}
+	for key, b := range beforeFields {
+		a, ok := afterFields[key]
+		if !ok || !reflect.DeepEqual(a, b) {
+			changes[key] = &Change{Before: b, After: a}
+		}
+	}
+	for key, a := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			changes[key] = &Change{After: a}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// connectorFields flattens the audited fields of a connector, nil for none
+func connectorFields(conn *datamodel.Connector, isCredential func(key string) bool) map[string]interface{} {
+
+	fields := map[string]interface{}{}
+	if conn == nil {
+		return fields
+	}
+
+	fields["id"] = conn.ID
+	fields["description"] = conn.Description.String
+	fields["state"] = connectorPB.Connector_State(conn.State).String()
+	fields["visibility"] = connectorPB.Connector_Visibility(conn.Visibility).String()
+	fields["task"] = conn.Task
+	fields["tombstone"] = conn.Tombstone
+
+	if conn.Configuration != nil {
+		configuration := map[string]interface{}{}
+		if err := json.Unmarshal(conn.Configuration, &configuration); err == nil {
+			flattenConfiguration(fields, configuration, "", isCredential)
+		}
+	}
+
+	return fields
+}
+
+func flattenConfiguration(fields map[string]interface{}, configuration map[string]interface{}, prefix string, isCredential func(key string) bool) {
+	for k, v := range configuration {
+		key := prefix + k
+		if isCredential == nil || isCredential(key) {
+			// The raw values are only compared, they are redacted by Diff
+			fields["configuration."+key] = redactedValue{v}
+			continue
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			flattenConfiguration(fields, nested, key+".", isCredential)
+			continue
+		}
+		fields["configuration."+key] = v
+	}
+}
+
+// redactedValue is the value of a credential field, which is marshaled
+// redacted
+type redactedValue struct {
+	value interface{}
+}
+
+// MarshalJSON implements json.Marshaler
+func (redactedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func newTestConnector(description string, configuration string) *datamodel.Connector {
+	return &datamodel.Connector{
+		ID:            "audited",
+		Description:   sql.NullString{String: description, Valid: true},
+		Configuration: []byte(configuration),
+		State:         datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED),
+		Visibility:    datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PRIVATE),
+		Task:          "TASK_UNSPECIFIED",
+	}
+}
+
+func decodeDiff(t *testing.T, diff []byte) map[string]*Change {
+	t.Helper()
+	if diff == nil {
+		return nil
+	}
+	changes := map[string]*Change{}
+	if err := json.Unmarshal(diff, &changes); err != nil {
+		t.Fatalf("decode diff %s: %v", diff, err)
+	}
+	return changes
+}
+
+func isAPIKey(key string) bool {
+	return key == "api_key"
+}
+
+func TestDiff(t *testing.T) {
+	before := newTestConnector("before", `{"api_key": "old secret", "engine": {"host": "localhost", "port": 80}, "removed": true}`)
+	after := newTestConnector("after", `{"api_key": "new secret", "engine": {"host": "localhost", "port": 8080}, "added": "value"}`)
+
+	got := decodeDiff(t, Diff(before, after, isAPIKey))
+	want := map[string]*Change{
+		"description":               {Before: "before", After: "after"},
+		"configuration.api_key":     {Before: Redacted, After: Redacted},
+		"configuration.engine.port": {Before: 80.0, After: 8080.0},
+		"configuration.removed":     {Before: true},
+		"configuration.added":       {After: "value"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("diff:\ngot  %s\nwant %s", Diff(before, after, isAPIKey), mustMarshal(t, want))
+	}
+}
+
+func TestDiffCreateAndDelete(t *testing.T) {
+	conn := newTestConnector("created", `{"api_key": "secret"}`)
+
+	created := decodeDiff(t, Diff(nil, conn, isAPIKey))
+	if c := created["id"]; c == nil || c.Before != nil || c.After != "audited" {
+		t.Fatalf("diff of a creation: got %v for the id", c)
+	}
+	if c := created["configuration.api_key"]; c == nil || c.After != Redacted {
+		t.Fatalf("diff of a creation: got %v for the credential", c)
+	}
+
+	deleted := decodeDiff(t, Diff(conn, nil, isAPIKey))
+	if c := deleted["state"]; c == nil || c.Before != "STATE_DISCONNECTED" || c.After != nil {
+		t.Fatalf("diff of a deletion: got %v for the state", c)
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	// An unchanged credential is not reported, the raw values are compared
+	if diff := Diff(newTestConnector("same", `{"api_key": "secret"}`), newTestConnector("same", `{"api_key": "secret"}`), isAPIKey); diff != nil {
+		t.Fatalf("diff of unchanged connectors: got %s, want nil", diff)
+	}
+	changes := decodeDiff(t, Diff(newTestConnector("same", `{"api_key": "secret"}`), newTestConnector("same", `{"api_key": "other"}`), isAPIKey))
+	if len(changes) != 1 || changes["configuration.api_key"] == nil {
+		t.Fatalf("diff of a changed credential: got %v", changes)
+	}
+}
+
+func TestDiffWithoutCredentialFields(t *testing.T) {
+	// Without the credential fields, every configuration value is redacted
+	before := newTestConnector("same", `{"token": "old secret", "engine": {"password": "old password"}}`)
+	after := newTestConnector("same", `{"token": "new secret", "engine": {"password": "new password"}}`)
+
+	diff := Diff(before, after, nil)
+	got := decodeDiff(t, diff)
+	want := map[string]*Change{
+		"configuration.token":  {Before: Redacted, After: Redacted},
+		"configuration.engine": {Before: Redacted, After: Redacted},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("diff without credential fields:\ngot  %s\nwant %s", diff, mustMarshal(t, want))
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,223 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the version of the connector bundle format
+const Version = "v1alpha"
+
+// Format is the serialization format of a bundle
+type Format string
+
+// Bundle formats
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// CredentialMode defines how credential fields are exported
+type CredentialMode string
+
+// Credential modes
+const (
+	// CredentialOmit leaves credential fields out of the bundle
+	CredentialOmit CredentialMode = "omit"
+	// CredentialPlaceholder replaces credential values by ${ENV} placeholders
+	CredentialPlaceholder CredentialMode = "placeholder"
+)
+
+// ConflictStrategy defines how an imported connector whose ID already exists is handled
+type ConflictStrategy string
+
+// Conflict strategies
+const (
+	// ConflictFail reports the conflict and imports nothing
+	ConflictFail ConflictStrategy = "fail"
+	// ConflictSkip keeps the existing connector
+	ConflictSkip ConflictStrategy = "skip"
+	// ConflictOverwrite replaces the existing connector settings
+	ConflictOverwrite ConflictStrategy = "overwrite"
+	// ConflictRename imports the connector under a new ID
+	ConflictRename ConflictStrategy = "rename"
+)
+
+// Action is the outcome of importing a bundle entry
+type Action string
+
+// Import actions
+const (
+	ActionCreate    Action = "create"
+	ActionSkip      Action = "skip"
+	ActionOverwrite Action = "overwrite"
+	ActionRename    Action = "rename"
+)
+
+// Bundle is a portable set of connectors
+type Bundle struct {
+	Version    string       `json:"version" yaml:"version"`
+	Connectors []*Connector `json:"connectors" yaml:"connectors"`
+}
+
+// Connector is a bundle entry
+type Connector struct {
+	ConnectorDefinition string `json:"connector_definition" yaml:"connector_definition"`
+	ID                  string `json:"id" yaml:"id"`
+	Description         string `json:"description,omitempty" yaml:"description,omitempty"`
+	Visibility          string `json:"visibility,omitempty" yaml:"visibility,omitempty"`
+	// State is the desired state, STATE_CONNECTED or STATE_DISCONNECTED,
+	// enforced by reconciliation and ignored by import
+	State         string                 `json:"state,omitempty" yaml:"state,omitempty"`
+	Configuration map[string]interface{} `json:"configuration" yaml:"configuration"`
+}
+
+var placeholderRegexp = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*)\}$`)
+var envNameRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+var envRefRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ParseFormat returns the bundle format given its name or a file extension, YAML by default
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "", "yaml", "yml":
+		return FormatYAML, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("unsupported bundle format %q", s)
+}
+
+// ParseCredentialMode returns the credential mode given its name, omit by default
+func ParseCredentialMode(s string) (CredentialMode, error) {
+	switch CredentialMode(s) {
+	case "", CredentialOmit:
+		return CredentialOmit, nil
+	case CredentialPlaceholder:
+		return CredentialPlaceholder, nil
+	}
+	return "", fmt.Errorf("unsupported credential mode %q", s)
+}
+
+// ParseConflictStrategy returns the conflict strategy given its name, fail by default
+func ParseConflictStrategy(s string) (ConflictStrategy, error) {
+	switch ConflictStrategy(s) {
+	case "", ConflictFail:
+		return ConflictFail, nil
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+		return ConflictStrategy(s), nil
+	}
+	return "", fmt.Errorf("unsupported conflict strategy %q", s)
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatJSON {
+		return "application/json"
+	}
+	return "application/yaml"
+}
+
+// Marshal serializes a bundle
+func Marshal(b *Bundle, format Format) ([]byte, error) {
+	if format == FormatJSON {
+		return json.MarshalIndent(b, "", "  ")
+	}
+	return yaml.Marshal(b)
+}
+
+// Unmarshal deserializes a bundle and checks its version
+func Unmarshal(data []byte, format Format) (*Bundle, error) {
+	b := &Bundle{}
+	if format == FormatJSON {
+		if err := json.Unmarshal(data, b); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := yaml.Unmarshal(data, b); err != nil {
+			return nil, err
+		}
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("unsupported bundle version %q, expected %q", b.Version, Version)
+	}
+	return b, nil
+}
+
+// Placeholder returns the placeholder of a credential field of a connector,
+// e.g., ${MY_CONNECTOR_API_KEY} for the field api_key of my-connector
+func Placeholder(connectorID string, field string) string {
+	name := envNameRegexp.ReplaceAllString(connectorID+"_"+field, "_")
+	return "${" + strings.ToUpper(strings.Trim(name, "_")) + "}"
+}
+
+// PlaceholderName returns the variable name of a placeholder value
+func PlaceholderName(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return "", false
+	}
+	m := placeholderRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// Expand substitutes the ${ENV} references in the descriptions and
+// configurations of the connectors with the values given by lookup. An error
+// is returned for undefined variables.
+func Expand(b *Bundle, lookup func(string) (string, bool)) error {
+	for _, c := range b.Connectors {
+		description, err := expandValue(c.Description, lookup)
+		if err != nil {
+			return fmt.Errorf("connector %s: %w", c.ID, err)
+		}
+		c.Description = description.(string)
+
+		if _, err := expandValue(c.Configuration, lookup); err != nil {
+			return fmt.Errorf("connector %s: %w", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// expandValue expands the strings of v, maps and slices are updated in place
+func expandValue(v interface{}, lookup func(string) (string, bool)) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		var missing []string
+		expanded := envRefRegexp.ReplaceAllStringFunc(t, func(ref string) string {
+			name := envRefRegexp.FindStringSubmatch(ref)[1]
+			val, ok := lookup(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+			return val
+		})
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("undefined variable %s", strings.Join(missing, ", "))
+		}
+		return expanded, nil
+	case map[string]interface{}:
+		for k, e := range t {
+			expanded, err := expandValue(e, lookup)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = expanded
+		}
+	case []interface{}:
+		for i, e := range t {
+			expanded, err := expandValue(e, lookup)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = expanded
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,222 @@
+// Package builtin implements the connector family whose definitions are
+// uploaded by the admins at runtime rather than compiled in. A definition
+// declares the connection specification of its connectors and the HTTP
+// request sent to execute them, so that a REST server, e.g., an internal model
+// server, is registered as a connector without a connector package of its own.
+package builtin
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	connectorBase "github.com/instill-ai/connector/pkg/base"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// requestTimeout bounds the HTTP requests of the builtin connectors
+const requestTimeout = 60 * time.Second
+
+var once sync.Once
+var connector *Connector
+
+// Connector is the registry of the builtin connector definitions, which is
+// shared by all the users of the package
+type Connector struct {
+	mu          sync.RWMutex
+	definitions map[uuid.UUID]*definition
+	// uids in registration order
+	uids []uuid.UUID
+
+	client *http.Client
+	Logger *zap.Logger
+}
+
+var _ connectorBase.IConnector = (*Connector)(nil)
+
+// Init returns the builtin connector registry
+func Init(logger *zap.Logger) *Connector {
+	once.Do(func() {
+		connector = &Connector{
+			definitions: map[uuid.UUID]*definition{},
+			client:      &http.Client{Timeout: requestTimeout},
+			Logger:      logger,
+		}
+	})
+	return connector
+}
+
+// Register validates the spec and registers its definition, which replaces
+// the definition with the same id, if any. The connectors of a replaced
+// definition use the new spec from their next connection.
+func (c *Connector) Register(spec *Spec) (*connectorPB.ConnectorDefinition, error) {
+
+	def, err := compile(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	uid := spec.UID()
+	if _, ok := c.definitions[uid]; !ok {
+		c.uids = append(c.uids, uid)
+	}
+	c.definitions[uid] = def
+
+	return def.pb, nil
+}
+
+// Unregister removes the definition with the given id
+func (c *Connector) Unregister(defId string) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	uid := (&Spec{ID: defId}).UID()
+	if _, ok := c.definitions[uid]; !ok {
+		return fmt.Errorf("builtin connector definition %s not found", defId)
+	}
+	delete(c.definitions, uid)
+	for idx := range c.uids {
+		if c.uids[idx] == uid {
+			c.uids = append(c.uids[:idx], c.uids[idx+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// GetSpec returns the spec of the definition with the given id
+func (c *Connector) GetSpec(defId string) (*Spec, error) {
+	def, err := c.get((&Spec{ID: defId}).UID())
+	if err != nil {
+		return nil, err
+	}
+	return def.spec, nil
+}
+
+// ListSpecs returns the specs in registration order
+func (c *Connector) ListSpecs() []*Spec {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	specs := make([]*Spec, 0, len(c.uids))
+	for _, uid := range c.uids {
+		specs = append(specs, c.definitions[uid].spec)
+	}
+	return specs
+}
+
+func (c *Connector) get(defUid uuid.UUID) (*definition, error) {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	def, ok := c.definitions[defUid]
+	if !ok {
+		return nil, fmt.Errorf("get connector defintion error")
+	}
+	return def, nil
+}
+
+// AddConnectorDefinition implements connectorBase.IConnector, the builtin
+// definitions are registered from their spec instead
+func (c *Connector) AddConnectorDefinition(uid uuid.UUID, id string, def *connectorPB.ConnectorDefinition) error {
+	return fmt.Errorf("builtin connector definition %s must be registered from a spec", id)
+}
+
+func (c *Connector) GetConnectorDefinitionMap() map[uuid.UUID]*connectorPB.ConnectorDefinition {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	defs := make(map[uuid.UUID]*connectorPB.ConnectorDefinition, len(c.definitions))
+	for uid, def := range c.definitions {
+		defs[uid] = def.pb
+	}
+	return defs
+}
+
+func (c *Connector) GetConnectorDefinitionByUid(defUid uuid.UUID) (*connectorPB.ConnectorDefinition, error) {
+	def, err := c.get(defUid)
+	if err != nil {
+		return nil, err
+	}
+	return def.pb, nil
+}
+
+func (c *Connector) GetConnectorDefinitionById(defId string) (*connectorPB.ConnectorDefinition, error) {
+	return c.GetConnectorDefinitionByUid((&Spec{ID: defId}).UID())
+}
+
+func (c *Connector) ListConnectorDefinitions() []*connectorPB.ConnectorDefinition {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	defs := make([]*connectorPB.ConnectorDefinition, 0, len(c.uids))
+	for _, uid := range c.uids {
+		defs = append(defs, c.definitions[uid].pb)
+	}
+	return defs
+}
+
+func (c *Connector) ListConnectorDefinitionUids() []uuid.UUID {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]uuid.UUID{}, c.uids...)
+}
+
+func (c *Connector) ListCredentialField(defId string) []string {
+	spec, err := c.GetSpec(defId)
+	if err != nil {
+		return []string{}
+	}
+	return append([]string{}, spec.CredentialFields...)
+}
+
+func (c *Connector) HasUid(defUid uuid.UUID) bool {
+	_, err := c.get(defUid)
+	return err == nil
+}
+
+func (c *Connector) IsCredentialField(defId string, target string) bool {
+	def, err := c.get((&Spec{ID: defId}).UID())
+	return err == nil && def.credential[target]
+}
+
+// CreateConnection validates the configuration against the connection
+// specification of the definition and returns a connection to execute it
+func (c *Connector) CreateConnection(defUid uuid.UUID, config *structpb.Struct, logger *zap.Logger) (connectorBase.IConnection, error) {
+
+	def, err := c.get(defUid)
+	if err != nil {
+		return nil, err
+	}
+
+	configuration := map[string]interface{}{}
+	if config != nil {
+		configuration = config.AsMap()
+	}
+	if err := def.schema.Validate(configuration); err != nil {
+		return nil, fmt.Errorf("invalid configuration of connector definition %s: %w", def.spec.ID, err)
+	}
+
+	return &Connection{
+		BaseConnection: connectorBase.BaseConnection{Logger: logger},
+		definition:     def,
+		config:         configuration,
+		client:         c.client,
+	}, nil
+}
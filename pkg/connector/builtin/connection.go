@@ -0,0 +1,229 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	connectorBase "github.com/instill-ai/connector/pkg/base"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// maxResponseSize bounds the response bodies read by the builtin connectors
+const maxResponseSize = 32 << 20
+
+// Connection is a connection of a builtin connector
+type Connection struct {
+	connectorBase.BaseConnection
+	definition *definition
+	config     map[string]interface{}
+	client     *http.Client
+}
+
+// Execute sends a request for each data payload and maps the responses to the outputs
+func (c *Connection) Execute(inputs []*connectorPB.DataPayload) ([]*connectorPB.DataPayload, error) {
+
+	outputs := make([]*connectorPB.DataPayload, 0, len(inputs))
+	for idx, input := range inputs {
+		b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+		inputMap := map[string]interface{}{}
+		if err := json.Unmarshal(b, &inputMap); err != nil {
+			return nil, err
+		}
+
+		status, body, err := c.send(c.definition.request, inputMap)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", idx, err)
+		}
+		if status < 200 || status >= 300 {
+			return nil, fmt.Errorf("input %d: request failed with status %d: %s", idx, status, truncate(body))
+		}
+
+		output, err := c.mapResponse(body)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", idx, err)
+		}
+		output.DataMappingIndex = input.GetDataMappingIndex()
+		outputs = append(outputs, output)
+	}
+
+	return outputs, nil
+}
+
+// Test sends the test request of the definition, if any
+func (c *Connection) Test() (connectorPB.Connector_State, error) {
+
+	if c.definition.test == nil {
+		return connectorPB.Connector_STATE_CONNECTED, nil
+	}
+
+	status, body, err := c.send(c.definition.test, map[string]interface{}{})
+	if err != nil {
+		c.Logger.Warn(fmt.Sprintf("test connector definition %s: %s", c.definition.spec.ID, err.Error()))
+		return connectorPB.Connector_STATE_ERROR, nil
+	}
+	if status < 200 || status >= 300 {
+		c.Logger.Warn(fmt.Sprintf("test connector definition %s: status %d: %s", c.definition.spec.ID, status, truncate(body)))
+		return connectorPB.Connector_STATE_ERROR, nil
+	}
+
+	return connectorPB.Connector_STATE_CONNECTED, nil
+}
+
+// GetTaskName returns the task of the definition
+func (c *Connection) GetTaskName() (string, error) {
+	return c.definition.spec.Task, nil
+}
+
+// send renders the request template and returns the response status and body
+func (c *Connection) send(rt *requestTemplate, input map[string]interface{}) (int, []byte, error) {
+
+	data := map[string]interface{}{
+		"config": c.config,
+		"input":  input,
+	}
+	render := func(name string, t interface {
+		Execute(io.Writer, interface{}) error
+	}) (string, error) {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("render the request %s: %w", name, err)
+		}
+		return buf.String(), nil
+	}
+
+	url, err := render("url", rt.url)
+	if err != nil {
+		return 0, nil, err
+	}
+	var body io.Reader
+	if rt.body != nil {
+		b, err := render("body", rt.body)
+		if err != nil {
+			return 0, nil, err
+		}
+		body = strings.NewReader(b)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, rt.method, url, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, t := range rt.headers {
+		value, err := render("header "+key, t)
+		if err != nil {
+			return 0, nil, err
+		}
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return resp.StatusCode, b, nil
+}
+
+// mapResponse maps a JSON response body to an output data payload
+func (c *Connection) mapResponse(body []byte) (*connectorPB.DataPayload, error) {
+
+	var resp interface{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("the response is not JSON: %w", err)
+	}
+
+	output := &connectorPB.DataPayload{}
+	mapping := c.definition.spec.Response
+
+	if mapping.Texts != "" {
+		v, err := lookup(resp, mapping.Texts)
+		if err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case string:
+			output.Texts = []string{v}
+		case []interface{}:
+			for _, text := range v {
+				s, ok := text.(string)
+				if !ok {
+					return nil, fmt.Errorf("response texts %s is not a list of strings", mapping.Texts)
+				}
+				output.Texts = append(output.Texts, s)
+			}
+		default:
+			return nil, fmt.Errorf("response texts %s is neither a string nor a list of strings", mapping.Texts)
+		}
+	}
+
+	v, err := lookup(resp, mapping.StructuredData)
+	if err != nil {
+		return nil, err
+	}
+	if obj, ok := v.(map[string]interface{}); ok {
+		if output.StructuredData, err = structpb.NewStruct(obj); err != nil {
+			return nil, err
+		}
+	} else if mapping.StructuredData != "" {
+		return nil, fmt.Errorf("response structured data %s is not an object", mapping.StructuredData)
+	}
+
+	return output, nil
+}
+
+// lookup returns the value at the dot-separated path, the empty path is the value itself
+func lookup(v interface{}, path string) (interface{}, error) {
+	if path == "" {
+		return v, nil
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			value, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("response has no %s", path)
+			}
+			v = value
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, fmt.Errorf("response has no %s", path)
+			}
+			v = node[idx]
+		default:
+			return nil, fmt.Errorf("response has no %s", path)
+		}
+	}
+	return v, nil
+}
+
+// truncate shortens a response body quoted in an error
+func truncate(body []byte) string {
+	if len(body) > 512 {
+		return string(body[:512]) + "..."
+	}
+	return string(body)
+}
@@ -0,0 +1,345 @@
+package builtin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/gofrs/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/x/checkfield"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// namespace is the UUID namespace of the builtin definition uids, which are
+// derived from the definition id so that every replica agrees on them
+var namespace = uuid.Must(uuid.FromString("5a4bc1a8-0a4c-4fd4-9d5c-2f14f1a7b3e0"))
+
+// tasks are the connector tasks accepted by the connector table
+var tasks = []string{
+	"TASK_UNSPECIFIED",
+	"TASK_CLASSIFICATION",
+	"TASK_DETECTION",
+	"TASK_KEYPOINT",
+	"TASK_OCR",
+	"TASK_INSTANCE_SEGMENTATION",
+	"TASK_SEMANTIC_SEGMENTATION",
+	"TASK_TEXT_TO_IMAGE",
+	"TASK_TEXT_GENERATION",
+}
+
+// Spec is a builtin connector definition uploaded by an admin
+type Spec struct {
+	// ID of the connector definition
+	ID string `json:"id"`
+	// Title of the connector definition
+	Title            string `json:"title"`
+	DocumentationURL string `json:"documentation_url,omitempty"`
+	IconURL          string `json:"icon_url,omitempty"`
+	// ConnectorType is CONNECTOR_TYPE_AI by default
+	ConnectorType string `json:"connector_type,omitempty"`
+	// Task of the connectors, e.g., TASK_TEXT_GENERATION
+	Task string `json:"task"`
+	// ConnectionSpecification is the JSON schema of the connector configuration
+	ConnectionSpecification map[string]interface{} `json:"connection_specification"`
+	// CredentialFields are the dot-separated paths of the configuration
+	// fields which are masked in the responses, e.g., auth.api_key
+	CredentialFields []string `json:"credential_fields,omitempty"`
+	// Request is sent for each data payload when a connector is executed
+	Request Request `json:"request"`
+	// Test is sent when a connector is tested, the connector is connected if
+	// the response status is 2xx. Without a test request, a connector with a
+	// valid configuration is connected.
+	Test *Request `json:"test,omitempty"`
+	// Response maps the response to the output data payload
+	Response Response `json:"response"`
+}
+
+// Request is the template of an HTTP request. The URL, header values and body
+// are Go text/template strings evaluated with .config, the connector
+// configuration, and .input, the data payload with its proto field names,
+// e.g., {{.config.server_url}}/predict or {"prompt": {{json (index .input.texts 0)}}}.
+type Request struct {
+	// Method is POST by default
+	Method string `json:"method,omitempty"`
+	// URL values are escaped for the part of the URL they are written to,
+	// e.g., {{.config.model}} in {{.config.server_url}}/models/{{.config.model}}?key={{.config.key}}
+	// is path escaped and {{.config.key}} is query escaped. The values
+	// written before the path, e.g., the server URL, are written as is.
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Response maps the JSON response body to the output data payload with
+// dot-separated paths, where list elements are selected by their index, e.g.,
+// choices.0.text
+type Response struct {
+	// Texts is the path of a string or a list of strings
+	Texts string `json:"texts,omitempty"`
+	// StructuredData is the path of an object, the whole body by default
+	StructuredData string `json:"structured_data,omitempty"`
+}
+
+// SpecError is a validation error of a spec field
+type SpecError struct {
+	Field       string
+	Description string
+}
+
+func (e *SpecError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Description)
+}
+
+// UID returns the connector definition uid of the spec
+func (s *Spec) UID() uuid.UUID {
+	return uuid.NewV5(namespace, s.ID)
+}
+
+// Validate returns a *SpecError if the spec cannot be registered
+func (s *Spec) Validate() error {
+	_, err := compile(s)
+	return err
+}
+
+// definition is a validated spec with its compiled templates and schema
+type definition struct {
+	spec       *Spec
+	pb         *connectorPB.ConnectorDefinition
+	schema     *jsonschema.Schema
+	request    *requestTemplate
+	test       *requestTemplate
+	credential map[string]bool
+}
+
+type requestTemplate struct {
+	method  string
+	url     *template.Template
+	headers map[string]*template.Template
+	body    *template.Template
+}
+
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"pathescape": func(v interface{}) string {
+		return url.PathEscape(fmt.Sprint(v))
+	},
+	"queryescape": func(v interface{}) string {
+		return url.QueryEscape(fmt.Sprint(v))
+	},
+}
+
+// compile validates the spec and compiles its templates and schema
+func compile(spec *Spec) (*definition, error) {
+
+	if err := checkfield.CheckResourceID(spec.ID); err != nil {
+		return nil, &SpecError{Field: "id", Description: err.Error()}
+	}
+	if spec.Title == "" {
+		return nil, &SpecError{Field: "title", Description: "title is required"}
+	}
+
+	validTask := false
+	for _, task := range tasks {
+		validTask = validTask || spec.Task == task
+	}
+	if !validTask {
+		return nil, &SpecError{Field: "task", Description: fmt.Sprintf("task must be one of %s", strings.Join(tasks, ", "))}
+	}
+
+	connType := connectorPB.ConnectorType_CONNECTOR_TYPE_AI
+	if spec.ConnectorType != "" {
+		v, ok := connectorPB.ConnectorType_value[spec.ConnectorType]
+		if !ok || v == int32(connectorPB.ConnectorType_CONNECTOR_TYPE_UNSPECIFIED) {
+			return nil, &SpecError{Field: "connector_type", Description: fmt.Sprintf("invalid connector type %s", spec.ConnectorType)}
+		}
+		connType = connectorPB.ConnectorType(v)
+	}
+
+	if spec.ConnectionSpecification == nil {
+		return nil, &SpecError{Field: "connection_specification", Description: "connection_specification is required"}
+	}
+	connSpec, err := structpb.NewStruct(spec.ConnectionSpecification)
+	if err != nil {
+		return nil, &SpecError{Field: "connection_specification", Description: err.Error()}
+	}
+	b, err := json.Marshal(spec.ConnectionSpecification)
+	if err != nil {
+		return nil, &SpecError{Field: "connection_specification", Description: err.Error()}
+	}
+	schema, err := jsonschema.CompileString("builtin:///"+spec.ID+".json", string(b))
+	if err != nil {
+		return nil, &SpecError{Field: "connection_specification", Description: err.Error()}
+	}
+
+	credential := map[string]bool{}
+	for idx, path := range spec.CredentialFields {
+		if !hasProperty(spec.ConnectionSpecification, strings.Split(path, ".")) {
+			return nil, &SpecError{Field: fmt.Sprintf("credential_fields[%d]", idx), Description: fmt.Sprintf("%s is not a property of the connection specification", path)}
+		}
+		credential[path] = true
+	}
+
+	request, err := compileRequest("request", &spec.Request)
+	if err != nil {
+		return nil, err
+	}
+	var test *requestTemplate
+	if spec.Test != nil {
+		if test, err = compileRequest("test", spec.Test); err != nil {
+			return nil, err
+		}
+	}
+
+	return &definition{
+		spec: spec,
+		pb: &connectorPB.ConnectorDefinition{
+			Uid:              spec.UID().String(),
+			Id:               spec.ID,
+			Title:            spec.Title,
+			DocumentationUrl: spec.DocumentationURL,
+			IconUrl:          spec.IconURL,
+			Spec: &connectorPB.Spec{
+				DocumentationUrl:        spec.DocumentationURL,
+				ConnectionSpecification: connSpec,
+			},
+			ConnectorType: connType,
+			Public:        true,
+			Custom:        true,
+			Vendor:        "builtin",
+			VendorAttributes: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"task": structpb.NewStringValue(spec.Task),
+			}},
+		},
+		schema:     schema,
+		request:    request,
+		test:       test,
+		credential: credential,
+	}, nil
+}
+
+func compileRequest(field string, r *Request) (*requestTemplate, error) {
+
+	method := strings.ToUpper(r.Method)
+	switch method {
+	case "":
+		method = http.MethodPost
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return nil, &SpecError{Field: field + ".method", Description: fmt.Sprintf("unsupported method %s", r.Method)}
+	}
+
+	if r.URL == "" {
+		return nil, &SpecError{Field: field + ".url", Description: "url is required"}
+	}
+
+	parse := func(name string, text string) (*template.Template, error) {
+		t, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, &SpecError{Field: fmt.Sprintf("%s.%s", field, name), Description: err.Error()}
+		}
+		return t, nil
+	}
+
+	rt := &requestTemplate{method: method, headers: map[string]*template.Template{}}
+	var err error
+	if rt.url, err = parse("url", r.URL); err != nil {
+		return nil, err
+	}
+	escapeURL(rt.url.Tree, rt.url.Root, "")
+	for key, value := range r.Headers {
+		if rt.headers[key], err = parse("headers."+key, value); err != nil {
+			return nil, err
+		}
+	}
+	if r.Body != "" {
+		if rt.body, err = parse("body", r.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	return rt, nil
+}
+
+// hasProperty reports whether the JSON schema declares the property path
+func hasProperty(schema map[string]interface{}, path []string) bool {
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	property, ok := properties[path[0]].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if len(path) == 1 {
+		return true
+	}
+	return hasProperty(property, path[1:])
+}
+
+// escapeURL pipes the values written to the path and the query of a URL
+// template through pathescape and queryescape. The prefix is the URL written
+// before the list, where every value is written as a placeholder, and the
+// URL written after the list is returned.
+func escapeURL(tree *parse.Tree, list *parse.ListNode, prefix string) string {
+	if list == nil {
+		return prefix
+	}
+	for _, node := range list.Nodes {
+		switch node := node.(type) {
+		case *parse.TextNode:
+			prefix += string(node.Text)
+		case *parse.ActionNode:
+			// A declaration writes nothing
+			if len(node.Pipe.Decl) > 0 {
+				continue
+			}
+			if escape := urlEscapeFunc(prefix); escape != "" {
+				node.Pipe.Cmds = append(node.Pipe.Cmds, &parse.CommandNode{
+					NodeType: parse.NodeCommand,
+					Pos:      node.Pos,
+					Args:     []parse.Node{parse.NewIdentifier(escape).SetTree(tree).SetPos(node.Pos)},
+				})
+			}
+			prefix += "x"
+		case *parse.IfNode:
+			escapeURL(tree, node.List, prefix)
+			escapeURL(tree, node.ElseList, prefix)
+			prefix += "x"
+		case *parse.RangeNode:
+			escapeURL(tree, node.List, prefix)
+			escapeURL(tree, node.ElseList, prefix)
+			prefix += "x"
+		case *parse.WithNode:
+			escapeURL(tree, node.List, prefix)
+			escapeURL(tree, node.ElseList, prefix)
+			prefix += "x"
+		}
+	}
+	return prefix
+}
+
+// urlEscapeFunc returns the escape function of a value written after the
+// prefix, none before the path
+func urlEscapeFunc(prefix string) string {
+	if strings.Contains(prefix, "?") {
+		return "queryescape"
+	}
+	if i := strings.Index(prefix, "://"); i >= 0 {
+		prefix = prefix[i+len("://"):]
+	}
+	if strings.Contains(prefix, "/") {
+		return "pathescape"
+	}
+	return ""
+}
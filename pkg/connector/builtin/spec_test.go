@@ -0,0 +1,81 @@
+package builtin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func TestURLEscape(t *testing.T) {
+	var path, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.EscapedPath(), r.URL.RawQuery
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := &Connector{definitions: map[uuid.UUID]*definition{}, client: server.Client()}
+	connDef, err := c.Register(&Spec{
+		ID:    "escape",
+		Title: "Escape",
+		Task:  "TASK_UNSPECIFIED",
+		ConnectionSpecification: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"server_url": map[string]interface{}{"type": "string"},
+				"model":      map[string]interface{}{"type": "string"},
+				"key":        map[string]interface{}{"type": "string"},
+			},
+		},
+		Request: Request{URL: "{{.config.server_url}}/models/{{.config.model}}:predict?key={{.config.key}}"},
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	config, err := structpb.NewStruct(map[string]interface{}{
+		"server_url": server.URL,
+		"model":      "../admin?drop=1",
+		"key":        "a&b=c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := c.CreateConnection(uuid.FromStringOrNil(connDef.GetUid()), config, zap.NewNop())
+	if err != nil {
+		t.Fatalf("create connection: %v", err)
+	}
+	if _, err := conn.Execute([]*connectorPB.DataPayload{{}}); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	if want := "/models/..%2Fadmin%3Fdrop=1:predict"; path != want {
+		t.Errorf("path: got %s, want %s", path, want)
+	}
+	if want := "key=a%26b%3Dc"; query != want {
+		t.Errorf("query: got %s, want %s", query, want)
+	}
+}
+
+func TestURLEscapeFunc(t *testing.T) {
+	for prefix, want := range map[string]string{
+		"":                      "",
+		"https://":              "",
+		"https://x":             "",
+		"x":                     "",
+		"x/models/":             "pathescape",
+		"https://x/":            "pathescape",
+		"https://x/models?":     "queryescape",
+		"x/models?key=x&other=": "queryescape",
+	} {
+		if got := urlEscapeFunc(prefix); got != want {
+			t.Errorf("escape after %q: got %q, want %q", prefix, got, want)
+		}
+	}
+}
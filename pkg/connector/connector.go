@@ -2,12 +2,14 @@ package connector
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/connector/builtin"
 
 	connectorAI "github.com/instill-ai/connector-ai/pkg"
 	connectorBlockchain "github.com/instill-ai/connector-blockchain/pkg"
@@ -15,16 +17,23 @@ import (
 	connectorDestinationAirbyte "github.com/instill-ai/connector-destination/pkg/airbyte"
 	connectorSource "github.com/instill-ai/connector-source/pkg"
 	connectorBase "github.com/instill-ai/connector/pkg/base"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
 )
 
 const credentialMaskString = "*****MASK*****"
 
+var once sync.Once
+var connectorAll *Connector
+
 type Connector struct {
 	connectorBase.BaseConnector
 	Destination connectorBase.IConnector
 	Source      connectorBase.IConnector
 	Blockchain  connectorBase.IConnector
 	AI          connectorBase.IConnector
+	// Builtin holds the definitions registered at runtime, which are looked
+	// up after the compiled-in ones
+	Builtin *builtin.Connector
 }
 
 func GetConnectorDestinationOptions() connectorDestination.ConnectorOptions {
@@ -40,7 +49,16 @@ func GetConnectorDestinationOptions() connectorDestination.ConnectorOptions {
 
 }
 
+// InitConnectorAll returns the registry of the connectors of every family,
+// which is shared by the handlers, the services and the usage reporter
 func InitConnectorAll(logger *zap.Logger) connectorBase.IConnector {
+	once.Do(func() {
+		connectorAll = initConnectorAll(logger)
+	})
+	return connectorAll
+}
+
+func initConnectorAll(logger *zap.Logger) *Connector {
 	connectorSource := connectorSource.Init(logger)
 	connectorDestination := connectorDestination.Init(logger, GetConnectorDestinationOptions())
 	connectorBlockchain := connectorBlockchain.Init(logger, connectorBlockchain.ConnectorOptions{})
@@ -52,6 +70,7 @@ func InitConnectorAll(logger *zap.Logger) connectorBase.IConnector {
 		Source:        connectorSource,
 		Blockchain:    connectorBlockchain,
 		AI:            connectorAI,
+		Builtin:       builtin.Init(logger),
 	}
 
 	for _, uid := range connectorDestination.ListConnectorDefinitionUids() {
@@ -107,12 +126,75 @@ func (c *Connector) CreateConnection(defUid uuid.UUID, config *structpb.Struct,
 		return c.Blockchain.CreateConnection(defUid, config, logger)
 	case c.AI.HasUid(defUid):
 		return c.AI.CreateConnection(defUid, config, logger)
+	case c.Builtin.HasUid(defUid):
+		return c.Builtin.CreateConnection(defUid, config, logger)
 
 	default:
 		return nil, fmt.Errorf("no connector uid: %s", defUid)
 	}
 }
 
+func (c *Connector) GetConnectorDefinitionMap() map[uuid.UUID]*connectorPB.ConnectorDefinition {
+	defs := map[uuid.UUID]*connectorPB.ConnectorDefinition{}
+	for uid, def := range c.Builtin.GetConnectorDefinitionMap() {
+		defs[uid] = def
+	}
+	for uid, def := range c.BaseConnector.GetConnectorDefinitionMap() {
+		defs[uid] = def
+	}
+	return defs
+}
+
+func (c *Connector) GetConnectorDefinitionByUid(defUid uuid.UUID) (*connectorPB.ConnectorDefinition, error) {
+	if def, err := c.BaseConnector.GetConnectorDefinitionByUid(defUid); err == nil {
+		return def, nil
+	}
+	return c.Builtin.GetConnectorDefinitionByUid(defUid)
+}
+
+func (c *Connector) GetConnectorDefinitionById(defId string) (*connectorPB.ConnectorDefinition, error) {
+	if def, err := c.BaseConnector.GetConnectorDefinitionById(defId); err == nil {
+		return def, nil
+	}
+	return c.Builtin.GetConnectorDefinitionById(defId)
+}
+
+func (c *Connector) ListConnectorDefinitions() []*connectorPB.ConnectorDefinition {
+	return append(c.BaseConnector.ListConnectorDefinitions(), c.Builtin.ListConnectorDefinitions()...)
+}
+
+func (c *Connector) ListConnectorDefinitionUids() []uuid.UUID {
+	uids := append([]uuid.UUID{}, c.BaseConnector.ListConnectorDefinitionUids()...)
+	return append(uids, c.Builtin.ListConnectorDefinitionUids()...)
+}
+
+func (c *Connector) HasUid(defUid uuid.UUID) bool {
+	return c.BaseConnector.HasUid(defUid) || c.Builtin.HasUid(defUid)
+}
+
+// IsCompiledIn reports whether the definition id belongs to a compiled-in
+// connector family rather than to the builtin one
+func (c *Connector) IsCompiledIn(defId string) bool {
+	_, err := c.BaseConnector.GetConnectorDefinitionById(defId)
+	return err == nil
+}
+
+func (c *Connector) ListCredentialField(defId string) []string {
+	if !c.IsCompiledIn(defId) {
+		return c.Builtin.ListCredentialField(defId)
+	}
+	return c.BaseConnector.ListCredentialField(defId)
+}
+
+func (c *Connector) IsCredentialField(defId string, target string) bool {
+	for _, field := range c.ListCredentialField(defId) {
+		if target == field {
+			return true
+		}
+	}
+	return false
+}
+
 func MaskCredentialFields(connector connectorBase.IConnector, defId string, config *structpb.Struct) {
 	maskCredentialFields(connector, defId, config, "")
 }
@@ -0,0 +1,105 @@
+package connector
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ConfigurationMigration rewrites a connector configuration written for an
+// older connection specification of its definition. Since the stored
+// configurations carry no spec version, a migration recognises the old shape
+// by itself and leaves the other configurations unchanged.
+type ConfigurationMigration struct {
+	// Name describes the migration in the reports, e.g., rename api_token to api_key
+	Name string
+	// Migrate rewrites the configuration in place and reports whether it changed
+	Migrate func(configuration map[string]interface{}) (bool, error)
+}
+
+var configurationMigrations = struct {
+	sync.RWMutex
+	byDefinitionID map[string][]ConfigurationMigration
+}{byDefinitionID: map[string][]ConfigurationMigration{}}
+
+// RegisterConfigurationMigration registers a migration of the configurations
+// of the definition, the migrations are applied in registration order
+func RegisterConfigurationMigration(defId string, migration ConfigurationMigration) {
+	configurationMigrations.Lock()
+	defer configurationMigrations.Unlock()
+	configurationMigrations.byDefinitionID[defId] = append(configurationMigrations.byDefinitionID[defId], migration)
+}
+
+// ListConfigurationMigrations returns the migrations registered for the definition
+func ListConfigurationMigrations(defId string) []ConfigurationMigration {
+	configurationMigrations.RLock()
+	defer configurationMigrations.RUnlock()
+	return append([]ConfigurationMigration{}, configurationMigrations.byDefinitionID[defId]...)
+}
+
+// RenameConfigurationField returns a migration moving the value at the
+// dot-separated path from to the path to, unless the latter is already set
+func RenameConfigurationField(from string, to string) ConfigurationMigration {
+	return ConfigurationMigration{
+		Name: fmt.Sprintf("rename %s to %s", from, to),
+		Migrate: func(configuration map[string]interface{}) (bool, error) {
+			parent, key := lookupConfigurationField(configuration, from, false)
+			if parent == nil {
+				return false, nil
+			}
+			value, ok := parent[key]
+			if !ok {
+				return false, nil
+			}
+			toParent, toKey := lookupConfigurationField(configuration, to, true)
+			if toParent == nil {
+				return false, fmt.Errorf("%s is not an object", to)
+			}
+			if _, ok := toParent[toKey]; ok {
+				return false, nil
+			}
+			toParent[toKey] = value
+			delete(parent, key)
+			return true, nil
+		},
+	}
+}
+
+// DefaultConfigurationField returns a migration setting the field at the
+// dot-separated path to value if it is missing, e.g., for a newly required field
+func DefaultConfigurationField(path string, value interface{}) ConfigurationMigration {
+	return ConfigurationMigration{
+		Name: fmt.Sprintf("default %s", path),
+		Migrate: func(configuration map[string]interface{}) (bool, error) {
+			parent, key := lookupConfigurationField(configuration, path, true)
+			if parent == nil {
+				return false, fmt.Errorf("%s is not an object", path)
+			}
+			if _, ok := parent[key]; ok {
+				return false, nil
+			}
+			parent[key] = value
+			return true, nil
+		},
+	}
+}
+
+// lookupConfigurationField returns the object holding the field at the path
+// and the key of the field, the missing objects are created if create is set
+func lookupConfigurationField(configuration map[string]interface{}, path string, create bool) (map[string]interface{}, string) {
+	keys := strings.Split(path, ".")
+	parent := configuration
+	for _, key := range keys[:len(keys)-1] {
+		v, ok := parent[key]
+		if !ok && create {
+			v = map[string]interface{}{}
+			parent[key] = v
+		}
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, ""
+		}
+		parent = obj
+	}
+	return parent, keys[len(keys)-1]
+}
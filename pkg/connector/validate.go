@@ -0,0 +1,155 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// ConfigurationViolation is a configuration field which does not validate
+// against the connection specification of its definition
+type ConfigurationViolation struct {
+	// Pointer is the JSON pointer of the field in the configuration, empty for the configuration itself
+	Pointer string
+	// Description of the violation
+	Description string
+}
+
+func (v *ConfigurationViolation) Error() string {
+	if v.Pointer == "" {
+		return v.Description
+	}
+	return fmt.Sprintf("%s: %s", v.Pointer, v.Description)
+}
+
+// quotedNames matches the property names of a missing properties message
+var quotedNames = regexp.MustCompile(`'([^']*)'`)
+
+// emptySpecification stands for the definitions without connection specification
+var emptySpecification = &structpb.Struct{}
+
+// schemas caches the compiled connection specifications by the specification
+// struct, which is replaced rather than modified when a definition changes
+var schemas sync.Map
+
+// ValidateConfiguration validates the configuration against the connection
+// specification of the definition and returns the violations of the innermost
+// schema keywords
+func ValidateConfiguration(connDef *connectorPB.ConnectorDefinition, configuration map[string]interface{}) ([]*ConfigurationViolation, error) {
+
+	schema, err := compileConnectionSpecification(connDef)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.Validate(configuration)
+	if err == nil {
+		return nil, nil
+	}
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	violations := []*ConfigurationViolation{}
+	var collect func(e *jsonschema.ValidationError)
+	collect = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			// A violation per missing property, so that each points at its field
+			if strings.HasSuffix(e.KeywordLocation, "/required") {
+				if names := quotedNames.FindAllStringSubmatch(e.Message, -1); len(names) > 0 {
+					for _, name := range names {
+						violations = append(violations, &ConfigurationViolation{
+							Pointer:     e.InstanceLocation + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name[1]),
+							Description: "missing required property",
+						})
+					}
+					return
+				}
+			}
+			violations = append(violations, &ConfigurationViolation{
+				Pointer:     e.InstanceLocation,
+				Description: e.Message,
+			})
+			return
+		}
+		for _, cause := range e.Causes {
+			collect(cause)
+		}
+	}
+	collect(validationErr)
+
+	return violations, nil
+}
+
+// ApplyConfigurationDefaults sets the missing configuration fields which
+// declare a default in the connection specification of the definition. The
+// nested objects are filled as well, including those set by a default.
+func ApplyConfigurationDefaults(connDef *connectorPB.ConnectorDefinition, configuration map[string]interface{}) {
+	applyDefaults(connDef.GetSpec().GetConnectionSpecification().AsMap(), configuration)
+}
+
+func applyDefaults(schema map[string]interface{}, value map[string]interface{}) {
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key, p := range properties {
+		property, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := value[key]; !ok {
+			if d, ok := property["default"]; ok {
+				value[key] = d
+			}
+		}
+		if obj, ok := value[key].(map[string]interface{}); ok {
+			applyDefaults(property, obj)
+		}
+	}
+}
+
+// FieldPath turns a JSON pointer into the dot-separated path of the field,
+// e.g., /engine/host into engine.host
+func FieldPath(pointer string) string {
+	if pointer == "" {
+		return ""
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for idx, token := range tokens {
+		tokens[idx] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return strings.Join(tokens, ".")
+}
+
+func compileConnectionSpecification(connDef *connectorPB.ConnectorDefinition) (*jsonschema.Schema, error) {
+
+	spec := connDef.GetSpec().GetConnectionSpecification()
+	if spec == nil {
+		spec = emptySpecification
+	}
+	if schema, ok := schemas.Load(spec); ok {
+		return schema.(*jsonschema.Schema), nil
+	}
+
+	b, err := protojson.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := jsonschema.CompileString(fmt.Sprintf("connector-definitions:///%s.json", connDef.GetId()), string(b))
+	if err != nil {
+		return nil, fmt.Errorf("connection specification of connector definition %s: %w", connDef.GetId(), err)
+	}
+	schemas.Store(spec, schema)
+
+	return schema, nil
+}
@@ -0,0 +1,135 @@
+package connector
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func newTestDefinition(t *testing.T, id string, spec map[string]interface{}) *connectorPB.ConnectorDefinition {
+	t.Helper()
+	s, err := structpb.NewStruct(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &connectorPB.ConnectorDefinition{Id: id, Spec: &connectorPB.Spec{ConnectionSpecification: s}}
+}
+
+var testSpecification = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"engine", "api/key"},
+	"properties": map[string]interface{}{
+		"api/key": map[string]interface{}{"type": "string"},
+		"mode":    map[string]interface{}{"type": "string", "enum": []interface{}{"fast", "safe"}, "default": "safe"},
+		"engine": map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"host"},
+			"default":  map[string]interface{}{"host": "localhost"},
+			"properties": map[string]interface{}{
+				"host": map[string]interface{}{"type": "string"},
+				"port": map[string]interface{}{"type": "integer", "minimum": 1, "default": 8080},
+			},
+		},
+	},
+}
+
+func TestValidateConfiguration(t *testing.T) {
+	connDef := newTestDefinition(t, "validate", testSpecification)
+
+	for _, c := range []struct {
+		configuration string
+		want          []string
+	}{
+		{`{"api/key": "key", "engine": {"host": "localhost"}}`, nil},
+		// A violation per missing property, the JSON pointer tokens are escaped
+		{`{}`, []string{"/api~1key: missing required property", "/engine: missing required property"}},
+		{`{"api/key": "key", "engine": {"port": 0}}`, []string{"/engine/host: missing required property", "/engine/port: must be >= 1 but found 0"}},
+		{`{"api/key": 1, "engine": {"host": "localhost"}, "mode": "slow"}`, []string{"/api~1key: expected string, but got number", `/mode: value must be one of "fast", "safe"`}},
+	} {
+		configuration := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(c.configuration), &configuration); err != nil {
+			t.Fatal(err)
+		}
+		violations, err := ValidateConfiguration(connDef, configuration)
+		if err != nil {
+			t.Fatalf("validate %s: %v", c.configuration, err)
+		}
+		var got []string
+		for _, v := range violations {
+			got = append(got, v.Error())
+		}
+		if !sameElements(got, c.want) {
+			t.Errorf("violations of %s:\ngot  %q\nwant %q", c.configuration, got, c.want)
+		}
+	}
+
+	// The definitions without connection specification accept any configuration
+	violations, err := ValidateConfiguration(&connectorPB.ConnectorDefinition{Id: "empty"}, map[string]interface{}{"any": true})
+	if err != nil || len(violations) != 0 {
+		t.Fatalf("validate without connection specification: got %v and %v, want no violation", violations, err)
+	}
+}
+
+func TestApplyConfigurationDefaults(t *testing.T) {
+	connDef := newTestDefinition(t, "defaults", testSpecification)
+
+	for _, c := range []struct {
+		configuration string
+		want          string
+	}{
+		// The nested objects set by a default are filled as well
+		{`{}`, `{"engine": {"host": "localhost", "port": 8080}, "mode": "safe"}`},
+		// The set fields are kept
+		{`{"engine": {"host": "remote", "port": 9090}, "mode": "fast"}`, `{"engine": {"host": "remote", "port": 9090}, "mode": "fast"}`},
+		{`{"engine": {"host": "remote"}}`, `{"engine": {"host": "remote", "port": 8080}, "mode": "safe"}`},
+	} {
+		var configuration, want map[string]interface{}
+		if err := json.Unmarshal([]byte(c.configuration), &configuration); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(c.want), &want); err != nil {
+			t.Fatal(err)
+		}
+		ApplyConfigurationDefaults(connDef, configuration)
+		if !reflect.DeepEqual(configuration, want) {
+			t.Errorf("defaults of %s: got %v, want %v", c.configuration, configuration, want)
+		}
+	}
+}
+
+func TestFieldPath(t *testing.T) {
+	for pointer, want := range map[string]string{
+		"":             "",
+		"/engine/host": "engine.host",
+		"/api~1key":    "api/key",
+		"/tilde~0":     "tilde~",
+	} {
+		if got := FieldPath(pointer); got != want {
+			t.Errorf("field path of %q: got %q, want %q", pointer, got, want)
+		}
+	}
+}
+
+// sameElements compares two lists of strings regardless of their order
+func sameElements(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := map[string]int{}
+	for _, s := range a {
+		count[s]++
+	}
+	for _, s := range b {
+		count[s]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
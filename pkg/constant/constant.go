@@ -4,3 +4,18 @@ package constant
 const DefaultOwnerID string = "instill-ai"
 const HeaderOwnerUIDKey = "jwt-sub"
 const HeaderOwnerIDKey = "owner-id"
+
+// MaxBatchSize is the maximum number of items in a batch request
+const MaxBatchSize = 100
+
+// HeaderDeprecationKey is the response header with the deprecation message of a connector definition
+const HeaderDeprecationKey = "x-connector-definition-deprecation"
+
+// HeaderStateReasonKey is the response header with the reason of a connector in STATE_ERROR
+const HeaderStateReasonKey = "x-connector-state-reason"
+
+// HeaderRequestIDKey is the header with the ID of a request, which is added to its logs
+const HeaderRequestIDKey = "x-request-id"
+
+// HeaderDebugLogKey is the header enabling the debug logs of a request of an admin
+const HeaderDebugLogKey = "x-debug-log"
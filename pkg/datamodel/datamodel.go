@@ -51,6 +51,21 @@ type Connector struct {
 	State                  ConnectorState      `sql:"type:valid_state_type"`
 	Visibility             ConnectorVisibility `sql:"type:valid_visibility"`
 	Task                   string              `sql:"type:valid_task"`
+	// StateReason is the reason of a STATE_ERROR set by the service, it is
+	// cleared when the connector leaves the state
+	StateReason sql.NullString
+}
+
+// ConnectorUsageCount is a row of the connector usage aggregate, the number
+// of live connectors of an owner with the same type, state, visibility and
+// definition
+type ConnectorUsageCount struct {
+	Owner                  string
+	ConnectorType          ConnectorType
+	State                  ConnectorState
+	Visibility             ConnectorVisibility
+	ConnectorDefinitionUID uuid.UUID
+	Count                  int64
 }
 
 // ConnectorType is an alias type for Protobuf enum ConnectorType
@@ -94,3 +109,100 @@ func (r *ConnectorVisibility) Scan(value interface{}) error {
 func (r ConnectorVisibility) Value() (driver.Value, error) {
 	return connectorPB.Connector_Visibility(r).String(), nil
 }
+
+// ConnectorDefinitionPolicy is the data model of the connector_definition_policy
+// table, the administrator policy of a connector definition. A definition
+// without a policy is enabled.
+type ConnectorDefinitionPolicy struct {
+	ConnectorDefinitionUID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ConnectorDefinitionID  string
+	State                  DefinitionPolicyState `sql:"type:valid_definition_policy_state"`
+	Message                string
+	CreateTime             time.Time `gorm:"autoCreateTime:nano"`
+	UpdateTime             time.Time `gorm:"autoUpdateTime:nano"`
+}
+
+// DefinitionPolicyState is the state of a connector definition policy
+type DefinitionPolicyState string
+
+const (
+	// DefinitionPolicyEnabled definitions are listed and accept new connectors
+	DefinitionPolicyEnabled DefinitionPolicyState = "enabled"
+	// DefinitionPolicyDeprecated definitions accept new connectors, the policy
+	// message tells the users what to migrate to
+	DefinitionPolicyDeprecated DefinitionPolicyState = "deprecated"
+	// DefinitionPolicyHidden definitions are only visible to administrators
+	// and reject new connectors
+	DefinitionPolicyHidden DefinitionPolicyState = "hidden"
+)
+
+// IsValid reports whether s is a known policy state
+func (s DefinitionPolicyState) IsValid() bool {
+	switch s {
+	case DefinitionPolicyEnabled, DefinitionPolicyDeprecated, DefinitionPolicyHidden:
+		return true
+	}
+	return false
+}
+
+// AcceptsConnectors reports whether connectors of a definition in state s
+// can be created. The existing connectors of the other states are returned
+// with a tombstone.
+func (s DefinitionPolicyState) AcceptsConnectors() bool {
+	return s == DefinitionPolicyEnabled || s == DefinitionPolicyDeprecated
+}
+
+// BuiltinConnectorDefinition is the data model of the
+// builtin_connector_definition table, the spec of a connector definition
+// uploaded by an administrator
+type BuiltinConnectorDefinition struct {
+	UID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ID         string
+	Spec       datatypes.JSON `gorm:"type:jsonb"`
+	CreateTime time.Time      `gorm:"autoCreateTime:nano"`
+	UpdateTime time.Time      `gorm:"autoUpdateTime:nano"`
+}
+
+// ExecutionMetering is the data model of the execution_metering table, the
+// executions of a connector counted over the time window starting at
+// WindowStart
+type ExecutionMetering struct {
+	Owner                  string    `gorm:"primaryKey"`
+	ConnectorUID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ConnectorDefinitionUID uuid.UUID `gorm:"type:uuid"`
+	WindowStart            time.Time `gorm:"primaryKey"`
+	Executions             int64
+	InputItems             int64
+	PayloadBytes           int64
+}
+
+// AuditOutcome is the outcome of an audited operation
+type AuditOutcome string
+
+const (
+	// AuditOutcomeSuccess operations completed
+	AuditOutcomeSuccess AuditOutcome = "success"
+	// AuditOutcomeFailure operations returned an error, which is the message
+	// of the event
+	AuditOutcomeFailure AuditOutcome = "failure"
+)
+
+// AuditEvent is the data model of the append-only audit_event table, a
+// connector change or execution requested by an actor
+type AuditEvent struct {
+	UID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CreateTime time.Time `gorm:"autoCreateTime:nano"`
+	EventName  string
+	// Actor is the permalink of the caller, Owner that of the connector owner
+	Actor        string
+	Owner        string
+	ConnectorUID uuid.NullUUID `gorm:"type:uuid"`
+	ConnectorID  string
+	RequestID    string
+	TraceID      string
+	Outcome      AuditOutcome
+	Message      string
+	// Diff holds the changed connector fields with their before and after
+	// values, the credential fields are redacted
+	Diff datatypes.JSON `gorm:"type:jsonb"`
+}
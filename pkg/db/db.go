@@ -1,14 +1,25 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"sync"
 
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
 
 	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/tlsconfig"
+)
+
+// Database drivers
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
 )
 
 var db *gorm.DB
@@ -18,41 +29,96 @@ var once sync.Once
 func GetConnection() *gorm.DB {
 	once.Do(func() {
 		databaseConfig := config.Config.Database
-		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=%s",
-			databaseConfig.Host,
-			databaseConfig.Username,
-			databaseConfig.Password,
-			databaseConfig.Name,
-			databaseConfig.Port,
-			databaseConfig.TimeZone,
-		)
-		var err error
+
+		if databaseConfig.Driver == DriverSQLite {
+			var err error
+			if db, err = OpenSQLite(databaseConfig.Path); err != nil {
+				panic(fmt.Sprintf("Could not open database connection: %s", err.Error()))
+			}
+			return
+		}
+
+		sqlDB, err := OpenPostgres(&databaseConfig, databaseConfig.Name)
+		if err != nil {
+			panic(fmt.Sprintf("Could not open database connection: %s", err.Error()))
+		}
 		db, err = gorm.Open(postgres.New(postgres.Config{
-			DSN:                  dsn,
+			Conn:                 sqlDB,
 			PreferSimpleProtocol: true, // disables implicit prepared statement usage
-		}), &gorm.Config{
-			QueryFields: true, // QueryFields mode will select by all fields’ name for current model
-			NamingStrategy: schema.NamingStrategy{
-				SingularTable: true,
-			},
-		})
+		}), gormConfig())
 
 		if err != nil {
 			panic("Could not open database connection")
 		}
+		if err := db.Use(queryTracing{}); err != nil {
+			panic(fmt.Sprintf("Could not register the query tracing: %s", err.Error()))
+		}
+		if err := db.Use(queryMetrics{}); err != nil {
+			panic(fmt.Sprintf("Could not register the query metrics: %s", err.Error()))
+		}
 
-		sqlDB, _ := db.DB()
+		setPool(sqlDB, &databaseConfig)
 
-		// SetMaxIdleConns sets the maximum number of connections in the idle connection pool.
-		sqlDB.SetMaxIdleConns(databaseConfig.Pool.IdleConnections)
-		// SetMaxOpenConns sets the maximum number of open connections to the database.
-		sqlDB.SetMaxOpenConns(databaseConfig.Pool.MaxConnections)
-		// SetConnMaxLifetime sets the maximum amount of time a connection may be reused.
-		sqlDB.SetConnMaxLifetime(databaseConfig.Pool.ConnLifeTime)
+		// The pool sizes are reloadable
+		config.Subscribe(func(prev *config.AppConfig, next *config.AppConfig) {
+			if prev.Database.Pool != next.Database.Pool {
+				setPool(sqlDB, &next.Database)
+			}
+		})
 	})
 	return db
 }
 
+// OpenPostgres opens the database name of the Postgres server. With the TLS
+// enabled, the server certificate is verified as with sslmode verify-full.
+func OpenPostgres(databaseConfig *config.DatabaseConfig, name string) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(postgresDSN(databaseConfig, name))
+	if err != nil {
+		return nil, err
+	}
+	if connConfig.TLSConfig, err = tlsconfig.New(databaseConfig.TLS, databaseConfig.Host); err != nil {
+		return nil, err
+	}
+	return stdlib.OpenDB(*connConfig), nil
+}
+
+// postgresDSN returns the connection string of the database name. The sslmode
+// follows the TLS so that pgx never falls back to a plain connection, the TLS
+// configuration itself is set by OpenPostgres.
+func postgresDSN(databaseConfig *config.DatabaseConfig, name string) string {
+	sslMode := "disable"
+	if databaseConfig.TLS.Enabled {
+		sslMode = "verify-full"
+	}
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=%s",
+		databaseConfig.Host,
+		databaseConfig.Username,
+		databaseConfig.Password,
+		name,
+		databaseConfig.Port,
+		sslMode,
+		databaseConfig.TimeZone,
+	)
+}
+
+func setPool(sqlDB *sql.DB, databaseConfig *config.DatabaseConfig) {
+	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool.
+	sqlDB.SetMaxIdleConns(databaseConfig.Pool.IdleConnections)
+	// SetMaxOpenConns sets the maximum number of open connections to the database.
+	sqlDB.SetMaxOpenConns(databaseConfig.Pool.MaxConnections)
+	// SetConnMaxLifetime sets the maximum amount of time a connection may be reused.
+	sqlDB.SetConnMaxLifetime(databaseConfig.Pool.ConnLifeTime)
+}
+
+func gormConfig() *gorm.Config {
+	return &gorm.Config{
+		QueryFields: true, // QueryFields mode will select by all fields’ name for current model
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	}
+}
+
 // Close closes the db connection
 func Close(db *gorm.DB) {
 	// https://github.com/go-gorm/gorm/issues/3216
@@ -64,3 +130,12 @@ func Close(db *gorm.DB) {
 		sqlDB.Close()
 	}
 }
+
+// Ping verifies that the database is reachable
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
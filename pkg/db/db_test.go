@@ -0,0 +1,28 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/instill-ai/connector-backend/config"
+)
+
+func TestPostgresDSN(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		databaseConfig := &config.DatabaseConfig{Host: "pg-sql", Username: "postgres", Port: 5432, TimeZone: "Etc/UTC"}
+		databaseConfig.TLS.Enabled = enabled
+
+		connConfig, err := pgx.ParseConfig(postgresDSN(databaseConfig, "connector"))
+		if err != nil {
+			t.Fatalf("parse the DSN with TLS %v: %v", enabled, err)
+		}
+		// A fallback would retry without the TLS, or with the TLS when disabled
+		if len(connConfig.Fallbacks) != 0 {
+			t.Errorf("DSN with TLS %v: got %d fallbacks, want none", enabled, len(connConfig.Fallbacks))
+		}
+		if (connConfig.TLSConfig != nil) != enabled {
+			t.Errorf("DSN with TLS %v: got TLS config %v", enabled, connConfig.TLSConfig)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+)
+
+// queryStartKey is the instance key of the start time of a query
+const queryStartKey = "metrics:query_start"
+
+// queryMetrics is a gorm plugin recording the latency of the queries
+type queryMetrics struct{}
+
+func (queryMetrics) Name() string {
+	return "metrics"
+}
+
+func (queryMetrics) Initialize(db *gorm.DB) error {
+
+	before := func(db *gorm.DB) {
+		db.InstanceSet(queryStartKey, time.Now())
+	}
+	after := func(operation string) func(db *gorm.DB) {
+		return func(db *gorm.DB) {
+			v, ok := db.InstanceGet(queryStartKey)
+			if !ok {
+				return
+			}
+			err := db.Error
+			if err == gorm.ErrRecordNotFound {
+				err = nil
+			}
+			custom_otel.RecordDBQuery(db.Statement.Context, operation, db.Statement.Table, time.Since(v.(time.Time)), err)
+		}
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("metrics:before_create", before),
+		cb.Create().After("gorm:create").Register("metrics:after_create", after("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", before),
+		cb.Query().After("gorm:query").Register("metrics:after_query", after("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", before),
+		cb.Update().After("gorm:update").Register("metrics:after_update", after("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", before),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", after("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", before),
+		cb.Row().After("gorm:row").Register("metrics:after_row", after("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", before),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", after("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS public.connector;
+DROP TABLE IF EXISTS public.connector_definition;
+DROP TYPE IF EXISTS valid_state;
+DROP TYPE IF EXISTS valid_release_stage;
+DROP TYPE IF EXISTS valid_connector_type;
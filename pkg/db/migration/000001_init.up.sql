@@ -1,4 +1,3 @@
-BEGIN;
 CREATE TYPE valid_connector_type AS ENUM (
   'CONNECTOR_TYPE_UNSPECIFIED',
   'CONNECTOR_TYPE_SOURCE',
@@ -62,4 +61,3 @@ CREATE INDEX connector_connector_definition_uid_idx ON public.connector (connect
 CREATE UNIQUE INDEX unique_owner_id_connector_type_deleted_at ON public.connector (owner, id, connector_type)
 WHERE delete_time IS NULL;
 CREATE INDEX connector_uid_create_time_pagination ON public.connector (uid, create_time);
-COMMIT;
@@ -0,0 +1,10 @@
+-- The values added to valid_connector_type can not be removed from the enum type
+DROP INDEX IF EXISTS unique_owner_id_deleted_at;
+CREATE UNIQUE INDEX unique_owner_id_connector_type_deleted_at ON public.connector (owner, id, connector_type)
+WHERE delete_time IS NULL;
+
+ALTER TABLE public.connector DROP COLUMN IF EXISTS "task";
+ALTER TABLE public.connector DROP COLUMN IF EXISTS "visibility";
+
+DROP TYPE IF EXISTS valid_task;
+DROP TYPE IF EXISTS valid_visibility;
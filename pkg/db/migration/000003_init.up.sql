@@ -1,5 +1,3 @@
-BEGIN;
-
 ALTER TYPE valid_connector_type ADD VALUE IF NOT EXISTS 'CONNECTOR_TYPE_AI';
 ALTER TYPE valid_connector_type ADD VALUE IF NOT EXISTS 'CONNECTOR_TYPE_BLOCKCHAIN';
 
@@ -27,4 +25,3 @@ ALTER TABLE public.connector ADD COLUMN "task" valid_task DEFAULT 'TASK_UNSPECIF
 DROP INDEX IF EXISTS unique_owner_id_connector_type_deleted_at;
 CREATE UNIQUE INDEX unique_owner_id_deleted_at ON public.connector (owner, id) WHERE delete_time IS NULL;
 
-COMMIT;
@@ -0,0 +1,135 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/config"
+
+	database "github.com/instill-ai/connector-backend/pkg/db"
+)
+
+// goSteps are the migration steps implemented in Go
+var goSteps = []*Step{
+	{
+		Version:     4,
+		Name:        "model_connectors",
+		Kind:        KindGo,
+		Up:          migrateModelConnectors,
+		Description: "create an AI connector for each model of the model database, skipped if the database does not exist",
+	},
+}
+
+// modelConnectorDefinitionUID is the uid of the Instill Model connector definition
+const modelConnectorDefinitionUID = "ddcf42c3-4c30-4c65-9585-25f1c89b2b48"
+
+type model struct {
+	UID         string
+	ID          string
+	Owner       string
+	Visibility  string
+	Task        string
+	Description sql.NullString
+}
+
+// migrateModelConnectors creates an AI connector for each model of
+// model-backend, so that pipelines refer to models through connectors
+func migrateModelConnectors(ctx context.Context, tx *sql.Tx) error {
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_database WHERE datname = 'model')").Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	models, err := listModels()
+	if err != nil {
+		return err
+	}
+
+	serverURL := "https://api.instill.tech/model"
+	if strings.Split(config.Config.Server.Edition, ":")[0] == "local-ce" {
+		serverURL = "http://localhost:9080"
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	letters := []byte("abcdefghijklmnopqrstuvwxyz")
+
+	for _, m := range models {
+		// Suffix the id with random letters until it is free, deleted connectors included
+		connID := m.ID
+		for {
+			var count int64
+			if err := tx.QueryRowContext(ctx, "SELECT count(*) FROM connector WHERE owner = $1 AND id = $2", m.Owner, connID).Scan(&count); err != nil {
+				return err
+			}
+			if count == 0 {
+				break
+			}
+			b := make([]byte, 3)
+			for i := range b {
+				b[i] = letters[r.Intn(len(letters))]
+			}
+			connID = fmt.Sprintf("%s-%s", m.ID, string(b))
+		}
+
+		configuration, err := structpb.NewStruct(map[string]interface{}{
+			"api_key":    "",
+			"server_url": serverURL,
+			"model_id":   connID,
+		})
+		if err != nil {
+			return err
+		}
+		configurationJSON, err := configuration.MarshalJSON()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO connector (uid, id, owner, description, connector_definition_uid, tombstone, configuration, connector_type, visibility, state, task, create_time, update_time, delete_time)
+			VALUES ($1, $2, $3, $4, $5, FALSE, $6, 'CONNECTOR_TYPE_AI', $7, 'STATE_DISCONNECTED', $8, NOW(), NOW(), NULL)`,
+			m.UID, connID, m.Owner, m.Description.String, modelConnectorDefinitionUID, string(configurationJSON), m.Visibility, m.Task,
+		); err != nil {
+			return fmt.Errorf("create the connector of model %s: %w", m.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// listModels returns the models of the model database, deleted ones included
+func listModels() ([]*model, error) {
+
+	databaseConfig := config.Config.Database
+	modelSQLDB, err := database.OpenPostgres(&databaseConfig, "model")
+	if err != nil {
+		return nil, err
+	}
+	defer modelSQLDB.Close()
+
+	rows, err := modelSQLDB.Query("SELECT uid, id, owner, visibility, task, description FROM model")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var models []*model
+	for rows.Next() {
+		m := &model{}
+		if err := rows.Scan(&m.UID, &m.ID, &m.Owner, &m.Visibility, &m.Task, &m.Description); err != nil {
+			return nil, err
+		}
+		models = append(models, m)
+	}
+
+	return models, rows.Err()
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.connector_definition_policy;
+
+DROP TYPE IF EXISTS valid_definition_policy_state;
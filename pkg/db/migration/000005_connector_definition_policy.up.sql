@@ -0,0 +1,15 @@
+CREATE TYPE valid_definition_policy_state AS ENUM (
+  'enabled',
+  'deprecated',
+  'hidden'
+);
+
+CREATE TABLE IF NOT EXISTS public.connector_definition_policy(
+  "connector_definition_uid" UUID NOT NULL,
+  "connector_definition_id" VARCHAR(255) NOT NULL,
+  "state" VALID_DEFINITION_POLICY_STATE DEFAULT 'enabled' NOT NULL,
+  "message" VARCHAR(1023) DEFAULT '' NOT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "update_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT connector_definition_policy_pkey PRIMARY KEY (connector_definition_uid)
+);
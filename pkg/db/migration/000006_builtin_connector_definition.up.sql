@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS public.builtin_connector_definition(
+  "uid" UUID NOT NULL,
+  "id" VARCHAR(255) NOT NULL,
+  "spec" JSONB NOT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "update_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT builtin_connector_definition_pkey PRIMARY KEY (uid)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS unique_builtin_connector_definition_id ON builtin_connector_definition (id);
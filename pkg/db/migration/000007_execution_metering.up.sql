@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS public.execution_metering(
+  "owner" VARCHAR(255) NOT NULL,
+  "connector_uid" UUID NOT NULL,
+  "connector_definition_uid" UUID NOT NULL,
+  "window_start" TIMESTAMPTZ NOT NULL,
+  "executions" BIGINT DEFAULT 0 NOT NULL,
+  "input_items" BIGINT DEFAULT 0 NOT NULL,
+  "payload_bytes" BIGINT DEFAULT 0 NOT NULL,
+  CONSTRAINT execution_metering_pkey PRIMARY KEY (owner, connector_uid, window_start)
+);
+CREATE INDEX IF NOT EXISTS execution_metering_owner_window_start ON execution_metering (owner, window_start);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS public.audit_event(
+  "uid" UUID NOT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "event_name" VARCHAR(255) NOT NULL,
+  "actor" VARCHAR(255) NOT NULL,
+  "owner" VARCHAR(255) DEFAULT '' NOT NULL,
+  "connector_uid" UUID NULL,
+  "connector_id" VARCHAR(255) DEFAULT '' NOT NULL,
+  "request_id" VARCHAR(255) DEFAULT '' NOT NULL,
+  "trace_id" VARCHAR(255) DEFAULT '' NOT NULL,
+  "outcome" VARCHAR(255) NOT NULL,
+  "message" TEXT DEFAULT '' NOT NULL,
+  "diff" JSONB NULL,
+  CONSTRAINT audit_event_pkey PRIMARY KEY (uid)
+);
+CREATE INDEX IF NOT EXISTS audit_event_create_time ON audit_event (create_time, uid);
+CREATE INDEX IF NOT EXISTS audit_event_actor_create_time ON audit_event (actor, create_time);
+CREATE INDEX IF NOT EXISTS audit_event_connector_uid_create_time ON audit_event (connector_uid, create_time);
+-- The audit events are append-only
+CREATE OR REPLACE FUNCTION audit_event_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_event is append-only';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER audit_event_append_only BEFORE UPDATE OR DELETE ON audit_event
+  FOR EACH ROW EXECUTE FUNCTION audit_event_append_only();
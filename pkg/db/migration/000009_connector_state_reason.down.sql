@@ -0,0 +1 @@
+ALTER TABLE public.connector DROP COLUMN IF EXISTS "state_reason";
@@ -0,0 +1 @@
+ALTER TABLE public.connector ADD COLUMN IF NOT EXISTS "state_reason" TEXT NULL;
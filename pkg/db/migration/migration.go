@@ -0,0 +1,463 @@
+// Package migration is the registry of the database schema migrations. A
+// step is either a pair of embedded SQL files, NNNNNN_name.up.sql and
+// NNNNNN_name.down.sql, or a Go function registered in goSteps. The applied
+// steps are recorded in the migration_history table.
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed *.sql
+var sqlFiles embed.FS
+
+// Kind is the implementation of a migration step
+type Kind string
+
+// Migration step kinds
+const (
+	KindSQL Kind = "sql"
+	KindGo  Kind = "go"
+)
+
+// Step is a migration step. SQL steps have UpSQL and optionally DownSQL, Go
+// steps have Up and optionally Down. A step without down is irreversible.
+type Step struct {
+	Version uint
+	Name    string
+	Kind    Kind
+	UpSQL   string
+	DownSQL string
+	Up      func(ctx context.Context, tx *sql.Tx) error
+	Down    func(ctx context.Context, tx *sql.Tx) error
+	// Description explains what a Go step does, it is printed by dry runs
+	Description string
+}
+
+// Status is the status of a migration step
+type Status struct {
+	Step      *Step
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// historyTable records the applied steps
+const historyTable = `CREATE TABLE IF NOT EXISTS migration_history(
+  "version" INTEGER NOT NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "kind" VARCHAR(16) NOT NULL,
+  "applied_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT migration_history_pkey PRIMARY KEY (version)
+)`
+
+// lockKey is the key of the Postgres advisory lock serializing the
+// migrations of concurrent instances
+const lockKey = 7_263_912_485_401
+
+var sqlFileRegexp = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Steps returns the migration steps ordered by version
+func Steps() ([]*Step, error) {
+
+	byVersion := map[uint]*Step{}
+
+	files, err := fs.ReadDir(sqlFiles, ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		m := sqlFileRegexp.FindStringSubmatch(file.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", file.Name())
+		}
+		version, err := strconv.ParseUint(m[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %s: %w", file.Name(), err)
+		}
+		content, err := sqlFiles.ReadFile(file.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		step, ok := byVersion[uint(version)]
+		if !ok {
+			step = &Step{Version: uint(version), Name: m[2], Kind: KindSQL}
+			byVersion[uint(version)] = step
+		} else if step.Name != m[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, step.Name, m[2])
+		}
+		if m[3] == "up" {
+			step.UpSQL = strings.TrimSpace(string(content))
+		} else {
+			step.DownSQL = strings.TrimSpace(string(content))
+		}
+	}
+
+	for _, step := range goSteps {
+		if _, ok := byVersion[step.Version]; ok {
+			return nil, fmt.Errorf("migration %d is registered more than once", step.Version)
+		}
+		byVersion[step.Version] = step
+	}
+
+	steps := make([]*Step, 0, len(byVersion))
+	for _, step := range byVersion {
+		if step.Kind == KindSQL && step.UpSQL == "" {
+			return nil, fmt.Errorf("migration %d %s has no up SQL", step.Version, step.Name)
+		}
+		steps = append(steps, step)
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Version < steps[j].Version })
+
+	return steps, nil
+}
+
+// Reversible reports whether the step can be migrated down
+func (s *Step) Reversible() bool {
+	if s.Kind == KindGo {
+		return s.Down != nil
+	}
+	return s.DownSQL != ""
+}
+
+// Migrator applies migration steps to a Postgres database. In dry run mode,
+// the SQL of the steps is written to Out instead of being executed.
+type Migrator struct {
+	db     *sql.DB
+	steps  []*Step
+	DryRun bool
+	Out    io.Writer
+}
+
+// NewMigrator initiates a migrator of the database
+func NewMigrator(db *sql.DB, steps []*Step) *Migrator {
+	return &Migrator{
+		db:    db,
+		steps: steps,
+		Out:   io.Discard,
+	}
+}
+
+// Status returns the status of every step
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return m.status(ctx, conn)
+}
+
+// Latest returns the version of the last step
+func (m *Migrator) Latest() uint {
+	if len(m.steps) == 0 {
+		return 0
+	}
+	return m.steps[len(m.steps)-1].Version
+}
+
+// Migrate applies the pending steps up to the target version. It fails if a
+// step above the target is applied, the applied steps are only reverted by
+// Revert.
+func (m *Migrator) Migrate(ctx context.Context, target uint) error {
+
+	if target > m.Latest() {
+		return fmt.Errorf("target version %d is above the latest migration %d", target, m.Latest())
+	}
+
+	return m.migrate(ctx, func(statuses []*Status) ([]*Step, error) {
+		return UpSteps(statuses, target)
+	}, true)
+}
+
+// Revert reverts the applied steps above the target version, the last one
+// first
+func (m *Migrator) Revert(ctx context.Context, target uint) error {
+	return m.migrate(ctx, func(statuses []*Status) ([]*Step, error) {
+		return DownSteps(statuses, target), nil
+	}, false)
+}
+
+func (m *Migrator) migrate(ctx context.Context, stepsOf func(statuses []*Status) ([]*Step, error), up bool) error {
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if !m.DryRun {
+		// The lock is held by the session, it is released when the connection is closed
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			return fmt.Errorf("acquire the migration lock: %w", err)
+		}
+		defer func() {
+			_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+		}()
+
+		if _, err := conn.ExecContext(ctx, historyTable); err != nil {
+			return fmt.Errorf("create the migration history: %w", err)
+		}
+		if err := m.adoptSchemaMigrations(ctx, conn); err != nil {
+			return err
+		}
+	}
+
+	// The status is read under the lock, another instance may have migrated meanwhile
+	statuses, err := m.status(ctx, conn)
+	if err != nil {
+		return err
+	}
+	steps, err := stepsOf(statuses)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		if err := m.apply(ctx, conn, step, up); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpSteps returns the pending steps up to the target version, in order. It
+// fails if a step above the target is applied, so that a target below the
+// version of the database never reverts it.
+func UpSteps(statuses []*Status, target uint) ([]*Step, error) {
+	var steps []*Step
+	for _, s := range statuses {
+		if s.Applied && s.Step.Version > target {
+			return nil, fmt.Errorf("step %06d %s above the target version %d is applied, revert it with the down command", s.Step.Version, s.Step.Name, target)
+		}
+		if !s.Applied && s.Step.Version <= target {
+			steps = append(steps, s.Step)
+		}
+	}
+	return steps, nil
+}
+
+// DownSteps returns the applied steps above the target version, the last
+// one first
+func DownSteps(statuses []*Status, target uint) []*Step {
+	var steps []*Step
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if s := statuses[i]; s.Applied && s.Step.Version > target {
+			steps = append(steps, s.Step)
+		}
+	}
+	return steps
+}
+
+// UpTarget returns the target version applying the next n pending steps
+func UpTarget(statuses []*Status, n int) uint {
+	var target uint
+	for _, s := range statuses {
+		if s.Applied {
+			target = s.Step.Version
+			continue
+		}
+		if n == 0 {
+			break
+		}
+		target = s.Step.Version
+		n--
+	}
+	return target
+}
+
+// DownTarget returns the target version reverting the last n applied steps
+func DownTarget(statuses []*Status, n int) uint {
+	var applied []uint
+	for _, s := range statuses {
+		if s.Applied {
+			applied = append(applied, s.Step.Version)
+		}
+	}
+	if n >= len(applied) {
+		return 0
+	}
+	return applied[len(applied)-1-n]
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, step *Step, up bool) error {
+
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+	if !up && !step.Reversible() {
+		return fmt.Errorf("migration %06d %s is irreversible", step.Version, step.Name)
+	}
+
+	if m.DryRun {
+		fmt.Fprintf(m.Out, "-- %06d %s (%s, %s)\n", step.Version, step.Name, step.Kind, direction)
+		switch {
+		case step.Kind == KindGo:
+			fmt.Fprintf(m.Out, "-- Go migration: %s\n\n", step.Description)
+		case up:
+			fmt.Fprintf(m.Out, "%s\n\n", step.UpSQL)
+		default:
+			fmt.Fprintf(m.Out, "%s\n\n", step.DownSQL)
+		}
+		return nil
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	switch {
+	case step.Kind == KindGo && up:
+		err = step.Up(ctx, tx)
+	case step.Kind == KindGo:
+		err = step.Down(ctx, tx)
+	case up:
+		_, err = tx.ExecContext(ctx, step.UpSQL)
+	default:
+		_, err = tx.ExecContext(ctx, step.DownSQL)
+	}
+	if err != nil {
+		return fmt.Errorf("migrate %06d %s %s: %w", step.Version, step.Name, direction, err)
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, "INSERT INTO migration_history (version, name, kind) VALUES ($1, $2, $3)", step.Version, step.Name, string(step.Kind))
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM migration_history WHERE version = $1", step.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("record migration %06d %s %s: %w", step.Version, step.Name, direction, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	fmt.Fprintf(m.Out, "Migrated %06d %s %s\n", step.Version, step.Name, direction)
+	return nil
+}
+
+// status reads the migration history, which is empty if the table does not
+// exist yet. The versions recorded by golang-migrate, which managed the
+// schema before, count as applied.
+func (m *Migrator) status(ctx context.Context, conn *sql.Conn) ([]*Status, error) {
+
+	applied := map[uint]time.Time{}
+
+	var exists bool
+	if err := conn.QueryRowContext(ctx, "SELECT to_regclass('migration_history') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists {
+		rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM migration_history")
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var version uint
+			var appliedAt time.Time
+			if err := rows.Scan(&version, &appliedAt); err != nil {
+				return nil, err
+			}
+			applied[version] = appliedAt
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(applied) == 0 {
+		version, err := schemaMigrationsVersion(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+		for _, step := range m.steps {
+			if step.Version <= version {
+				applied[step.Version] = time.Time{}
+			}
+		}
+	}
+
+	statuses := make([]*Status, 0, len(m.steps))
+	for _, step := range m.steps {
+		appliedAt, ok := applied[step.Version]
+		statuses = append(statuses, &Status{Step: step, Applied: ok, AppliedAt: appliedAt})
+		delete(applied, step.Version)
+	}
+	for version := range applied {
+		return nil, fmt.Errorf("applied migration %d is unknown to this version of the service", version)
+	}
+
+	return statuses, nil
+}
+
+// adoptSchemaMigrations records the steps applied by golang-migrate in an
+// empty migration history
+func (m *Migrator) adoptSchemaMigrations(ctx context.Context, conn *sql.Conn) error {
+
+	var count int
+	if err := conn.QueryRowContext(ctx, "SELECT count(*) FROM migration_history").Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	version, err := schemaMigrationsVersion(ctx, conn)
+	if err != nil || version == 0 {
+		return err
+	}
+	for _, step := range m.steps {
+		if step.Version > version {
+			break
+		}
+		if _, err := conn.ExecContext(ctx, "INSERT INTO migration_history (version, name, kind) VALUES ($1, $2, $3)", step.Version, step.Name, string(step.Kind)); err != nil {
+			return err
+		}
+		fmt.Fprintf(m.Out, "Adopted %06d %s from schema_migrations\n", step.Version, step.Name)
+	}
+	return nil
+}
+
+// schemaMigrationsVersion returns the version recorded by golang-migrate, 0
+// if it never ran
+func schemaMigrationsVersion(ctx context.Context, conn *sql.Conn) (uint, error) {
+
+	var exists bool
+	if err := conn.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	var version uint
+	var dirty bool
+	if err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema_migrations has the dirty flag at version %d, please fix it", version)
+	}
+	return version, nil
+}
@@ -0,0 +1,123 @@
+package migration_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/instill-ai/connector-backend/pkg/db/migration"
+)
+
+func TestSteps(t *testing.T) {
+	steps, err := migration.Steps()
+	if err != nil {
+		t.Fatalf("steps: %v", err)
+	}
+	for idx, step := range steps {
+		if step.Version != uint(idx+1) {
+			t.Fatalf("step %d %s: got version %d, want %d", idx, step.Name, step.Version, idx+1)
+		}
+		if step.Kind == migration.KindGo && (step.Up == nil || step.Description == "") {
+			t.Errorf("Go step %06d %s has no up or no description", step.Version, step.Name)
+		}
+		// The connector definition table and the model connectors are not restored
+		if got, want := step.Reversible(), step.Version != 2 && step.Version != 4; got != want {
+			t.Errorf("step %06d %s: got reversible %v, want %v", step.Version, step.Name, got, want)
+		}
+	}
+}
+
+func statusesOf(applied ...bool) []*migration.Status {
+	statuses := make([]*migration.Status, len(applied))
+	for idx := range applied {
+		statuses[idx] = &migration.Status{Step: &migration.Step{Version: uint(idx + 1)}, Applied: applied[idx]}
+	}
+	return statuses
+}
+
+func TestUpTarget(t *testing.T) {
+	for _, c := range []struct {
+		statuses []*migration.Status
+		n        int
+		want     uint
+	}{
+		{statusesOf(false, false, false), 1, 1},
+		{statusesOf(false, false, false), 3, 3},
+		{statusesOf(false, false, false), 5, 3},
+		{statusesOf(true, false, false), 1, 2},
+		{statusesOf(true, true, true), 1, 3},
+		{statusesOf(true, true, false), 0, 2},
+	} {
+		if got := migration.UpTarget(c.statuses, c.n); got != c.want {
+			t.Errorf("up %d from %d applied: got %d, want %d", c.n, len(c.statuses), got, c.want)
+		}
+	}
+}
+
+func TestDownTarget(t *testing.T) {
+	for _, c := range []struct {
+		statuses []*migration.Status
+		n        int
+		want     uint
+	}{
+		{statusesOf(true, true, true), 1, 2},
+		{statusesOf(true, true, true), 2, 1},
+		{statusesOf(true, true, true), 3, 0},
+		{statusesOf(true, true, false), 1, 1},
+		{statusesOf(false, false, false), 1, 0},
+		{statusesOf(true, true, true), 0, 3},
+	} {
+		if got := migration.DownTarget(c.statuses, c.n); got != c.want {
+			t.Errorf("down %d: got %d, want %d", c.n, got, c.want)
+		}
+	}
+}
+
+func versionsOf(steps []*migration.Step) []uint {
+	versions := []uint{}
+	for _, step := range steps {
+		versions = append(versions, step.Version)
+	}
+	return versions
+}
+
+func TestUpSteps(t *testing.T) {
+	for _, c := range []struct {
+		statuses []*migration.Status
+		target   uint
+		want     []uint
+	}{
+		{statusesOf(false, false, false), 3, []uint{1, 2, 3}},
+		{statusesOf(true, false, false), 2, []uint{2}},
+		{statusesOf(true, true, true), 3, []uint{}},
+		{statusesOf(true, false, true), 3, []uint{2}},
+	} {
+		steps, err := migration.UpSteps(c.statuses, c.target)
+		if err != nil {
+			t.Fatalf("up to %d: %v", c.target, err)
+		}
+		if got := versionsOf(steps); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("up to %d: got %v, want %v", c.target, got, c.want)
+		}
+	}
+
+	// A target below the applied version fails rather than reverting
+	if steps, err := migration.UpSteps(statusesOf(true, true, true), 1); err == nil {
+		t.Fatalf("up to 1 with 3 applied: got %v, want an error", versionsOf(steps))
+	}
+}
+
+func TestDownSteps(t *testing.T) {
+	for _, c := range []struct {
+		statuses []*migration.Status
+		target   uint
+		want     []uint
+	}{
+		{statusesOf(true, true, true), 1, []uint{3, 2}},
+		{statusesOf(true, true, false), 0, []uint{2, 1}},
+		{statusesOf(true, true, true), 3, []uint{}},
+	} {
+		if got := versionsOf(migration.DownSteps(c.statuses, c.target)); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("down to %d: got %v, want %v", c.target, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+//go:build postgres
+
+package migration_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+
+	"github.com/instill-ai/connector-backend/pkg/db/migration"
+)
+
+// The Postgres tests run with the postgres build tag against the server of
+// TEST_POSTGRES_DSN, a key/value DSN, e.g.,
+//
+//	TEST_POSTGRES_DSN="host=localhost user=postgres password=password dbname=postgres port=5432 sslmode=disable" \
+//		go test -tags postgres ./pkg/db/migration/...
+//
+// Every test migrates a database of its own, dropped afterwards.
+func openPostgres(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	server, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open the Postgres server: %v", err)
+	}
+	name := fmt.Sprintf("connector_migration_%d", time.Now().UnixNano())
+	if _, err := server.Exec("CREATE DATABASE " + name); err != nil {
+		t.Fatalf("create database %s: %v", name, err)
+	}
+
+	// The last dbname of the DSN is used
+	db, err := sql.Open("pgx", dsn+" dbname="+name)
+	if err != nil {
+		t.Fatalf("open database %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		_, _ = server.Exec("DROP DATABASE " + name)
+		server.Close()
+	})
+	return db
+}
+
+func newMigrator(t *testing.T, db *sql.DB) (*migration.Migrator, *bytes.Buffer) {
+	t.Helper()
+	steps, err := migration.Steps()
+	if err != nil {
+		t.Fatalf("steps: %v", err)
+	}
+	out := &bytes.Buffer{}
+	m := migration.NewMigrator(db, steps)
+	m.Out = out
+	return m, out
+}
+
+func appliedVersions(t *testing.T, m *migration.Migrator) []uint {
+	t.Helper()
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	var versions []uint
+	for _, s := range statuses {
+		if s.Applied {
+			versions = append(versions, s.Step.Version)
+		}
+	}
+	return versions
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	m, out := newMigrator(t, openPostgres(t))
+
+	m.DryRun = true
+	if err := m.Migrate(ctx, m.Latest()); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !strings.Contains(out.String(), "-- 000001 init (sql, up)") || !strings.Contains(out.String(), "-- Go migration: ") {
+		t.Fatalf("dry run output: got %s", out.String())
+	}
+	if versions := appliedVersions(t, m); len(versions) != 0 {
+		t.Fatalf("applied versions after a dry run: got %v, want none", versions)
+	}
+
+	m.DryRun = false
+	if err := m.Migrate(ctx, m.Latest()); err != nil {
+		t.Fatalf("migrate to %d: %v", m.Latest(), err)
+	}
+	if versions := appliedVersions(t, m); uint(len(versions)) != m.Latest() {
+		t.Fatalf("applied versions: got %v, want 1 to %d", versions, m.Latest())
+	}
+
+	// A target below the version of the database is not reverted
+	if err := m.Migrate(ctx, 4); err == nil || !strings.Contains(err.Error(), "down command") {
+		t.Fatalf("migrate to 4: got %v, want an error", err)
+	}
+	if versions := appliedVersions(t, m); uint(len(versions)) != m.Latest() {
+		t.Fatalf("applied versions after migrating to 4: got %v, want 1 to %d", versions, m.Latest())
+	}
+
+	// The steps above the Go step are reverted and applied again
+	if err := m.Revert(ctx, 4); err != nil {
+		t.Fatalf("revert to 4: %v", err)
+	}
+	if versions := appliedVersions(t, m); len(versions) != 4 {
+		t.Fatalf("applied versions after down: got %v, want 1 to 4", versions)
+	}
+	if err := m.Migrate(ctx, m.Latest()); err != nil {
+		t.Fatalf("migrate up again: %v", err)
+	}
+
+	if err := m.Revert(ctx, 3); err == nil || !strings.Contains(err.Error(), "irreversible") {
+		t.Fatalf("revert past the Go step: got %v, want an irreversible error", err)
+	}
+	if err := m.Migrate(ctx, m.Latest()+1); err == nil {
+		t.Fatalf("migrate above the latest: got no error")
+	}
+}
+
+func TestMigrateAdoptsSchemaMigrations(t *testing.T) {
+	ctx := context.Background()
+	db := openPostgres(t)
+	m, out := newMigrator(t, db)
+
+	// The schema migrated by golang-migrate up to version 3
+	steps, err := migration.Steps()
+	if err != nil {
+		t.Fatalf("steps: %v", err)
+	}
+	for _, step := range steps[:3] {
+		if _, err := db.Exec(step.UpSQL); err != nil {
+			t.Fatalf("apply %06d %s: %v", step.Version, step.Name, err)
+		}
+	}
+	if _, err := db.Exec("CREATE TABLE schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL); INSERT INTO schema_migrations VALUES (3, false)"); err != nil {
+		t.Fatalf("create schema_migrations: %v", err)
+	}
+
+	if versions := appliedVersions(t, m); len(versions) != 3 {
+		t.Fatalf("applied versions before the migration: got %v, want 1 to 3", versions)
+	}
+	if err := m.Migrate(ctx, m.Latest()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if !strings.Contains(out.String(), "Adopted 000003 init from schema_migrations") {
+		t.Fatalf("migration output: got %s", out.String())
+	}
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM migration_history").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if uint(count) != m.Latest() {
+		t.Fatalf("migration history: got %d steps, want %d", count, m.Latest())
+	}
+}
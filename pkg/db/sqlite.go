@@ -0,0 +1,147 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteSchema mirrors the Postgres schema of pkg/db/migration, the enum
+// types are replaced by check constraints
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS connector(
+  "uid" TEXT NOT NULL,
+  "id" VARCHAR(255) NOT NULL,
+  "connector_definition_uid" TEXT NOT NULL,
+  "owner" VARCHAR(255) NOT NULL,
+  "description" VARCHAR(1023) NULL,
+  "configuration" JSON NOT NULL,
+  "connector_type" TEXT DEFAULT 'CONNECTOR_TYPE_UNSPECIFIED' NOT NULL CHECK ("connector_type" IN (
+    'CONNECTOR_TYPE_UNSPECIFIED',
+    'CONNECTOR_TYPE_SOURCE',
+    'CONNECTOR_TYPE_DESTINATION',
+    'CONNECTOR_TYPE_AI',
+    'CONNECTOR_TYPE_BLOCKCHAIN'
+  )),
+  "state" TEXT DEFAULT 'STATE_UNSPECIFIED' NOT NULL CHECK ("state" IN (
+    'STATE_UNSPECIFIED',
+    'STATE_DISCONNECTED',
+    'STATE_CONNECTED',
+    'STATE_ERROR'
+  )),
+  "tombstone" BOOL DEFAULT FALSE NOT NULL,
+  "create_time" DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "update_time" DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "delete_time" DATETIME NULL,
+  "visibility" TEXT DEFAULT 'VISIBILITY_PRIVATE' NOT NULL CHECK ("visibility" IN (
+    'VISIBILITY_UNSPECIFIED',
+    'VISIBILITY_PRIVATE',
+    'VISIBILITY_PUBLIC'
+  )),
+  "task" TEXT DEFAULT 'TASK_UNSPECIFIED' NOT NULL,
+  "state_reason" TEXT NULL,
+  CONSTRAINT connector_pkey PRIMARY KEY (uid)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS unique_owner_id_deleted_at ON connector (owner, id) WHERE delete_time IS NULL;
+CREATE INDEX IF NOT EXISTS connector_uid_create_time_pagination ON connector (uid, create_time);
+CREATE TABLE IF NOT EXISTS connector_definition_policy(
+  "connector_definition_uid" TEXT NOT NULL,
+  "connector_definition_id" VARCHAR(255) NOT NULL,
+  "state" TEXT DEFAULT 'enabled' NOT NULL CHECK ("state" IN (
+    'enabled',
+    'deprecated',
+    'hidden'
+  )),
+  "message" VARCHAR(1023) DEFAULT '' NOT NULL,
+  "create_time" DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "update_time" DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT connector_definition_policy_pkey PRIMARY KEY (connector_definition_uid)
+);
+CREATE TABLE IF NOT EXISTS builtin_connector_definition(
+  "uid" TEXT NOT NULL,
+  "id" VARCHAR(255) NOT NULL,
+  "spec" TEXT NOT NULL,
+  "create_time" DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "update_time" DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT builtin_connector_definition_pkey PRIMARY KEY (uid)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS unique_builtin_connector_definition_id ON builtin_connector_definition (id);
+CREATE TABLE IF NOT EXISTS execution_metering(
+  "owner" VARCHAR(255) NOT NULL,
+  "connector_uid" TEXT NOT NULL,
+  "connector_definition_uid" TEXT NOT NULL,
+  "window_start" DATETIME NOT NULL,
+  "executions" INTEGER DEFAULT 0 NOT NULL,
+  "input_items" INTEGER DEFAULT 0 NOT NULL,
+  "payload_bytes" INTEGER DEFAULT 0 NOT NULL,
+  CONSTRAINT execution_metering_pkey PRIMARY KEY (owner, connector_uid, window_start)
+);
+CREATE INDEX IF NOT EXISTS execution_metering_owner_window_start ON execution_metering (owner, window_start);
+CREATE TABLE IF NOT EXISTS audit_event(
+  "uid" TEXT NOT NULL,
+  "create_time" DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "event_name" VARCHAR(255) NOT NULL,
+  "actor" VARCHAR(255) NOT NULL,
+  "owner" VARCHAR(255) DEFAULT '' NOT NULL,
+  "connector_uid" TEXT NULL,
+  "connector_id" VARCHAR(255) DEFAULT '' NOT NULL,
+  "request_id" VARCHAR(255) DEFAULT '' NOT NULL,
+  "trace_id" VARCHAR(255) DEFAULT '' NOT NULL,
+  "outcome" VARCHAR(255) NOT NULL,
+  "message" TEXT DEFAULT '' NOT NULL,
+  "diff" TEXT NULL,
+  CONSTRAINT audit_event_pkey PRIMARY KEY (uid)
+);
+CREATE INDEX IF NOT EXISTS audit_event_create_time ON audit_event (create_time, uid);
+CREATE INDEX IF NOT EXISTS audit_event_actor_create_time ON audit_event (actor, create_time);
+CREATE INDEX IF NOT EXISTS audit_event_connector_uid_create_time ON audit_event (connector_uid, create_time);
+CREATE TRIGGER IF NOT EXISTS audit_event_no_update BEFORE UPDATE ON audit_event
+BEGIN
+  SELECT RAISE(ABORT, 'audit_event is append-only');
+END;
+CREATE TRIGGER IF NOT EXISTS audit_event_no_delete BEFORE DELETE ON audit_event
+BEGIN
+  SELECT RAISE(ABORT, 'audit_event is append-only');
+END;
+`
+
+// OpenSQLite opens the SQLite database file at path, or an in-memory database
+// for ":memory:", and creates the schema if needed
+func OpenSQLite(path string) (*gorm.DB, error) {
+
+	config := gormConfig()
+	// Timestamps are stored as text, they must share the same time zone to be
+	// compared by the pagination
+	config.NowFunc = func() time.Time {
+		return time.Now().UTC()
+	}
+
+	db, err := gorm.Open(sqlite.Open(path), config)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(queryTracing{}); err != nil {
+		return nil, err
+	}
+	if err := db.Use(queryMetrics{}); err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer, and each connection to ":memory:" opens a
+	// distinct database
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.Exec("PRAGMA busy_timeout = 5000").Error; err != nil {
+		return nil, err
+	}
+	if err := db.Exec(sqliteSchema).Error; err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -2,40 +2,56 @@ package external
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/instill-ai/connector-backend/config"
 	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/tlsconfig"
 
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
 	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
 	usagePB "github.com/instill-ai/protogen-go/base/usage/v1alpha"
 	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
 	pipelinePB "github.com/instill-ai/protogen-go/vdp/pipeline/v1alpha"
 )
 
+// instrumentedDialOptions propagate the trace context of the caller to the
+// outgoing calls, which are traced and measured
+func instrumentedDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(otelgrpc.UnaryClientInterceptor(), custom_otel.UnaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+	}
+}
+
+// transportCredentials returns the dial option of the TLS configuration of a
+// dependency, the insecure credentials if the TLS is disabled
+func transportCredentials(cfg config.TLSConfig, host string) (grpc.DialOption, error) {
+	tlsConfig, err := tlsconfig.New(cfg, host)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
+}
+
 // InitMgmtPrivateServiceClient initialises a MgmtPrivateServiceClient instance
 func InitMgmtPrivateServiceClient(ctx context.Context) (mgmtPB.MgmtPrivateServiceClient, *grpc.ClientConn) {
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var clientDialOpts grpc.DialOption
-	var creds credentials.TransportCredentials
-	var err error
-	if config.Config.MgmtBackend.HTTPS.Cert != "" && config.Config.MgmtBackend.HTTPS.Key != "" {
-		creds, err = credentials.NewServerTLSFromFile(config.Config.MgmtBackend.HTTPS.Cert, config.Config.MgmtBackend.HTTPS.Key)
-		if err != nil {
-			logger.Fatal(err.Error())
-		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
+	clientDialOpts, err := transportCredentials(config.Config.MgmtBackend.TLS, config.Config.MgmtBackend.Host)
+	if err != nil {
+		logger.Fatal(err.Error())
 	}
 
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.MgmtBackend.Host, config.Config.MgmtBackend.PrivatePort), clientDialOpts)
+	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.MgmtBackend.Host, config.Config.MgmtBackend.PrivatePort), append(instrumentedDialOptions(), clientDialOpts)...)
 	if err != nil {
 		logger.Error(err.Error())
 		return nil, nil
@@ -48,20 +64,12 @@ func InitMgmtPrivateServiceClient(ctx context.Context) (mgmtPB.MgmtPrivateServic
 func InitPipelinePublicServiceClient(ctx context.Context) (pipelinePB.PipelinePublicServiceClient, *grpc.ClientConn) {
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var clientDialOpts grpc.DialOption
-	var creds credentials.TransportCredentials
-	var err error
-	if config.Config.PipelineBackend.HTTPS.Cert != "" && config.Config.PipelineBackend.HTTPS.Key != "" {
-		creds, err = credentials.NewServerTLSFromFile(config.Config.PipelineBackend.HTTPS.Cert, config.Config.PipelineBackend.HTTPS.Key)
-		if err != nil {
-			logger.Fatal(err.Error())
-		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
+	clientDialOpts, err := transportCredentials(config.Config.PipelineBackend.TLS, config.Config.PipelineBackend.Host)
+	if err != nil {
+		logger.Fatal(err.Error())
 	}
 
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.PipelineBackend.Host, config.Config.PipelineBackend.PublicPort), clientDialOpts)
+	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.PipelineBackend.Host, config.Config.PipelineBackend.PublicPort), append(instrumentedDialOptions(), clientDialOpts)...)
 	if err != nil {
 		logger.Error(err.Error())
 		return nil, nil
@@ -74,15 +82,12 @@ func InitPipelinePublicServiceClient(ctx context.Context) (pipelinePB.PipelinePu
 func InitUsageServiceClient(ctx context.Context) (usagePB.UsageServiceClient, *grpc.ClientConn) {
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var clientDialOpts grpc.DialOption
-	if config.Config.Server.Usage.TLSEnabled {
-		tlsConfig := &tls.Config{}
-		clientDialOpts = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
+	clientDialOpts, err := transportCredentials(config.Config.Server.Usage.TLS, config.Config.Server.Usage.Host)
+	if err != nil {
+		logger.Fatal(err.Error())
 	}
 
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.Server.Usage.Host, config.Config.Server.Usage.Port), clientDialOpts)
+	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.Server.Usage.Host, config.Config.Server.Usage.Port), append(instrumentedDialOptions(), clientDialOpts)...)
 	if err != nil {
 		logger.Error(err.Error())
 		return nil, nil
@@ -95,20 +100,12 @@ func InitUsageServiceClient(ctx context.Context) (usagePB.UsageServiceClient, *g
 func InitControllerPrivateServiceClient(ctx context.Context) (controllerPB.ControllerPrivateServiceClient, *grpc.ClientConn) {
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var clientDialOpts grpc.DialOption
-	var creds credentials.TransportCredentials
-	var err error
-	if config.Config.Controller.HTTPS.Cert != "" && config.Config.Controller.HTTPS.Key != "" {
-		creds, err = credentials.NewServerTLSFromFile(config.Config.Controller.HTTPS.Cert, config.Config.Controller.HTTPS.Key)
-		if err != nil {
-			logger.Fatal(err.Error())
-		}
-		clientDialOpts = grpc.WithTransportCredentials(creds)
-	} else {
-		clientDialOpts = grpc.WithTransportCredentials(insecure.NewCredentials())
+	clientDialOpts, err := transportCredentials(config.Config.Controller.TLS, config.Config.Controller.Host)
+	if err != nil {
+		logger.Fatal(err.Error())
 	}
 
-	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.Controller.Host, config.Config.Controller.PrivatePort), clientDialOpts)
+	clientConn, err := grpc.Dial(fmt.Sprintf("%v:%v", config.Config.Controller.Host, config.Config.Controller.PrivatePort), append(instrumentedDialOptions(), clientDialOpts)...)
 	if err != nil {
 		logger.Fatal(err.Error())
 	}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/instill-ai/connector-backend/pkg/audit"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/service"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+)
+
+// auditRecord collects the audit event of a handler call, it is written by
+// writeAudit with the outcome of the call once it returns
+type auditRecord struct {
+	event  datamodel.AuditEvent
+	before *datamodel.Connector
+	after  *datamodel.Connector
+}
+
+// newAuditRecord starts the audit event of a call, the request id is the id
+// of the log message of the call
+func newAuditRecord(span trace.Span, requestID string, eventName string) *auditRecord {
+	a := &auditRecord{}
+	a.event.EventName = eventName
+	a.event.RequestID = requestID
+	if span.SpanContext().HasTraceID() {
+		a.event.TraceID = span.SpanContext().TraceID().String()
+	}
+	return a
+}
+
+func (a *auditRecord) setActor(owner *mgmtPB.User) {
+	a.event.Actor = service.GenOwnerPermalink(owner)
+}
+
+func (a *auditRecord) setConnector(conn *datamodel.Connector) {
+	a.event.Owner = conn.Owner
+	a.event.ConnectorUID = uuid.NullUUID{UUID: conn.UID, Valid: true}
+	a.event.ConnectorID = conn.ID
+}
+
+// setBefore sets the connector state before the call
+func (a *auditRecord) setBefore(conn *datamodel.Connector) {
+	a.before = conn
+	a.setConnector(conn)
+}
+
+// setAfter sets the connector state after the call
+func (a *auditRecord) setAfter(conn *datamodel.Connector) {
+	a.after = conn
+	a.setConnector(conn)
+}
+
+// auditConnector sets the connector of the audit record, and its state
+// before the call if before is true. The connector is only looked up if the
+// audit is enabled, a failed look up leaves the connector unset.
+func (h *PublicHandler) auditConnector(ctx context.Context, a *auditRecord, connID string, owner *mgmtPB.User, before bool) {
+	if h.audit == nil {
+		return
+	}
+	conn, err := h.service.GetConnectorByID(ctx, connID, owner, !before)
+	if err != nil {
+		a.event.ConnectorID = connID
+		return
+	}
+	if before {
+		a.setBefore(conn)
+	} else {
+		a.setConnector(conn)
+	}
+}
+
+// writeAudit writes the audit event with the outcome of err. A failure to
+// write the event is logged, the call is not failed.
+func (h *PublicHandler) writeAudit(ctx context.Context, a *auditRecord, err error) {
+
+	if h.audit == nil {
+		return
+	}
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	event := a.event
+	if uid, uidErr := uuid.NewV4(); uidErr == nil {
+		event.UID = uid
+	}
+	event.CreateTime = time.Now().UTC()
+	if err != nil {
+		event.Outcome = datamodel.AuditOutcomeFailure
+		event.Message = err.Error()
+	} else {
+		event.Outcome = datamodel.AuditOutcomeSuccess
+	}
+
+	if a.before != nil || a.after != nil {
+		conn := a.after
+		if conn == nil {
+			conn = a.before
+		}
+		// The credential fields of an unknown definition are unknown, the
+		// whole configuration is redacted
+		var isCredential func(key string) bool
+		if connDef, defErr := h.connectors.GetConnectorDefinitionByUid(conn.ConnectorDefinitionUID); defErr == nil {
+			isCredential = func(key string) bool {
+				return h.connectors.IsCredentialField(connDef.GetId(), key)
+			}
+		}
+		event.Diff = audit.Diff(a.before, a.after, isCredential)
+	}
+
+	if writeErr := h.audit.Write(ctx, &event); writeErr != nil {
+		logger.Error(fmt.Sprintf("write audit event %s of %s: %s", event.EventName, event.ConnectorID, writeErr.Error()))
+	}
+}
+
+// auditBatchItem writes the audit event of the batch item on the connector
+// connID, a created connector is audited after and a deleted one before the
+// call. The state changes are audited without a diff, the batch results
+// holding the connectors after the change only.
+func (h *PublicHandler) auditBatchItem(ctx context.Context, span trace.Span, logID string, owner *mgmtPB.User, eventName string, connID string, batchResult *service.BatchResult) {
+
+	if h.audit == nil {
+		return
+	}
+
+	a := newAuditRecord(span, logID, eventName)
+	a.setActor(owner)
+	a.event.ConnectorID = connID
+	if conn := batchResult.Connector; conn != nil {
+		switch eventName {
+		case "CreateConnector":
+			a.setAfter(conn)
+		case "DeleteConnector":
+			a.setBefore(conn)
+		default:
+			a.setConnector(conn)
+		}
+	}
+	h.writeAudit(ctx, a, batchResult.Err)
+}
@@ -127,3 +127,25 @@ func DBToPBConnector(
 	return pbConnector
 
 }
+
+// setPolicyTombstone sets the tombstone of a connector whose definition
+// policy rejects new connectors. Only the response is flagged, the stored
+// connector keeps its own tombstone.
+func setPolicyTombstone(pbConnector *connectorPB.Connector, connDefUID uuid.UUID, policies map[uuid.UUID]*datamodel.ConnectorDefinitionPolicy) {
+	if policy, ok := policies[connDefUID]; ok && !policy.State.AcceptsConnectors() {
+		pbConnector.Tombstone = true
+	}
+}
+
+// DBToPBConnectorDefinitionPolicy converts a connector definition policy to its response representation
+func DBToPBConnectorDefinitionPolicy(policy *datamodel.ConnectorDefinitionPolicy) *ConnectorDefinitionPolicy {
+	pbPolicy := &ConnectorDefinitionPolicy{
+		Name:    fmt.Sprintf("connector-definitions/%s", policy.ConnectorDefinitionID),
+		State:   string(policy.State),
+		Message: policy.Message,
+	}
+	if !policy.UpdateTime.IsZero() {
+		pbPolicy.UpdateTime = timestamppb.New(policy.UpdateTime)
+	}
+	return pbPolicy
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/repository"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// connectorDefinitionDeclarations declares the fields which the connector
+// definitions can be filtered on. The filter syntax has no boolean literal,
+// true and false are declared so that the flags can be compared.
+func connectorDefinitionDeclarations() (*filtering.Declarations, error) {
+	var connType connectorPB.ConnectorType
+	return filtering.NewDeclarations([]filtering.DeclarationOption{
+		filtering.DeclareStandardFunctions(),
+		filtering.DeclareIdent("true", filtering.TypeBool),
+		filtering.DeclareIdent("false", filtering.TypeBool),
+		filtering.DeclareIdent("uid", filtering.TypeString),
+		filtering.DeclareIdent("id", filtering.TypeString),
+		filtering.DeclareIdent("title", filtering.TypeString),
+		filtering.DeclareIdent("description", filtering.TypeString),
+		filtering.DeclareIdent("vendor", filtering.TypeString),
+		filtering.DeclareIdent("release_stage", filtering.TypeString),
+		filtering.DeclareIdent("task", filtering.TypeString),
+		filtering.DeclareEnumIdent("connector_type", connType.Type()),
+		filtering.DeclareIdent("public", filtering.TypeBool),
+		filtering.DeclareIdent("custom", filtering.TypeBool),
+		filtering.DeclareIdent("tombstone", filtering.TypeBool),
+		filtering.DeclareIdent("capabilities", filtering.TypeList(filtering.TypeString)),
+	}...)
+}
+
+// connectorDefinitionFields resolves the filter identifiers of a definition.
+// The release stage, the task and the capabilities come from the vendor
+// attributes, the tombstone includes the one set by the definition policy.
+func connectorDefinitionFields(def *connectorPB.ConnectorDefinition, tombstone bool) repository.Fields {
+	attributes := def.GetVendorAttributes().GetFields()
+	return func(name string) (interface{}, error) {
+		switch name {
+		case "true", "false":
+			return name == "true", nil
+		case "uid":
+			return def.GetUid(), nil
+		case "id":
+			return def.GetId(), nil
+		case "title":
+			return def.GetTitle(), nil
+		case "description":
+			if description := connectorDefinitionDescription(def); description != "" {
+				return description, nil
+			}
+			return nil, nil
+		case "vendor":
+			return def.GetVendor(), nil
+		case "release_stage":
+			return stringAttribute(attributes, "releaseStage", "release_stage"), nil
+		case "task":
+			return stringAttribute(attributes, "task"), nil
+		case "connector_type":
+			return def.GetConnectorType().String(), nil
+		case "public":
+			return def.GetPublic(), nil
+		case "custom":
+			return def.GetCustom(), nil
+		case "tombstone":
+			return def.GetTombstone() || tombstone, nil
+		case "capabilities":
+			return connectorDefinitionCapabilities(attributes), nil
+		default:
+			return nil, fmt.Errorf("unknown field %s", name)
+		}
+	}
+}
+
+// stringAttribute returns the first vendor attribute set among the keys, nil if none
+func stringAttribute(attributes map[string]*structpb.Value, keys ...string) interface{} {
+	for _, key := range keys {
+		if v, ok := attributes[key].GetKind().(*structpb.Value_StringValue); ok {
+			return v.StringValue
+		}
+	}
+	return nil
+}
+
+// connectorDefinitionCapabilities returns the boolean vendor attributes which
+// are set, e.g., supportsIncremental of the vendor spec, in snake case
+func connectorDefinitionCapabilities(attributes map[string]*structpb.Value) []string {
+	capabilities := []string{}
+	collect := func(fields map[string]*structpb.Value) {
+		for key, value := range fields {
+			if value.GetBoolValue() {
+				capabilities = append(capabilities, strcase.ToSnake(key))
+			}
+		}
+	}
+	collect(attributes)
+	collect(attributes["spec"].GetStructValue().GetFields())
+	sort.Strings(capabilities)
+	return capabilities
+}
+
+// connectorDefinitionDescription returns the description of the connection specification
+func connectorDefinitionDescription(def *connectorPB.ConnectorDefinition) string {
+	return def.GetSpec().GetConnectionSpecification().GetFields()["description"].GetStringValue()
+}
+
+// matchSearchQuery reports whether every term of the query matches the
+// title, the description or the id of the definition. A term matches a
+// substring or, to tolerate typos, a word within a small edit distance.
+func matchSearchQuery(def *connectorPB.ConnectorDefinition, q string) bool {
+	text := strings.ToLower(strings.Join([]string{def.GetTitle(), connectorDefinitionDescription(def), def.GetId()}, " "))
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	for _, term := range strings.Fields(strings.ToLower(q)) {
+		if !matchSearchTerm(term, text, words) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchSearchTerm(term string, text string, words []string) bool {
+	if strings.Contains(text, term) {
+		return true
+	}
+	maxEdits := 0
+	switch {
+	case len(term) >= 8:
+		maxEdits = 2
+	case len(term) >= 4:
+		maxEdits = 1
+	}
+	if maxEdits == 0 {
+		return false
+	}
+	for _, word := range words {
+		if editDistance(term, word, maxEdits) <= maxEdits {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance returns the Levenshtein distance of a and b, or max+1 as soon
+// as it exceeds max
+func editDistance(a string, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return max + 1
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
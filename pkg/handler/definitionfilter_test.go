@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func TestConnectorDefinitionFilter(t *testing.T) {
+	attributes, err := structpb.NewStruct(map[string]interface{}{
+		"releaseStage": "alpha",
+		"spec":         map[string]interface{}{"supportsIncremental": true, "supportsNormalization": false},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := &connectorPB.ConnectorDefinition{
+		Uid:              uuid.Must(uuid.NewV4()).String(),
+		Id:               "destination-http",
+		Title:            "HTTP",
+		ConnectorType:    connectorPB.ConnectorType_CONNECTOR_TYPE_DESTINATION,
+		Public:           true,
+		VendorAttributes: attributes,
+	}
+
+	declarations, err := connectorDefinitionDeclarations()
+	if err != nil {
+		t.Fatalf("declarations: %v", err)
+	}
+	for _, c := range []struct {
+		filter    string
+		tombstone bool
+		want      bool
+	}{
+		{`id = "destination-http"`, false, true},
+		{`connector_type = CONNECTOR_TYPE_DESTINATION`, false, true},
+		{`connector_type = CONNECTOR_TYPE_SOURCE`, false, false},
+		{`release_stage = "alpha" AND public = true`, false, true},
+		{`capabilities:"supports_incremental"`, false, true},
+		{`capabilities:"supports_normalization"`, false, false},
+		{`tombstone = false`, false, true},
+		{`tombstone = true`, false, false},
+		{`tombstone = true`, true, true},
+	} {
+		filter := c.filter
+		parsed, err := filtering.ParseFilter(&connectorPB.ListConnectorDefinitionsRequest{Filter: &filter}, declarations)
+		if err != nil {
+			t.Fatalf("parse filter %s: %v", c.filter, err)
+		}
+		evaluator := repository.NewEvaluator(parsed)
+		got, err := evaluator.EvaluateFields(connectorDefinitionFields(def, c.tombstone))
+		if err != nil {
+			t.Fatalf("evaluate filter %s: %v", c.filter, err)
+		}
+		if got != c.want {
+			t.Errorf("filter %s with tombstone %v: got %v, want %v", c.filter, c.tombstone, got, c.want)
+		}
+	}
+}
+
+func TestMatchSearchQuery(t *testing.T) {
+	def := &connectorPB.ConnectorDefinition{Id: "destination-pinecone", Title: "Pinecone"}
+	for q, want := range map[string]bool{
+		"pinecone":             true,
+		"PINE":                 true,
+		"pincone":              true,
+		"destination pinecone": true,
+		"pinecone weaviate":    false,
+		"pin":                  true,
+		"pon":                  false,
+	} {
+		if got := matchSearchQuery(def, q); got != want {
+			t.Errorf("search %q: got %v, want %v", q, got, want)
+		}
+	}
+}
+
+func TestSetPolicyTombstone(t *testing.T) {
+	hidden := uuid.Must(uuid.NewV4())
+	deprecated := uuid.Must(uuid.NewV4())
+	policies := map[uuid.UUID]*datamodel.ConnectorDefinitionPolicy{
+		hidden:     {ConnectorDefinitionUID: hidden, State: datamodel.DefinitionPolicyHidden},
+		deprecated: {ConnectorDefinitionUID: deprecated, State: datamodel.DefinitionPolicyDeprecated},
+	}
+	for _, c := range []struct {
+		connDefUID uuid.UUID
+		tombstone  bool
+		want       bool
+	}{
+		{hidden, false, true},
+		{deprecated, false, false},
+		{deprecated, true, true},
+		{uuid.Must(uuid.NewV4()), false, false},
+	} {
+		pbConnector := &connectorPB.Connector{Tombstone: c.tombstone}
+		setPolicyTombstone(pbConnector, c.connDefUID, policies)
+		if pbConnector.Tombstone != c.want {
+			t.Errorf("connector of definition %s with tombstone %v: got %v, want %v", c.connDefUID, c.tombstone, pbConnector.Tombstone, c.want)
+		}
+	}
+}
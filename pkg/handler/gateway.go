@@ -0,0 +1,621 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/instill-ai/connector-backend/pkg/connector/builtin"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/middleware"
+
+	connectorExtPB "github.com/instill-ai/connector-backend/pkg/protogen/vdp/connector/v1alpha"
+	healthcheckPB "github.com/instill-ai/protogen-go/common/healthcheck/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// gatewayFunc is a custom endpoint implementation which returns the response body and the HTTP status code
+type gatewayFunc func(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error)
+
+// rawResponse is a gatewayFunc response body which is written as is
+type rawResponse struct {
+	contentType string
+	body        []byte
+}
+
+// protoJSON marshals the wrapped protobuf message with the same options as the gateway JSON marshaler
+type protoJSON struct {
+	proto.Message
+}
+
+// MarshalJSON implements json.Marshaler
+func (p protoJSON) MarshalJSON() ([]byte, error) {
+	if p.Message == nil || !p.Message.ProtoReflect().IsValid() {
+		return []byte("null"), nil
+	}
+	return protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+		UseEnumNumbers:  false,
+	}.Marshal(p.Message)
+}
+
+// RegisterPublicCustomHandlers registers the public endpoints which are not generated from the protobuf service definitions
+func RegisterPublicCustomHandlers(mux *runtime.ServeMux, h *PublicHandler) error {
+	// Takes over the generated endpoint, which has no search query
+	if err := mux.HandlePath("GET", "/v1alpha/connector-definitions", h.gatewayHandler(mux, "ListConnectorDefinitions", h.handleListConnectorDefinitions)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("POST", "/v1alpha/{name=connectors/*}/clone", h.gatewayHandler(mux, "CloneConnector", h.handleCloneConnector)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("POST", "/v1alpha/connectors/batchCreate", h.gatewayHandler(mux, "BatchCreateConnectors", h.handleBatchCreateConnectors)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("POST", "/v1alpha/connectors/batchDelete", h.gatewayHandler(mux, "BatchDeleteConnectors", h.handleBatchDeleteConnectors)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("POST", "/v1alpha/connectors/batchConnect", h.gatewayHandler(mux, "BatchConnectConnectors", h.handleBatchConnectConnectors)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("POST", "/v1alpha/connectors/batchDisconnect", h.gatewayHandler(mux, "BatchDisconnectConnectors", h.handleBatchDisconnectConnectors)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("POST", "/v1alpha/connectors/export", h.gatewayHandler(mux, "ExportConnectors", h.handleExportConnectors)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("POST", "/v1alpha/connectors/import", h.gatewayHandler(mux, "ImportConnectors", h.handleImportConnectors)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("GET", "/v1alpha/connector-definition-policies", h.gatewayHandler(mux, "ListConnectorDefinitionPolicies", h.handleListConnectorDefinitionPolicies)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("GET", "/v1alpha/{name=connector-definitions/*}/policy", h.gatewayHandler(mux, "GetConnectorDefinitionPolicy", h.handleGetConnectorDefinitionPolicy)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("GET", "/v1alpha/usage", h.gatewayHandler(mux, "GetUsage", h.handleGetUsage)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("GET", "/v1alpha/__readiness/dependencies", h.gatewayHandler(mux, "Readiness", h.handleReadinessDependencies)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RegisterPrivateCustomHandlers registers the private endpoints which are not
+// generated from the protobuf service definitions. The admin endpoints are
+// only served on the private port, the admin check of the service still applies.
+func RegisterPrivateCustomHandlers(mux *runtime.ServeMux, h *PublicHandler) error {
+	// The log level is read by GET and changed at runtime by PUT, e.g., {"level":"debug"}
+	logLevel := func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		logger.Level().ServeHTTP(w, r)
+	}
+	if err := mux.HandlePath("GET", "/v1alpha/admin/log-level", logLevel); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("PUT", "/v1alpha/admin/log-level", logLevel); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("POST", "/v1alpha/admin/connectors/checkConfigurations", h.gatewayHandler(mux, "CheckConnectorConfigurations", h.handleCheckConnectorConfigurations)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("PUT", "/v1alpha/admin/{name=connector-definitions/*}/policy", h.gatewayHandler(mux, "UpdateConnectorDefinitionPolicy", h.handleUpdateConnectorDefinitionPolicy)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("GET", "/v1alpha/admin/builtin-connector-definitions", h.gatewayHandler(mux, "ListBuiltinConnectorDefinitions", h.handleListBuiltinConnectorDefinitions)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("POST", "/v1alpha/admin/builtin-connector-definitions", h.gatewayHandler(mux, "CreateBuiltinConnectorDefinition", h.handleCreateBuiltinConnectorDefinition)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("DELETE", "/v1alpha/admin/{name=builtin-connector-definitions/*}", h.gatewayHandler(mux, "DeleteBuiltinConnectorDefinition", h.handleDeleteBuiltinConnectorDefinition)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("GET", "/v1alpha/admin/usage-reporter", h.gatewayHandler(mux, "GetUsageReporterStatus", h.handleGetUsageReporterStatus)); err != nil {
+		return err
+	}
+	if err := mux.HandlePath("GET", "/v1alpha/admin/audit-events", h.gatewayHandler(mux, "ListAuditEvents", h.handleListAuditEvents)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// gatewayHandler adapts a gatewayFunc to a gateway handler. The incoming
+// headers are converted to gRPC metadata so that the owner is resolved, and
+// the request context is set up, in the same way as for the generated
+// endpoints.
+func (h *PublicHandler) gatewayHandler(mux *runtime.ServeMux, rpcName string, fn gatewayFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateIncomingContext(r.Context(), mux, r, "/vdp.connector.v1alpha.ConnectorPublicService/"+rpcName)
+		if err != nil {
+			runtime.HTTPError(r.Context(), mux, outboundMarshaler, w, r, err)
+			return
+		}
+		if ctx, err = middleware.AppendMetadata(ctx); err != nil {
+			runtime.HTTPError(r.Context(), mux, outboundMarshaler, w, r, err)
+			return
+		}
+		ctx = middleware.NewRequestContext(ctx, h.service.GetMgmtPrivateServiceClient())
+
+		resp, code, err := fn(ctx, r, pathParams)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		if raw, ok := resp.(*rawResponse); ok {
+			w.Header().Set("Content-Type", raw.contentType)
+			w.WriteHeader(code)
+			_, _ = w.Write(raw.body)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		if resp != nil {
+			_ = json.NewEncoder(w).Encode(resp)
+		}
+	}
+}
+
+// decodeJSONBody decodes the request body into v, an empty body is accepted
+func decodeJSONBody(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return status.Errorf(codes.InvalidArgument, "invalid request body: %s", err.Error())
+	}
+	return nil
+}
+
+// decodeProtoBody decodes the JSON request body into the protobuf message m,
+// the unknown fields are discarded and an empty body is accepted
+func decodeProtoBody(r *http.Request, m proto.Message) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request body: %s", err.Error())
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, m); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request body: %s", err.Error())
+	}
+	return nil
+}
+
+func (h *PublicHandler) handleListConnectorDefinitions(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	query := r.URL.Query()
+	req := &SearchConnectorDefinitionsRequest{
+		ListConnectorDefinitionsRequest: &connectorPB.ListConnectorDefinitionsRequest{},
+		Q:                               query.Get("q"),
+	}
+	if v := query.Get("page_size"); v != "" {
+		pageSize, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, 0, status.Errorf(codes.InvalidArgument, "invalid page_size: %s", err.Error())
+		}
+		req.PageSize = &pageSize
+	}
+	if v := query.Get("page_token"); v != "" {
+		req.PageToken = &v
+	}
+	if v := query.Get("view"); v != "" {
+		view, ok := connectorPB.View_value[v]
+		if !ok {
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return nil, 0, status.Errorf(codes.InvalidArgument, "invalid view: %s", v)
+			}
+			view = int32(n)
+		}
+		req.View = connectorPB.View(view).Enum()
+	}
+	if v := query.Get("filter"); v != "" {
+		req.Filter = &v
+	}
+
+	resp, err := h.SearchConnectorDefinitions(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	return protoJSON{resp}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleCloneConnector(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	req := &connectorExtPB.CloneConnectorRequest{}
+	if err := decodeProtoBody(r, req); err != nil {
+		return nil, 0, err
+	}
+	req.Name = pathParams["name"]
+
+	resp, err := h.CloneConnector(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	return protoJSON{resp}, http.StatusCreated, nil
+}
+
+func (h *PublicHandler) handleBatchCreateConnectors(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	req := &connectorExtPB.BatchCreateConnectorsRequest{}
+	if err := decodeProtoBody(r, req); err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := h.BatchCreateConnectors(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	return protoJSON{resp}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleBatchDeleteConnectors(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	req := &connectorExtPB.BatchDeleteConnectorsRequest{}
+	if err := decodeProtoBody(r, req); err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := h.BatchDeleteConnectors(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	return protoJSON{resp}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleBatchConnectConnectors(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	req := &connectorExtPB.BatchConnectConnectorsRequest{}
+	if err := decodeProtoBody(r, req); err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := h.BatchConnectConnectors(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	return protoJSON{resp}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleBatchDisconnectConnectors(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	req := &connectorExtPB.BatchDisconnectConnectorsRequest{}
+	if err := decodeProtoBody(r, req); err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := h.BatchDisconnectConnectors(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	return protoJSON{resp}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleExportConnectors(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	resp, err := h.ExportConnectors(ctx, &ExportConnectorsRequest{
+		Format:      r.URL.Query().Get("format"),
+		Credentials: r.URL.Query().Get("credentials"),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return &rawResponse{contentType: resp.ContentType, body: resp.Bundle}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleImportConnectors(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, 0, status.Errorf(codes.InvalidArgument, "invalid request body: %s", err.Error())
+	}
+
+	// The bundle format follows the content type unless set explicitly
+	format := r.URL.Query().Get("format")
+	if format == "" && strings.Contains(r.Header.Get("Content-Type"), "json") {
+		format = "json"
+	}
+
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			return nil, 0, status.Errorf(codes.InvalidArgument, "invalid dry_run: %s", err.Error())
+		}
+	}
+
+	resp, err := h.ImportConnectors(ctx, &ImportConnectorsRequest{
+		Bundle:   body,
+		Format:   format,
+		Conflict: r.URL.Query().Get("conflict"),
+		DryRun:   dryRun,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]map[string]interface{}, len(resp.Results))
+	for idx, result := range resp.Results {
+		results[idx] = map[string]interface{}{
+			"id":           result.Id,
+			"connector_id": result.ConnectorId,
+			"action":       result.Action,
+			"conflict":     result.Conflict,
+			"connector":    protoJSON{result.Connector},
+			"status":       protoJSON{result.Status},
+		}
+	}
+	return map[string]interface{}{
+		"results": results,
+		"applied": resp.Applied,
+	}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleCheckConnectorConfigurations(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	req := &CheckConnectorConfigurationsRequest{}
+	if err := decodeJSONBody(r, req); err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := h.CheckConnectorConfigurations(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	reports := make([]map[string]interface{}, len(resp.Reports))
+	for idx, report := range resp.Reports {
+		reports[idx] = map[string]interface{}{
+			"connector_uid":             report.ConnectorUid,
+			"connector_id":              report.ConnectorId,
+			"owner":                     report.Owner,
+			"connector_definition_name": report.ConnectorDefinitionName,
+			"outcome":                   report.Outcome,
+			"migrations":                report.Migrations,
+			"reason":                    report.Reason,
+		}
+	}
+	return map[string]interface{}{
+		"reports": reports,
+		"applied": resp.Applied,
+	}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleListConnectorDefinitionPolicies(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	resp, err := h.ListConnectorDefinitionPolicies(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	policies := make([]map[string]interface{}, len(resp.Policies))
+	for idx, policy := range resp.Policies {
+		policies[idx] = policyJSON(policy)
+	}
+	return map[string]interface{}{
+		"policies": policies,
+	}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleGetConnectorDefinitionPolicy(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	resp, err := h.GetConnectorDefinitionPolicy(ctx, &GetConnectorDefinitionPolicyRequest{
+		Name: pathParams["name"],
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return map[string]interface{}{
+		"policy": policyJSON(resp.Policy),
+	}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleUpdateConnectorDefinitionPolicy(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	policy := &ConnectorDefinitionPolicy{}
+	if err := decodeJSONBody(r, policy); err != nil {
+		return nil, 0, err
+	}
+	policy.Name = pathParams["name"]
+
+	resp, err := h.UpdateConnectorDefinitionPolicy(ctx, &UpdateConnectorDefinitionPolicyRequest{
+		Policy: policy,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return map[string]interface{}{
+		"policy": policyJSON(resp.Policy),
+	}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleListBuiltinConnectorDefinitions(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	resp, err := h.ListBuiltinConnectorDefinitions(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return map[string]interface{}{
+		"specs": resp.Specs,
+	}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleCreateBuiltinConnectorDefinition(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	spec := &builtin.Spec{}
+	if err := decodeJSONBody(r, spec); err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := h.CreateBuiltinConnectorDefinition(ctx, &CreateBuiltinConnectorDefinitionRequest{
+		Spec: spec,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return map[string]interface{}{
+		"connector_definition": protoJSON{resp.ConnectorDefinition},
+	}, http.StatusCreated, nil
+}
+
+func (h *PublicHandler) handleDeleteBuiltinConnectorDefinition(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	if err := h.DeleteBuiltinConnectorDefinition(ctx, &DeleteBuiltinConnectorDefinitionRequest{
+		Name: pathParams["name"],
+	}); err != nil {
+		return nil, 0, err
+	}
+	return nil, http.StatusNoContent, nil
+}
+
+func (h *PublicHandler) handleGetUsageReporterStatus(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	resp, err := h.GetUsageReporterStatus(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return map[string]interface{}{
+		"enabled":           resp.Enabled,
+		"last_success_time": protoJSON{resp.LastSuccessTime},
+		"last_error_time":   protoJSON{resp.LastErrorTime},
+		"last_error":        resp.LastError,
+		"queued_reports":    resp.QueuedReports,
+		"dropped_reports":   resp.DroppedReports,
+	}, http.StatusOK, nil
+}
+
+func (h *PublicHandler) handleGetUsage(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	resp, err := h.GetUsage(ctx, &GetUsageRequest{
+		Owner: r.URL.Query().Get("owner"),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	connectors := make([]map[string]interface{}, len(resp.Connectors))
+	for idx, c := range resp.Connectors {
+		connectors[idx] = map[string]interface{}{
+			"connector_uid":             c.ConnectorUid,
+			"connector_definition_name": c.ConnectorDefinitionName,
+			"usage":                     countersJSON(c.Usage),
+		}
+	}
+	windows := make([]map[string]interface{}, len(resp.Windows))
+	for idx, w := range resp.Windows {
+		windows[idx] = map[string]interface{}{
+			"window_start": protoJSON{w.WindowStart},
+			"usage":        countersJSON(w.Usage),
+		}
+	}
+	return map[string]interface{}{
+		"owner":        resp.Owner,
+		"plan":         resp.Plan,
+		"period_start": protoJSON{resp.PeriodStart},
+		"period_end":   protoJSON{resp.PeriodEnd},
+		"usage":        countersJSON(resp.Usage),
+		"limits":       countersJSON(resp.Limits),
+		"connectors":   connectors,
+		"windows":      windows,
+	}, http.StatusOK, nil
+}
+
+// handleReadinessDependencies responds with 503 if a dependency is not serving
+func (h *PublicHandler) handleReadinessDependencies(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	resp, err := h.ReadinessDependencies(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dependencies := make([]map[string]interface{}, len(resp.Dependencies))
+	for idx, d := range resp.Dependencies {
+		dependencies[idx] = map[string]interface{}{
+			"name":       d.Name,
+			"status":     d.Status,
+			"message":    d.Message,
+			"check_time": protoJSON{d.CheckTime},
+			"latency":    protoJSON{d.Latency},
+		}
+	}
+	code := http.StatusOK
+	if resp.Status != healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING.String() {
+		code = http.StatusServiceUnavailable
+	}
+	return map[string]interface{}{
+		"status":       resp.Status,
+		"dependencies": dependencies,
+	}, code, nil
+}
+
+func (h *PublicHandler) handleListAuditEvents(ctx context.Context, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+	query := r.URL.Query()
+	req := &ListAuditEventsRequest{
+		Actor:        query.Get("actor"),
+		ConnectorUid: query.Get("connector_uid"),
+		PageToken:    query.Get("page_token"),
+	}
+	if v := query.Get("page_size"); v != "" {
+		pageSize, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, 0, status.Errorf(codes.InvalidArgument, "invalid page_size: %s", err.Error())
+		}
+		req.PageSize = pageSize
+	}
+	if v := query.Get("start_time"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, 0, status.Errorf(codes.InvalidArgument, "invalid start_time: %s", err.Error())
+		}
+		req.StartTime = timestamppb.New(t)
+	}
+	if v := query.Get("end_time"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, 0, status.Errorf(codes.InvalidArgument, "invalid end_time: %s", err.Error())
+		}
+		req.EndTime = timestamppb.New(t)
+	}
+
+	resp, err := h.ListAuditEvents(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	events := make([]map[string]interface{}, len(resp.Events))
+	for idx, e := range resp.Events {
+		events[idx] = map[string]interface{}{
+			"uid":           e.Uid,
+			"create_time":   protoJSON{e.CreateTime},
+			"event_name":    e.EventName,
+			"actor":         e.Actor,
+			"owner":         e.Owner,
+			"connector_uid": e.ConnectorUid,
+			"connector_id":  e.ConnectorId,
+			"request_id":    e.RequestId,
+			"trace_id":      e.TraceId,
+			"outcome":       e.Outcome,
+			"message":       e.Message,
+			"diff":          e.Diff,
+		}
+	}
+	return map[string]interface{}{
+		"events":          events,
+		"next_page_token": resp.NextPageToken,
+	}, http.StatusOK, nil
+}
+
+// countersJSON renders execution counters
+func countersJSON(c *ExecutionCounters) map[string]interface{} {
+	return map[string]interface{}{
+		"executions":    c.Executions,
+		"input_items":   c.InputItems,
+		"payload_bytes": c.PayloadBytes,
+	}
+}
+
+// policyJSON renders a connector definition policy, the update time is null for the default policy
+func policyJSON(policy *ConnectorDefinitionPolicy) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        policy.Name,
+		"state":       policy.State,
+		"message":     policy.Message,
+		"update_time": protoJSON{policy.UpdateTime},
+	}
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+func TestRegisterCustomHandlers(t *testing.T) {
+	h := &PublicHandler{}
+	if err := RegisterPublicCustomHandlers(runtime.NewServeMux(), h); err != nil {
+		t.Fatalf("register public custom handlers: %v", err)
+	}
+	if err := RegisterPrivateCustomHandlers(runtime.NewServeMux(), h); err != nil {
+		t.Fatalf("register private custom handlers: %v", err)
+	}
+}
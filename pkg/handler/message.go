@@ -0,0 +1,293 @@
+package handler
+
+import (
+	rpcStatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/instill-ai/connector-backend/pkg/audit"
+	"github.com/instill-ai/connector-backend/pkg/connector/builtin"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// ExportConnectorsRequest represents a request to export the connectors of the owner
+type ExportConnectorsRequest struct {
+	// Format of the bundle, yaml (default) or json
+	Format string
+	// Credentials defines how credential fields are exported, omit (default) or placeholder
+	Credentials string
+}
+
+// ExportConnectorsResponse represents a response for exporting connectors
+type ExportConnectorsResponse struct {
+	// Bundle is the serialized connector bundle
+	Bundle []byte
+	// ContentType is the MIME type of the bundle
+	ContentType string
+}
+
+// ImportConnectorsRequest represents a request to import a connector bundle
+type ImportConnectorsRequest struct {
+	// Bundle is the serialized connector bundle
+	Bundle []byte
+	// Format of the bundle, yaml (default) or json
+	Format string
+	// Conflict is the strategy for existing connector IDs, fail (default), skip, overwrite or rename
+	Conflict string
+	// DryRun only validates the bundle
+	DryRun bool
+}
+
+// ImportConnectorResult represents the outcome of importing a bundle entry
+type ImportConnectorResult struct {
+	// Id of the connector in the bundle
+	Id string
+	// ConnectorId of the imported connector
+	ConnectorId string
+	// Action taken for the entry: create, skip, overwrite or rename
+	Action string
+	// Conflict is true if a connector with the same ID already exists
+	Conflict bool
+	// Connector is the imported connector, unset if skipped, failed or not applied
+	Connector *connectorPB.Connector
+	// Status is the status of the entry
+	Status *rpcStatus.Status
+}
+
+// ImportConnectorsResponse represents a response for importing a connector bundle
+type ImportConnectorsResponse struct {
+	// Results in the order of the bundle entries
+	Results []*ImportConnectorResult
+	// Applied is true if the bundle has been written
+	Applied bool
+}
+
+// ConnectorDefinitionPolicy represents the policy of a connector definition set by the admins
+type ConnectorDefinitionPolicy struct {
+	// Name of the connector definition, e.g., connector-definitions/{id}
+	Name string `json:"name"`
+	// State is enabled, deprecated or hidden
+	State string `json:"state"`
+	// Message tells the users why the definition is deprecated or hidden
+	Message string `json:"message"`
+	// UpdateTime is the time of the last policy update, unset for the default policy
+	UpdateTime *timestamppb.Timestamp `json:"-"`
+}
+
+// ListConnectorDefinitionPoliciesResponse represents a response for listing
+// the stored connector definition policies
+type ListConnectorDefinitionPoliciesResponse struct {
+	Policies []*ConnectorDefinitionPolicy
+}
+
+// GetConnectorDefinitionPolicyRequest represents a request to get the policy of a connector definition
+type GetConnectorDefinitionPolicyRequest struct {
+	// Name of the connector definition, e.g., connector-definitions/{id}
+	Name string
+}
+
+// GetConnectorDefinitionPolicyResponse represents a response for getting the policy of a connector definition
+type GetConnectorDefinitionPolicyResponse struct {
+	Policy *ConnectorDefinitionPolicy
+}
+
+// UpdateConnectorDefinitionPolicyRequest represents a request to replace the
+// policy of a connector definition, which is restricted to the admins
+type UpdateConnectorDefinitionPolicyRequest struct {
+	Policy *ConnectorDefinitionPolicy
+}
+
+// UpdateConnectorDefinitionPolicyResponse represents a response for updating the policy of a connector definition
+type UpdateConnectorDefinitionPolicyResponse struct {
+	Policy *ConnectorDefinitionPolicy
+}
+
+// ListBuiltinConnectorDefinitionsResponse represents a response for listing
+// the specs of the builtin connector definitions
+type ListBuiltinConnectorDefinitionsResponse struct {
+	Specs []*builtin.Spec
+}
+
+// CreateBuiltinConnectorDefinitionRequest represents a request to register a
+// builtin connector definition, which replaces the one with the same id
+type CreateBuiltinConnectorDefinitionRequest struct {
+	Spec *builtin.Spec
+}
+
+// CreateBuiltinConnectorDefinitionResponse represents a response for registering a builtin connector definition
+type CreateBuiltinConnectorDefinitionResponse struct {
+	ConnectorDefinition *connectorPB.ConnectorDefinition
+}
+
+// DeleteBuiltinConnectorDefinitionRequest represents a request to delete a builtin connector definition
+type DeleteBuiltinConnectorDefinitionRequest struct {
+	// Name of the definition, e.g., builtin-connector-definitions/{id}
+	Name string
+}
+
+// CheckConnectorConfigurationsRequest represents a request to validate the
+// stored connector configurations against the current definitions, which is
+// restricted to the admins
+type CheckConnectorConfigurationsRequest struct {
+	// DryRun reports the connectors without migrating them or changing their state
+	DryRun bool `json:"dry_run"`
+}
+
+// ConnectorConfigurationReport represents a connector whose configuration is
+// invalid for the current connection specification of its definition
+type ConnectorConfigurationReport struct {
+	// ConnectorUid is the uid of the connector
+	ConnectorUid string
+	// ConnectorId is the id of the connector
+	ConnectorId string
+	// Owner of the connector, e.g., users/{uid}
+	Owner string
+	// ConnectorDefinitionName, e.g., connector-definitions/{id}, empty if the definition is unknown
+	ConnectorDefinitionName string
+	// Outcome is migrated or incompatible
+	Outcome string
+	// Migrations are the migrations which changed the configuration
+	Migrations []string
+	// Reason is the validation error of an incompatible configuration
+	Reason string
+}
+
+// CheckConnectorConfigurationsResponse represents a response for checking the connector configurations
+type CheckConnectorConfigurationsResponse struct {
+	Reports []*ConnectorConfigurationReport
+	// Applied is true if the migrations and states have been written
+	Applied bool
+}
+
+// SearchConnectorDefinitionsRequest represents a request to list the connector
+// definitions, extended with a search query
+type SearchConnectorDefinitionsRequest struct {
+	*connectorPB.ListConnectorDefinitionsRequest
+	// Q is a fuzzy search query over the title and the description of the
+	// definitions, which all its terms must match
+	Q string
+}
+
+// GetUsageReporterStatusResponse represents the status of the usage reporter,
+// which is restricted to the admins
+type GetUsageReporterStatusResponse struct {
+	// Enabled is unset if the usage reporting is disabled
+	Enabled bool
+	// LastSuccessTime is the time of the last report sent
+	LastSuccessTime *timestamppb.Timestamp
+	// LastErrorTime is the time of the last failure to send the reports
+	LastErrorTime *timestamppb.Timestamp
+	// LastError is the error of the last failure
+	LastError string
+	// QueuedReports is the number of reports spooled on disk waiting to be sent
+	QueuedReports int64
+	// DroppedReports is the number of reports dropped from the full spool since the start
+	DroppedReports int64
+}
+
+// GetUsageRequest represents a request for the execution usage of an owner
+type GetUsageRequest struct {
+	// Owner is the owner of the usage, e.g., users/{id}, only the admins can
+	// read the usage of another owner. The usage of the caller if not set.
+	Owner string
+}
+
+// ExecutionCounters represents the connector executions counted by the metering
+type ExecutionCounters struct {
+	Executions   int64
+	InputItems   int64
+	PayloadBytes int64
+}
+
+// ConnectorExecutionUsage represents the execution usage of a connector
+type ConnectorExecutionUsage struct {
+	// ConnectorUid is the uid of the connector, which may since be deleted
+	ConnectorUid string
+	// ConnectorDefinitionName, e.g., connector-definitions/{id}, empty if the definition is unknown
+	ConnectorDefinitionName string
+	Usage                   *ExecutionCounters
+}
+
+// WindowExecutionUsage represents the execution usage over a metering window
+type WindowExecutionUsage struct {
+	WindowStart *timestamppb.Timestamp
+	Usage       *ExecutionCounters
+}
+
+// GetUsageResponse represents the execution usage of an owner over the
+// current quota period against the limits of its plan
+type GetUsageResponse struct {
+	// Owner of the usage, e.g., users/{uid}
+	Owner string
+	// Plan is the quota plan of the owner
+	Plan        string
+	PeriodStart *timestamppb.Timestamp
+	PeriodEnd   *timestamppb.Timestamp
+	Usage       *ExecutionCounters
+	// Limits of the period, a limit of 0 is unlimited
+	Limits *ExecutionCounters
+	// Connectors are the usage per connector
+	Connectors []*ConnectorExecutionUsage
+	// Windows are the usage per metering window with executions, in order
+	Windows []*WindowExecutionUsage
+}
+
+// ListAuditEventsRequest represents a request for the audit events, only the
+// admins can list them
+type ListAuditEventsRequest struct {
+	// StartTime and EndTime bound the creation time of the events, the end
+	// time is excluded, unbounded if not set
+	StartTime *timestamppb.Timestamp
+	EndTime   *timestamppb.Timestamp
+	// Actor is the caller of the audited operations, e.g., users/{id}
+	Actor string
+	// ConnectorUid is the uid of the audited connector, which is kept across renames
+	ConnectorUid string
+	PageSize     int64
+	PageToken    string
+}
+
+// AuditEvent represents a connector change or execution
+type AuditEvent struct {
+	Uid        string
+	CreateTime *timestamppb.Timestamp
+	EventName  string
+	// Actor and Owner are the permalinks of the caller and of the connector owner
+	Actor        string
+	Owner        string
+	ConnectorUid string
+	ConnectorId  string
+	RequestId    string
+	TraceId      string
+	// Outcome is success or failure, Message the error of a failure
+	Outcome string
+	Message string
+	// Diff maps the changed fields to their before and after values, the
+	// credential fields are redacted
+	Diff map[string]*audit.Change
+}
+
+// ListAuditEventsResponse represents a page of audit events, from the newest
+type ListAuditEventsResponse struct {
+	Events        []*AuditEvent
+	NextPageToken string
+}
+
+// ReadinessDependenciesResponse represents the statuses of the dependencies
+// checked by the readiness
+type ReadinessDependenciesResponse struct {
+	// Status is SERVING_STATUS_SERVING if all the dependencies are serving
+	Status       string
+	Dependencies []*DependencyStatus
+}
+
+// DependencyStatus represents the result of the last check of a dependency
+type DependencyStatus struct {
+	Name   string
+	Status string
+	// Message is the error of a failed check
+	Message   string
+	CheckTime *timestamppb.Timestamp
+	Latency   *durationpb.Duration
+}
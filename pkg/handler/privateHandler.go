@@ -79,6 +79,11 @@ func (h *PrivateHandler) ListConnectorsAdmin(ctx context.Context, req *connector
 		return resp, err
 	}
 
+	policies, err := h.service.ListConnectorDefinitionPolicies(ctx)
+	if err != nil {
+		return resp, err
+	}
+
 	var pbConnectors []*connectorPB.Connector
 	for idx := range dbConnectors {
 		dbConnDef, err := h.connectors.GetConnectorDefinitionByUid(dbConnectors[idx].ConnectorDefinitionUID)
@@ -91,6 +96,7 @@ func (h *PrivateHandler) ListConnectorsAdmin(ctx context.Context, req *connector
 			dbConnectors[idx].Owner,
 			fmt.Sprintf("%s/%s", connDefColID, dbConnDef.GetId()),
 		)
+		setPolicyTombstone(pbConnector, dbConnectors[idx].ConnectorDefinitionUID, policies)
 		if !isBasicView {
 			pbConnector.ConnectorDefinition = dbConnDef
 		}
@@ -160,12 +166,18 @@ func (h *PrivateHandler) LookUpConnectorAdmin(ctx context.Context, req *connecto
 		return resp, err
 	}
 
+	policies, err := h.service.ListConnectorDefinitionPolicies(ctx)
+	if err != nil {
+		return resp, err
+	}
+
 	pbConnector := DBToPBConnector(
 		ctx,
 		dbConnector,
 		dbConnector.Owner,
 		fmt.Sprintf("%s/%s", connDefColID, dbConnDef.GetId()),
 	)
+	setPolicyTombstone(pbConnector, dbConnector.ConnectorDefinitionUID, policies)
 
 	if !isBasicView {
 		connector.MaskCredentialFields(h.connectors, dbConnDef.Id, pbConnector.Configuration)
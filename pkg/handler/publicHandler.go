@@ -3,8 +3,10 @@ package handler
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -14,30 +16,40 @@ import (
 	"go.einride.tech/aip/filtering"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
-	"gorm.io/datatypes"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	fieldmask_utils "github.com/mennanov/fieldmask-utils"
 	proto "google.golang.org/protobuf/proto"
 
+	"github.com/instill-ai/connector-backend/config"
 	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/audit"
+	"github.com/instill-ai/connector-backend/pkg/bundle"
 	"github.com/instill-ai/connector-backend/pkg/connector"
+	"github.com/instill-ai/connector-backend/pkg/constant"
 	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/health"
 	"github.com/instill-ai/connector-backend/pkg/logger"
 	"github.com/instill-ai/connector-backend/pkg/repository"
 	"github.com/instill-ai/connector-backend/pkg/service"
+	"github.com/instill-ai/connector-backend/pkg/usage"
 	"github.com/instill-ai/x/checkfield"
 	"github.com/instill-ai/x/paginate"
 	"github.com/instill-ai/x/sterr"
 
 	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+	connectorExtPB "github.com/instill-ai/connector-backend/pkg/protogen/vdp/connector/v1alpha"
 	connectorBase "github.com/instill-ai/connector/pkg/base"
 	connectorConfigLoader "github.com/instill-ai/connector/pkg/configLoader"
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
 	healthcheckPB "github.com/instill-ai/protogen-go/common/healthcheck/v1alpha"
 	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
 )
@@ -46,12 +58,17 @@ var tracer = otel.Tracer("connector-backend.public-handler.tracer")
 
 type PublicHandler struct {
 	connectorPB.UnimplementedConnectorPublicServiceServer
+	connectorExtPB.UnimplementedConnectorCloneServiceServer
+	connectorExtPB.UnimplementedConnectorBatchServiceServer
 	service    service.Service
 	connectors connectorBase.IConnector
+	usage      usage.Usage
+	audit      audit.Sink
+	health     *health.Checker
 }
 
 // NewPublicHandler initiates a handler instance
-func NewPublicHandler(ctx context.Context, s service.Service) connectorPB.ConnectorPublicServiceServer {
+func NewPublicHandler(ctx context.Context, s service.Service) *PublicHandler {
 
 	logger, _ := logger.GetZapLogger(ctx)
 	return &PublicHandler{
@@ -70,6 +87,21 @@ func (h *PublicHandler) SetService(s service.Service) {
 	h.service = s
 }
 
+// SetUsage sets the usage reporter, nil if the usage reporting is disabled
+func (h *PublicHandler) SetUsage(u usage.Usage) {
+	h.usage = u
+}
+
+// SetAuditSink sets the sink of the audit events, nil if the audit is disabled
+func (h *PublicHandler) SetAuditSink(a audit.Sink) {
+	h.audit = a
+}
+
+// SetHealth sets the checker of the dependencies of the readiness
+func (h *PublicHandler) SetHealth(c *health.Checker) {
+	h.health = c
+}
+
 func (h *PublicHandler) Liveness(ctx context.Context, in *connectorPB.LivenessRequest) (*connectorPB.LivenessResponse, error) {
 	return &connectorPB.LivenessResponse{
 		HealthCheckResponse: &healthcheckPB.HealthCheckResponse{
@@ -78,15 +110,60 @@ func (h *PublicHandler) Liveness(ctx context.Context, in *connectorPB.LivenessRe
 	}, nil
 }
 
+// Readiness is serving if all the dependencies are serving
 func (h *PublicHandler) Readiness(ctx context.Context, in *connectorPB.ReadinessRequest) (*connectorPB.ReadinessResponse, error) {
+	status := healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING
+	if h.health != nil {
+		if serving, _ := h.health.Check(ctx); !serving {
+			status = healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING
+		}
+	}
 	return &connectorPB.ReadinessResponse{
 		HealthCheckResponse: &healthcheckPB.HealthCheckResponse{
-			Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING,
+			Status: status,
 		},
 	}, nil
 }
 
+// ReadinessDependencies returns the statuses of the dependencies checked by
+// the readiness
+func (h *PublicHandler) ReadinessDependencies(ctx context.Context) (*ReadinessDependenciesResponse, error) {
+	resp := &ReadinessDependenciesResponse{
+		Status: healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING.String(),
+	}
+	if h.health == nil {
+		return resp, nil
+	}
+	serving, statuses := h.health.Check(ctx)
+	if !serving {
+		resp.Status = healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING.String()
+	}
+	for _, s := range statuses {
+		dep := &DependencyStatus{
+			Name:      s.Name,
+			Status:    healthcheckPB.HealthCheckResponse_SERVING_STATUS_SERVING.String(),
+			Message:   s.Message,
+			CheckTime: timestamppb.New(s.CheckTime),
+			Latency:   durationpb.New(s.Latency),
+		}
+		if !s.Serving {
+			dep.Status = healthcheckPB.HealthCheckResponse_SERVING_STATUS_NOT_SERVING.String()
+		}
+		resp.Dependencies = append(resp.Dependencies, dep)
+	}
+	return resp, nil
+}
+
 func (h *PublicHandler) ListConnectorDefinitions(ctx context.Context, req *connectorPB.ListConnectorDefinitionsRequest) (resp *connectorPB.ListConnectorDefinitionsResponse, err error) {
+	return h.SearchConnectorDefinitions(ctx, &SearchConnectorDefinitionsRequest{
+		ListConnectorDefinitionsRequest: req,
+	})
+}
+
+// SearchConnectorDefinitions lists the connector definitions matching the
+// filter and the search query, ordered by id so that the page tokens remain
+// valid as definitions are added or removed, or the server restarts
+func (h *PublicHandler) SearchConnectorDefinitions(ctx context.Context, req *SearchConnectorDefinitionsRequest) (resp *connectorPB.ListConnectorDefinitionsResponse, err error) {
 	ctx, span := tracer.Start(ctx, "ListConnectorDefinitions",
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
@@ -98,25 +175,33 @@ func (h *PublicHandler) ListConnectorDefinitions(ctx context.Context, req *conne
 	pageToken := req.GetPageToken()
 	isBasicView := (req.GetView() == connectorPB.View_VIEW_BASIC) || (req.GetView() == connectorPB.View_VIEW_UNSPECIFIED)
 
-	var connType connectorPB.ConnectorType
-	declarations, err := filtering.NewDeclarations([]filtering.DeclarationOption{
-		filtering.DeclareStandardFunctions(),
-		filtering.DeclareEnumIdent("connector_type", connType.Type()),
-	}...)
+	declarations, err := connectorDefinitionDeclarations()
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
-	filter, err := filtering.ParseFilter(req, declarations)
+	filter, err := filtering.ParseFilter(req.ListConnectorDefinitionsRequest, declarations)
 	if err != nil {
+		st, e := sterr.CreateErrorBadRequest(
+			fmt.Sprintf("[handler] list connector definitions error: %s", err.Error()),
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "filter",
+					Description: err.Error(),
+				},
+			},
+		)
+		if e != nil {
+			logger.Error(e.Error())
+		}
 		span.SetStatus(1, err.Error())
-		return resp, err
+		return resp, st.Err()
 	}
 
-	prevLastUid := ""
+	prevLastId := ""
 
 	if pageToken != "" {
-		_, prevLastUid, err = paginate.DecodeToken(pageToken)
+		_, prevLastId, err = paginate.DecodeToken(pageToken)
 		if err != nil {
 			st, err := sterr.CreateErrorBadRequest(
 				fmt.Sprintf("[db] list connector error: %s", err.Error()),
@@ -140,56 +225,61 @@ func (h *PublicHandler) ListConnectorDefinitions(ctx context.Context, req *conne
 		pageSize = repository.MaxPageSize
 	}
 
-	unfilteredDefs := h.connectors.ListConnectorDefinitions()
-	var defs []*connectorPB.ConnectorDefinition
-	if filter.CheckedExpr != nil {
-		trans := repository.NewTranspiler(filter)
-		expr, _ := trans.Transpile()
-		typeMap := map[string]bool{}
-		for idx := range expr.Vars {
-			if idx == 0 {
-				typeMap[string(expr.Vars[idx].(protoreflect.Name))] = true
-			} else {
-				typeMap[string(expr.Vars[idx].([]interface{})[0].(protoreflect.Name))] = true
-			}
-
+	policies, err := h.service.ListConnectorDefinitionPolicies(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	evaluator := repository.NewEvaluator(filter)
+	defs := []*connectorPB.ConnectorDefinition{}
+	for _, def := range h.visibleConnectorDefinitions(ctx, h.connectors.ListConnectorDefinitions(), policies) {
+		if req.Q != "" && !matchSearchQuery(def, req.Q) {
+			continue
 		}
-		for idx := range unfilteredDefs {
-			if _, ok := typeMap[unfilteredDefs[idx].ConnectorType.String()]; ok {
-				defs = append(defs, unfilteredDefs[idx])
-			}
+		policy, ok := policies[uuid.FromStringOrNil(def.Uid)]
+		match, err := evaluator.EvaluateFields(connectorDefinitionFields(def, ok && !policy.State.AcceptsConnectors()))
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return resp, status.Errorf(codes.InvalidArgument, "invalid filter: %s", err.Error())
+		}
+		if match {
+			defs = append(defs, def)
 		}
-
-	} else {
-		defs = unfilteredDefs
 	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Id < defs[j].Id
+	})
 
+	// The page starts after the last id of the previous page, which may
+	// have been removed since
 	startIdx := 0
-	lastUid := ""
-	for idx, def := range defs {
-		if def.Uid == prevLastUid {
-			startIdx = idx + 1
-			break
-		}
+	if prevLastId != "" {
+		startIdx = sort.Search(len(defs), func(i int) bool {
+			return defs[i].Id > prevLastId
+		})
 	}
+	lastId := ""
 
 	page := []*connectorPB.ConnectorDefinition{}
 	for i := 0; i < int(pageSize) && startIdx+i < len(defs); i++ {
 		def := proto.Clone(defs[startIdx+i]).(*connectorPB.ConnectorDefinition)
 		page = append(page, def)
-		lastUid = def.Uid
+		lastId = def.Id
 	}
 
 	nextPageToken := ""
 
 	if startIdx+len(page) < len(defs) {
-		nextPageToken = paginate.EncodeToken(time.Time{}, lastUid)
+		nextPageToken = paginate.EncodeToken(time.Time{}, lastId)
 	}
 	for _, def := range page {
 		def.Name = fmt.Sprintf("connector-definitions/%s", def.Id)
 		if isBasicView {
 			def.Spec = nil
 		}
+		if policy, ok := policies[uuid.FromStringOrNil(def.Uid)]; ok && !policy.State.AcceptsConnectors() {
+			def.Tombstone = true
+		}
 		def.VendorAttributes = nil
 		resp.ConnectorDefinitions = append(
 			resp.ConnectorDefinitions,
@@ -220,7 +310,7 @@ func (h *PublicHandler) GetConnectorDefinition(ctx context.Context, req *connect
 	}
 	isBasicView := (req.GetView() == connectorPB.View_VIEW_BASIC) || (req.GetView() == connectorPB.View_VIEW_UNSPECIFIED)
 
-	dbDef, err := h.connectors.GetConnectorDefinitionById(connID)
+	dbDef, policy, err := h.getConnectorDefinitionWithPolicy(ctx, connID)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
@@ -231,192 +321,170 @@ func (h *PublicHandler) GetConnectorDefinition(ctx context.Context, req *connect
 	}
 	resp.ConnectorDefinition.VendorAttributes = nil
 	resp.ConnectorDefinition.Name = fmt.Sprintf("connector-definitions/%s", resp.ConnectorDefinition.GetId())
+	if !policy.State.AcceptsConnectors() {
+		resp.ConnectorDefinition.Tombstone = true
+	}
+
+	// The deprecation message is returned in a header, as the definition has no field for it
+	if policy.State == datamodel.DefinitionPolicyDeprecated {
+		message := policy.Message
+		if message == "" {
+			message = "deprecated"
+		}
+		if err := grpc.SetHeader(ctx, metadata.Pairs(constant.HeaderDeprecationKey, message)); err != nil {
+			logger.Error(err.Error())
+		}
+	}
 
 	logger.Info("GetConnectorDefinition")
 	return resp, nil
 
 }
 
-func (h *PublicHandler) CreateConnector(ctx context.Context, req *connectorPB.CreateConnectorRequest) (resp *connectorPB.CreateConnectorResponse, err error) {
-
-	eventName := "CreateConnector"
-
-	ctx, span := tracer.Start(ctx, eventName,
-		trace.WithSpanKind(trace.SpanKindServer))
-	defer span.End()
-
-	logUUID, _ := uuid.NewV4()
+// getConnectorDefinitionWithPolicy returns a connector definition and its
+// policy, a hidden definition is not found unless the caller is an admin
+func (h *PublicHandler) getConnectorDefinitionWithPolicy(ctx context.Context, connDefID string) (*connectorPB.ConnectorDefinition, *datamodel.ConnectorDefinitionPolicy, error) {
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var connID string
-	var connDesc sql.NullString
-	var connConfig datatypes.JSON
-
-	var connDefUID uuid.UUID
-	var connDefRscName string
-
-	resp = &connectorPB.CreateConnectorResponse{}
+	dbDef, err := h.connectors.GetConnectorDefinitionById(connDefID)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Set all OUTPUT_ONLY fields to zero value on the requested payload
-	if err := checkfield.CheckCreateOutputOnlyFields(req.GetConnector(), outputOnlyFields); err != nil {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] create connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "OUTPUT_ONLY fields",
-					Description: err.Error(),
-				},
-			},
-		)
-		if err != nil {
-			logger.Error(err.Error())
-		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+	policy, err := h.service.GetConnectorDefinitionPolicy(ctx, uuid.FromStringOrNil(dbDef.GetUid()))
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Return error if REQUIRED fields are not provided in the requested payload
-	if err := checkfield.CheckRequiredFields(req.GetConnector(), append(createRequiredFields, immutableFields...)); err != nil {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] create connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "REQUIRED fields",
-					Description: err.Error(),
-				},
-			},
+	if policy.State == datamodel.DefinitionPolicyHidden && !h.isAdminCaller(ctx) {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			"[handler] get connector definition",
+			"connector-definitions",
+			fmt.Sprintf("id %s", connDefID),
+			"",
+			"Not found",
 		)
 		if err != nil {
 			logger.Error(err.Error())
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+		return nil, nil, st.Err()
 	}
 
-	// TODO
-	// Validate DestinationConnector JSON Schema
-	configLoader := connectorConfigLoader.InitJSONSchema(logger)
-	if err := connectorConfigLoader.ValidateJSONSchema(configLoader.ConnJSONSchema, req.GetConnector(), false); err != nil {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] create connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "connector",
-					Description: err.Error(),
-				},
-			},
-		)
-		if err != nil {
-			logger.Error(err.Error())
+	return dbDef, policy, nil
+}
+
+// visibleConnectorDefinitions filters out the hidden definitions unless the caller is an admin
+func (h *PublicHandler) visibleConnectorDefinitions(ctx context.Context, defs []*connectorPB.ConnectorDefinition, policies map[uuid.UUID]*datamodel.ConnectorDefinitionPolicy) []*connectorPB.ConnectorDefinition {
+
+	hidden := map[string]bool{}
+	for uid, policy := range policies {
+		if policy.State == datamodel.DefinitionPolicyHidden {
+			hidden[uid.String()] = true
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+	}
+	// The owner is only resolved when needed, so that listing the definitions
+	// does not require authentication
+	if len(hidden) == 0 || h.isAdminCaller(ctx) {
+		return defs
 	}
 
-	connID = req.GetConnector().GetId()
-	if len(connID) > 8 && connID[:8] == "instill-" {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] create connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "connector",
-					Description: "the id can not start with instill-",
-				},
-			},
-		)
-		if err != nil {
-			logger.Error(err.Error())
+	visible := make([]*connectorPB.ConnectorDefinition, 0, len(defs))
+	for _, def := range defs {
+		if !hidden[def.GetUid()] {
+			visible = append(visible, def)
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
 	}
+	return visible
+}
+
+// isAdminCaller reports whether the caller is one of the server admins, an
+// unauthenticated caller is not
+func (h *PublicHandler) isAdminCaller(ctx context.Context) bool {
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	return err == nil && service.IsAdmin(owner)
+}
 
-	connConfig, err = req.GetConnector().GetConfiguration().MarshalJSON()
+func (h *PublicHandler) ListConnectorDefinitionPolicies(ctx context.Context) (resp *ListConnectorDefinitionPoliciesResponse, err error) {
+
+	ctx, span := tracer.Start(ctx, "ListConnectorDefinitionPolicies",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	resp = &ListConnectorDefinitionPoliciesResponse{}
+
+	policies, err := h.service.ListConnectorDefinitionPolicies(ctx)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	connDesc = sql.NullString{
-		String: req.GetConnector().GetDescription(),
-		Valid:  len(req.GetConnector().GetDescription()) > 0,
+	isAdmin := h.isAdminCaller(ctx)
+	for _, def := range h.connectors.ListConnectorDefinitions() {
+		policy, ok := policies[uuid.FromStringOrNil(def.GetUid())]
+		if !ok || (policy.State == datamodel.DefinitionPolicyHidden && !isAdmin) {
+			continue
+		}
+		resp.Policies = append(resp.Policies, DBToPBConnectorDefinitionPolicy(policy))
 	}
 
-	connDefResp, err := h.GetConnectorDefinition(
-		ctx,
-		&connectorPB.GetConnectorDefinitionRequest{
-			Name: req.GetConnector().GetConnectorDefinitionName(),
-			View: connectorPB.View_VIEW_FULL.Enum(),
-		})
+	logger.Info("ListConnectorDefinitionPolicies")
+
+	return resp, nil
+}
+
+func (h *PublicHandler) GetConnectorDefinitionPolicy(ctx context.Context, req *GetConnectorDefinitionPolicyRequest) (resp *GetConnectorDefinitionPolicyResponse, err error) {
+
+	ctx, span := tracer.Start(ctx, "GetConnectorDefinitionPolicy",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	resp = &GetConnectorDefinitionPolicyResponse{}
+
+	connDefID, err := resource.GetRscNameID(req.Name)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	// Validate Connector configuration JSON Schema
-	// connSpec := connDefResp.GetConnectorDefinition().GetSpec().GetConnectionSpecification()
-	// b, err := protojson.Marshal(connSpec)
-	// if err != nil {
-	// 	st, err := sterr.CreateErrorResourceInfo(
-	// 		codes.Internal,
-	// 		"[handler] create connector error",
-	// 		"connector-definitions",
-	// 		fmt.Sprintf("uid %s", connDefResp.ConnectorDefinition.GetUid()),
-	// 		"",
-	// 		err.Error(),
-	// 	)
-	// 	if err != nil {
-	// 		logger.Error(err.Error())
-	// 	}
-	// 	span.SetStatus(1, st.Err().Error())
-	// 	return resp, st.Err()
-	// }
-
-	// if err := connectorConfigLoader.ValidateJSONSchemaString(string(b), req.GetConnector().GetConfiguration()); err != nil {
-	// 	st, err := sterr.CreateErrorBadRequest(
-	// 		"[handler] create connector error",
-	// 		[]*errdetails.BadRequest_FieldViolation{
-	// 			{
-	// 				Field:       "connector.configuration",
-	// 				Description: err.Error(),
-	// 			},
-	// 		},
-	// 	)
-	// 	if err != nil {
-	// 		logger.Error(err.Error())
-	// 	}
-	// 	span.SetStatus(1, st.Err().Error())
-	// 	return resp, st.Err()
-	// }
-
-	connDefUID, err = uuid.FromString(connDefResp.ConnectorDefinition.GetUid())
+	_, policy, err := h.getConnectorDefinitionWithPolicy(ctx, connDefID)
 	if err != nil {
-		st, err := sterr.CreateErrorResourceInfo(
-			codes.Internal,
-			"[handler] create connector error",
-			"connector-definitions",
-			fmt.Sprintf("uid %s", connDefResp.ConnectorDefinition.GetUid()),
-			"",
-			err.Error(),
-		)
-		if err != nil {
-			logger.Error(err.Error())
-		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+		span.SetStatus(1, err.Error())
+		return resp, err
 	}
+	resp.Policy = DBToPBConnectorDefinitionPolicy(policy)
 
-	connDefRscName = fmt.Sprintf("connector-definitions/%s", connDefResp.ConnectorDefinition.GetId())
+	logger.Info("GetConnectorDefinitionPolicy")
 
-	// Return error if resource ID does not follow RFC-1034
-	if err := checkfield.CheckResourceID(connID); err != nil {
+	return resp, nil
+}
+
+func (h *PublicHandler) UpdateConnectorDefinitionPolicy(ctx context.Context, req *UpdateConnectorDefinitionPolicyRequest) (resp *UpdateConnectorDefinitionPolicyResponse, err error) {
+
+	eventName := "UpdateConnectorDefinitionPolicy"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	resp = &UpdateConnectorDefinitionPolicyResponse{}
+
+	if req.Policy == nil {
 		st, err := sterr.CreateErrorBadRequest(
-			"[handler] create connector error",
+			"[handler] update connector definition policy error",
 			[]*errdetails.BadRequest_FieldViolation{
 				{
-					Field:       "id",
-					Description: err.Error(),
+					Field:       "policy",
+					Description: "policy is required",
 				},
 			},
 		)
@@ -427,177 +495,294 @@ func (h *PublicHandler) CreateConnector(ctx context.Context, req *connectorPB.Cr
 		return resp, st.Err()
 	}
 
+	connDefID, err := resource.GetRscNameID(req.Policy.Name)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
 	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	dbConnector := &datamodel.Connector{
-		ID:                     connID,
-		Owner:                  owner.GetName(),
-		ConnectorDefinitionUID: connDefUID,
-		Tombstone:              false,
-		Configuration:          connConfig,
-		ConnectorType:          datamodel.ConnectorType(connDefResp.ConnectorDefinition.GetConnectorType()),
-		Description:            connDesc,
-		Visibility:             datamodel.ConnectorVisibility(req.Connector.Visibility),
-		Task:                   "TASK_UNSPECIFIED", // TODO: refactor this
+	dbDef, err := h.connectors.GetConnectorDefinitionById(connDefID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
 	}
 
-	dbConnector, err = h.service.CreateConnector(ctx, owner, dbConnector)
+	policy, err := h.service.UpdateConnectorDefinitionPolicy(ctx, owner, &datamodel.ConnectorDefinitionPolicy{
+		ConnectorDefinitionUID: uuid.FromStringOrNil(dbDef.GetUid()),
+		State:                  datamodel.DefinitionPolicyState(req.Policy.State),
+		Message:                req.Policy.Message,
+	})
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
+	resp.Policy = DBToPBConnectorDefinitionPolicy(policy)
 
 	logger.Info(string(custom_otel.NewLogMessage(
 		span,
 		logUUID.String(),
 		owner,
 		eventName,
-		custom_otel.SetEventResource(dbConnector),
+		custom_otel.SetEventResource(policy),
 	)))
 
-	pbConnector := DBToPBConnector(
-		ctx,
-		dbConnector,
-		service.GenOwnerPermalink(owner),
-		connDefRscName)
+	return resp, nil
+}
 
-	connector.MaskCredentialFields(h.connectors, connDefResp.ConnectorDefinition.Id, pbConnector.Configuration)
-	resp.Connector = pbConnector
+func (h *PublicHandler) ListBuiltinConnectorDefinitions(ctx context.Context) (resp *ListBuiltinConnectorDefinitionsResponse, err error) {
+
+	ctx, span := tracer.Start(ctx, "ListBuiltinConnectorDefinitions",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	resp = &ListBuiltinConnectorDefinitionsResponse{}
 
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
+		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	if err := grpc.SetHeader(ctx, metadata.Pairs("x-http-code", strconv.Itoa(http.StatusCreated))); err != nil {
+	resp.Specs, err = h.service.ListBuiltinConnectorDefinitions(ctx, owner)
+	if err != nil {
+		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
+	logger.Info("ListBuiltinConnectorDefinitions")
+
 	return resp, nil
 }
 
-func (h *PublicHandler) ListConnectors(ctx context.Context, req *connectorPB.ListConnectorsRequest) (resp *connectorPB.ListConnectorsResponse, err error) {
+func (h *PublicHandler) CreateBuiltinConnectorDefinition(ctx context.Context, req *CreateBuiltinConnectorDefinitionRequest) (resp *CreateBuiltinConnectorDefinitionResponse, err error) {
 
-	eventName := "ListConnectors"
+	eventName := "CreateBuiltinConnectorDefinition"
 
 	ctx, span := tracer.Start(ctx, eventName,
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
+	logUUID := logger.RequestID(ctx)
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var pageSize int64
-	var pageToken string
-	var isBasicView bool
-
-	var connDefColID string
+	resp = &CreateBuiltinConnectorDefinitionResponse{}
 
-	resp = &connectorPB.ListConnectorsResponse{}
-	pageSize = req.GetPageSize()
-	pageToken = req.GetPageToken()
-	isBasicView = (req.GetView() == connectorPB.View_VIEW_BASIC) || (req.GetView() == connectorPB.View_VIEW_UNSPECIFIED)
-	connDefColID = "connector-definitions"
+	if req.Spec == nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] create builtin connector definition error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "spec",
+					Description: "spec is required",
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
 
-	var connType connectorPB.ConnectorType
-	declarations, err := filtering.NewDeclarations([]filtering.DeclarationOption{
-		filtering.DeclareStandardFunctions(),
-		filtering.DeclareEnumIdent("connector_type", connType.Type()),
-	}...)
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
-	filter, err := filtering.ParseFilter(req, declarations)
+
+	resp.ConnectorDefinition, err = h.service.RegisterBuiltinConnectorDefinition(ctx, owner, req.Spec)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(resp.ConnectorDefinition),
+	)))
+
+	return resp, nil
+}
+
+func (h *PublicHandler) DeleteBuiltinConnectorDefinition(ctx context.Context, req *DeleteBuiltinConnectorDefinitionRequest) (err error) {
+
+	eventName := "DeleteBuiltinConnectorDefinition"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	connDefID, err := resource.GetRscNameID(req.Name)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return err
+	}
+
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return err
+	}
+
+	if err := h.service.DeleteBuiltinConnectorDefinition(ctx, owner, connDefID); err != nil {
+		span.SetStatus(1, err.Error())
+		return err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(req.Name),
+	)))
+
+	return nil
+}
+
+func (h *PublicHandler) CheckConnectorConfigurations(ctx context.Context, req *CheckConnectorConfigurationsRequest) (resp *CheckConnectorConfigurationsResponse, err error) {
+
+	eventName := "CheckConnectorConfigurations"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	resp = &CheckConnectorConfigurationsResponse{}
+
 	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	dbConnectors, totalSize, nextPageToken, err := h.service.ListConnectors(ctx, owner, pageSize, pageToken, isBasicView, filter)
+	reports, err := h.service.CheckConnectorConfigurationsAdmin(ctx, owner, req.DryRun)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	var pbConnectors []*connectorPB.Connector
-	for idx := range dbConnectors {
-		dbConnDef, err := h.connectors.GetConnectorDefinitionByUid(dbConnectors[idx].ConnectorDefinitionUID)
-		if err != nil {
-			span.SetStatus(1, err.Error())
-			return resp, err
-		}
-		pbConnector := DBToPBConnector(
-			ctx,
-			dbConnectors[idx],
-			dbConnectors[idx].Owner,
-			fmt.Sprintf("%s/%s", connDefColID, dbConnDef.GetId()),
-		)
-		if !isBasicView {
-			pbConnector.ConnectorDefinition = dbConnDef
+	for _, report := range reports {
+		connDefName := ""
+		if report.ConnectorDefinitionID != "" {
+			connDefName = fmt.Sprintf("connector-definitions/%s", report.ConnectorDefinitionID)
 		}
-		connector.MaskCredentialFields(h.connectors, dbConnDef.GetId(), pbConnector.Configuration)
-		pbConnectors = append(
-			pbConnectors,
-			pbConnector,
-		)
+		resp.Reports = append(resp.Reports, &ConnectorConfigurationReport{
+			ConnectorUid:            report.ConnectorUID.String(),
+			ConnectorId:             report.ConnectorID,
+			Owner:                   report.Owner,
+			ConnectorDefinitionName: connDefName,
+			Outcome:                 string(report.Outcome),
+			Migrations:              report.Migrations,
+			Reason:                  report.Reason,
+		})
 	}
+	resp.Applied = !req.DryRun
 
 	logger.Info(string(custom_otel.NewLogMessage(
 		span,
 		logUUID.String(),
 		owner,
 		eventName,
+		custom_otel.SetEventResult(fmt.Sprintf("%d connectors reported", len(resp.Reports))),
 	)))
 
-	resp.Connectors = pbConnectors
-	resp.NextPageToken = nextPageToken
-	resp.TotalSize = totalSize
-
 	return resp, nil
-
-}
-
-func (h *PublicHandler) GetConnector(ctx context.Context, req *connectorPB.GetConnectorRequest) (resp *connectorPB.GetConnectorResponse, err error) {
-	return h.getConnector(ctx, req, true)
 }
 
-func (h *PublicHandler) getConnector(ctx context.Context, req *connectorPB.GetConnectorRequest, credentialMask bool) (resp *connectorPB.GetConnectorResponse, err error) {
+func (h *PublicHandler) GetUsageReporterStatus(ctx context.Context) (resp *GetUsageReporterStatusResponse, err error) {
 
-	eventName := "GetConnector"
+	eventName := "GetUsageReporterStatus"
 
 	ctx, span := tracer.Start(ctx, eventName,
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
+	logUUID := logger.RequestID(ctx)
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var isBasicView bool
-
-	var connID string
-
-	var connDefColID string
+	resp = &GetUsageReporterStatusResponse{}
 
-	resp = &connectorPB.GetConnectorResponse{}
-	if connID, err = resource.GetRscNameID(req.GetName()); err != nil {
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
+	if !service.IsAdmin(owner) {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.PermissionDenied,
+			"[handler] get usage reporter status",
+			"usage-reporter",
+			"",
+			service.GenOwnerPermalink(owner),
+			"Permission denied",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
 
-	isBasicView = (req.GetView() == connectorPB.View_VIEW_BASIC) || (req.GetView() == connectorPB.View_VIEW_UNSPECIFIED)
-	connDefColID = "connector-definitions"
+	if h.usage != nil {
+		status := h.usage.GetReporterStatus()
+		resp.Enabled = config.Current().Server.Usage.Enabled
+		if !status.LastSuccessTime.IsZero() {
+			resp.LastSuccessTime = timestamppb.New(status.LastSuccessTime)
+		}
+		if !status.LastErrorTime.IsZero() {
+			resp.LastErrorTime = timestamppb.New(status.LastErrorTime)
+		}
+		resp.LastError = status.LastError
+		resp.QueuedReports = status.QueuedReports
+		resp.DroppedReports = status.DroppedReports
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResult(fmt.Sprintf("%d reports queued", resp.QueuedReports)),
+	)))
+
+	return resp, nil
+}
+
+func (h *PublicHandler) GetUsage(ctx context.Context, req *GetUsageRequest) (resp *GetUsageResponse, err error) {
+
+	eventName := "GetUsage"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	resp = &GetUsageResponse{}
 
 	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
@@ -605,107 +790,1345 @@ func (h *PublicHandler) getConnector(ctx context.Context, req *connectorPB.GetCo
 		return resp, err
 	}
 
-	dbConnector, err := h.service.GetConnectorByID(ctx, connID, owner, isBasicView)
+	target := owner
+	if req.Owner != "" && req.Owner != owner.GetName() {
+		target, err = resource.GetOwnerByName(ctx, h.service.GetMgmtPrivateServiceClient(), req.Owner)
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return resp, err
+		}
+	}
+
+	usage, err := h.service.GetExecutionUsage(ctx, owner, target)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	dbConnDef, err := h.connectors.GetConnectorDefinitionByUid(dbConnector.ConnectorDefinitionUID)
+	counters := func(u service.ExecutionUsage) *ExecutionCounters {
+		return &ExecutionCounters{
+			Executions:   u.Executions,
+			InputItems:   u.InputItems,
+			PayloadBytes: u.PayloadBytes,
+		}
+	}
+	resp.Owner = usage.Owner
+	resp.Plan = usage.Quota.Plan
+	resp.PeriodStart = timestamppb.New(usage.PeriodStart)
+	resp.PeriodEnd = timestamppb.New(usage.PeriodEnd)
+	resp.Usage = counters(usage.Total)
+	resp.Limits = counters(usage.Quota.Limits)
+	resp.Connectors = []*ConnectorExecutionUsage{}
+	for _, c := range usage.Connectors {
+		connDefName := ""
+		if connDef, err := h.connectors.GetConnectorDefinitionByUid(c.ConnectorDefinitionUID); err == nil {
+			connDefName = fmt.Sprintf("connector-definitions/%s", connDef.GetId())
+		}
+		resp.Connectors = append(resp.Connectors, &ConnectorExecutionUsage{
+			ConnectorUid:            c.ConnectorUID.String(),
+			ConnectorDefinitionName: connDefName,
+			Usage:                   counters(c.ExecutionUsage),
+		})
+	}
+	resp.Windows = []*WindowExecutionUsage{}
+	for _, w := range usage.Windows {
+		resp.Windows = append(resp.Windows, &WindowExecutionUsage{
+			WindowStart: timestamppb.New(w.WindowStart),
+			Usage:       counters(w.ExecutionUsage),
+		})
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResult(fmt.Sprintf("%d executions of %s", resp.Usage.Executions, resp.Owner)),
+	)))
+
+	return resp, nil
+}
+
+func (h *PublicHandler) ListAuditEvents(ctx context.Context, req *ListAuditEventsRequest) (resp *ListAuditEventsResponse, err error) {
+
+	eventName := "ListAuditEvents"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
 
+	logUUID := logger.RequestID(ctx)
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	resp = &ListAuditEventsResponse{}
+
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	resp.Connector = DBToPBConnector(
-		ctx,
-		dbConnector,
-		dbConnector.Owner,
-		fmt.Sprintf("%s/%s", connDefColID, dbConnDef.GetId()),
-	)
+	filter := repository.AuditEventFilter{}
+	if req.StartTime != nil {
+		filter.StartTime = req.StartTime.AsTime()
+	}
+	if req.EndTime != nil {
+		filter.EndTime = req.EndTime.AsTime()
+	}
+	if req.ConnectorUid != "" {
+		if filter.ConnectorUID, err = uuid.FromString(req.ConnectorUid); err != nil {
+			st, err := sterr.CreateErrorBadRequest(
+				"[handler] list audit events error",
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "connector_uid",
+						Description: err.Error(),
+					},
+				},
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			span.SetStatus(1, st.Err().Error())
+			return resp, st.Err()
+		}
+	}
+	// The actors are stored by permalink, the admins only know their names
+	if req.Actor != "" {
+		actor, err := resource.GetOwnerByName(ctx, h.service.GetMgmtPrivateServiceClient(), req.Actor)
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return resp, err
+		}
+		filter.Actor = service.GenOwnerPermalink(actor)
+	}
 
-	if credentialMask {
-		connector.MaskCredentialFields(h.connectors, dbConnDef.GetId(), resp.Connector.Configuration)
+	events, nextPageToken, err := h.service.ListAuditEvents(ctx, owner, filter, req.PageSize, req.PageToken)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
 	}
 
-	if !isBasicView {
-		resp.Connector.ConnectorDefinition = dbConnDef
+	resp.Events = make([]*AuditEvent, len(events))
+	for idx, e := range events {
+		event := &AuditEvent{
+			Uid:         e.UID.String(),
+			CreateTime:  timestamppb.New(e.CreateTime),
+			EventName:   e.EventName,
+			Actor:       e.Actor,
+			Owner:       e.Owner,
+			ConnectorId: e.ConnectorID,
+			RequestId:   e.RequestID,
+			TraceId:     e.TraceID,
+			Outcome:     string(e.Outcome),
+			Message:     e.Message,
+		}
+		if e.ConnectorUID.Valid {
+			event.ConnectorUid = e.ConnectorUID.UUID.String()
+		}
+		if e.Diff != nil {
+			if err := json.Unmarshal(e.Diff, &event.Diff); err != nil {
+				logger.Error(fmt.Sprintf("audit event %s: %s", event.Uid, err.Error()))
+			}
+		}
+		resp.Events[idx] = event
 	}
+	resp.NextPageToken = nextPageToken
 
 	logger.Info(string(custom_otel.NewLogMessage(
 		span,
 		logUUID.String(),
 		owner,
 		eventName,
-		custom_otel.SetEventResource(dbConnector),
+		custom_otel.SetEventResult(fmt.Sprintf("%d audit events", len(resp.Events))),
 	)))
 
 	return resp, nil
 }
 
-func (h *PublicHandler) UpdateConnector(ctx context.Context, req *connectorPB.UpdateConnectorRequest) (resp *connectorPB.UpdateConnectorResponse, err error) {
+func (h *PublicHandler) CreateConnector(ctx context.Context, req *connectorPB.CreateConnectorRequest) (resp *connectorPB.CreateConnectorResponse, err error) {
 
-	eventName := "UpdateConnector"
+	eventName := "CreateConnector"
 
 	ctx, span := tracer.Start(ctx, eventName,
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
+	logUUID := logger.RequestID(ctx)
+
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() { h.writeAudit(ctx, rec, err) }()
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var mask fieldmask_utils.Mask
+	resp = &connectorPB.CreateConnectorResponse{}
 
-	var connID string
+	dbConnector, connDef, err := h.validateCreateConnector(ctx, req.GetConnector())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
 
-	var connDefRscName string
+	connDefRscName := fmt.Sprintf("connector-definitions/%s", connDef.GetId())
 
-	owner, ownerErr := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setActor(owner)
 
-	resp = &connectorPB.UpdateConnectorResponse{}
-	if ownerErr != nil {
-		span.SetStatus(1, ownerErr.Error())
-		return resp, ownerErr
+	dbConnector.Owner = owner.GetName()
+
+	dbConnector, err = h.service.CreateConnector(ctx, owner, dbConnector)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
 	}
+	rec.setAfter(dbConnector)
 
-	pbConnectorReq := req.GetConnector()
-	pbUpdateMask := req.GetUpdateMask()
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbConnector),
+	)))
 
-	// configuration filed is type google.protobuf.Struct, which needs to be updated as a whole
-	for idx, path := range pbUpdateMask.Paths {
-		if strings.Contains(path, "configuration") {
-			pbUpdateMask.Paths[idx] = "configuration"
-		}
+	pbConnector := DBToPBConnector(
+		ctx,
+		dbConnector,
+		service.GenOwnerPermalink(owner),
+		connDefRscName)
+
+	connector.MaskCredentialFields(h.connectors, connDef.GetId(), pbConnector.Configuration)
+	resp.Connector = pbConnector
+
+	if err != nil {
+		return resp, err
 	}
 
-	if !pbUpdateMask.IsValid(req.GetConnector()) {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] update connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "update_mask",
-					Description: err.Error(),
-				},
-			},
-		)
-		if err != nil {
-			logger.Error(err.Error())
-		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
-	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs("x-http-code", strconv.Itoa(http.StatusCreated))); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// validateCreateConnector checks a connector of a create request and converts
+// it to the data model, the owner is left to the caller
+func (h *PublicHandler) validateCreateConnector(ctx context.Context, pbConnector *connectorPB.Connector) (*datamodel.Connector, *connectorPB.ConnectorDefinition, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	// Set all OUTPUT_ONLY fields to zero value on the requested payload
+	if err := checkfield.CheckCreateOutputOnlyFields(pbConnector, outputOnlyFields); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] create connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "OUTPUT_ONLY fields",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, nil, st.Err()
+	}
+
+	// Return error if REQUIRED fields are not provided in the requested payload
+	if err := checkfield.CheckRequiredFields(pbConnector, append(createRequiredFields, immutableFields...)); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] create connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "REQUIRED fields",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, nil, st.Err()
+	}
+
+	// TODO
+	// Validate DestinationConnector JSON Schema
+	configLoader := connectorConfigLoader.InitJSONSchema(logger)
+	if err := connectorConfigLoader.ValidateJSONSchema(configLoader.ConnJSONSchema, pbConnector, false); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] create connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "connector",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, nil, st.Err()
+	}
+
+	connID := pbConnector.GetId()
+	if len(connID) > 8 && connID[:8] == "instill-" {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] create connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "connector",
+					Description: "the id can not start with instill-",
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, nil, st.Err()
+	}
+
+	connConfig, err := pbConnector.GetConfiguration().MarshalJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	connDesc := sql.NullString{
+		String: pbConnector.GetDescription(),
+		Valid:  len(pbConnector.GetDescription()) > 0,
+	}
+
+	connDefResp, err := h.GetConnectorDefinition(
+		ctx,
+		&connectorPB.GetConnectorDefinitionRequest{
+			Name: pbConnector.GetConnectorDefinitionName(),
+			View: connectorPB.View_VIEW_FULL.Enum(),
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The configuration is validated against the connection specification by the service
+
+	connDefUID, err := uuid.FromString(connDefResp.ConnectorDefinition.GetUid())
+	if err != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			"[handler] create connector error",
+			"connector-definitions",
+			fmt.Sprintf("uid %s", connDefResp.ConnectorDefinition.GetUid()),
+			"",
+			err.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, nil, st.Err()
+	}
+
+	// Return error if resource ID does not follow RFC-1034
+	if err := checkfield.CheckResourceID(connID); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] create connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "id",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, nil, st.Err()
+	}
+
+	return &datamodel.Connector{
+		ID:                     connID,
+		ConnectorDefinitionUID: connDefUID,
+		Tombstone:              false,
+		Configuration:          connConfig,
+		ConnectorType:          datamodel.ConnectorType(connDefResp.ConnectorDefinition.GetConnectorType()),
+		Description:            connDesc,
+		Visibility:             datamodel.ConnectorVisibility(pbConnector.Visibility),
+		Task:                   "TASK_UNSPECIFIED", // TODO: refactor this
+	}, connDefResp.GetConnectorDefinition(), nil
+}
+
+func (h *PublicHandler) ListConnectors(ctx context.Context, req *connectorPB.ListConnectorsRequest) (resp *connectorPB.ListConnectorsResponse, err error) {
+
+	eventName := "ListConnectors"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var pageSize int64
+	var pageToken string
+	var isBasicView bool
+
+	var connDefColID string
+
+	resp = &connectorPB.ListConnectorsResponse{}
+	pageSize = req.GetPageSize()
+	pageToken = req.GetPageToken()
+	isBasicView = (req.GetView() == connectorPB.View_VIEW_BASIC) || (req.GetView() == connectorPB.View_VIEW_UNSPECIFIED)
+	connDefColID = "connector-definitions"
+
+	var connType connectorPB.ConnectorType
+	declarations, err := filtering.NewDeclarations([]filtering.DeclarationOption{
+		filtering.DeclareStandardFunctions(),
+		filtering.DeclareEnumIdent("connector_type", connType.Type()),
+	}...)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	filter, err := filtering.ParseFilter(req, declarations)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	dbConnectors, totalSize, nextPageToken, err := h.service.ListConnectors(ctx, owner, pageSize, pageToken, isBasicView, filter)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	policies, err := h.service.ListConnectorDefinitionPolicies(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	var pbConnectors []*connectorPB.Connector
+	for idx := range dbConnectors {
+		dbConnDef, err := h.connectors.GetConnectorDefinitionByUid(dbConnectors[idx].ConnectorDefinitionUID)
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return resp, err
+		}
+		pbConnector := DBToPBConnector(
+			ctx,
+			dbConnectors[idx],
+			dbConnectors[idx].Owner,
+			fmt.Sprintf("%s/%s", connDefColID, dbConnDef.GetId()),
+		)
+		setPolicyTombstone(pbConnector, dbConnectors[idx].ConnectorDefinitionUID, policies)
+		if !isBasicView {
+			pbConnector.ConnectorDefinition = dbConnDef
+		}
+		connector.MaskCredentialFields(h.connectors, dbConnDef.GetId(), pbConnector.Configuration)
+		pbConnectors = append(
+			pbConnectors,
+			pbConnector,
+		)
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+	)))
+
+	resp.Connectors = pbConnectors
+	resp.NextPageToken = nextPageToken
+	resp.TotalSize = totalSize
+
+	return resp, nil
+
+}
+
+func (h *PublicHandler) GetConnector(ctx context.Context, req *connectorPB.GetConnectorRequest) (resp *connectorPB.GetConnectorResponse, err error) {
+
+	resp, dbConnector, err := h.getConnector(ctx, req, true)
+	if err != nil {
+		return resp, err
+	}
+
+	// The reason of a STATE_ERROR is returned in a header, as the connector has no field for it
+	if dbConnector.State == datamodel.ConnectorState(connectorPB.Connector_STATE_ERROR) && dbConnector.StateReason.Valid {
+		if err := grpc.SetHeader(ctx, metadata.Pairs(constant.HeaderStateReasonKey, headerValue(dbConnector.StateReason.String))); err != nil {
+			logger, _ := logger.GetZapLogger(ctx)
+			logger.Error(err.Error())
+		}
+	}
+
+	return resp, nil
+}
+
+// headerValue replaces the characters which are not allowed in a header value
+func headerValue(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s)
+}
+
+func (h *PublicHandler) getConnector(ctx context.Context, req *connectorPB.GetConnectorRequest, credentialMask bool) (resp *connectorPB.GetConnectorResponse, dbConnector *datamodel.Connector, err error) {
+
+	eventName := "GetConnector"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var isBasicView bool
+
+	var connID string
+
+	var connDefColID string
+
+	resp = &connectorPB.GetConnectorResponse{}
+	if connID, err = resource.GetRscNameID(req.GetName()); err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, nil, err
+	}
+
+	isBasicView = (req.GetView() == connectorPB.View_VIEW_BASIC) || (req.GetView() == connectorPB.View_VIEW_UNSPECIFIED)
+	connDefColID = "connector-definitions"
+
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, nil, err
+	}
+
+	dbConnector, err = h.service.GetConnectorByID(ctx, connID, owner, isBasicView)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, nil, err
+	}
+
+	dbConnDef, err := h.connectors.GetConnectorDefinitionByUid(dbConnector.ConnectorDefinitionUID)
+
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, nil, err
+	}
+
+	policies, err := h.service.ListConnectorDefinitionPolicies(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, nil, err
+	}
+
+	resp.Connector = DBToPBConnector(
+		ctx,
+		dbConnector,
+		dbConnector.Owner,
+		fmt.Sprintf("%s/%s", connDefColID, dbConnDef.GetId()),
+	)
+	setPolicyTombstone(resp.Connector, dbConnector.ConnectorDefinitionUID, policies)
+
+	if credentialMask {
+		connector.MaskCredentialFields(h.connectors, dbConnDef.GetId(), resp.Connector.Configuration)
+	}
+
+	if !isBasicView {
+		resp.Connector.ConnectorDefinition = dbConnDef
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbConnector),
+	)))
+
+	return resp, dbConnector, nil
+}
+
+func (h *PublicHandler) UpdateConnector(ctx context.Context, req *connectorPB.UpdateConnectorRequest) (resp *connectorPB.UpdateConnectorResponse, err error) {
+
+	eventName := "UpdateConnector"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() { h.writeAudit(ctx, rec, err) }()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var mask fieldmask_utils.Mask
+
+	var connID string
+
+	var connDefRscName string
+
+	owner, ownerErr := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+
+	resp = &connectorPB.UpdateConnectorResponse{}
+	if ownerErr != nil {
+		span.SetStatus(1, ownerErr.Error())
+		return resp, ownerErr
+	}
+	rec.setActor(owner)
+
+	pbConnectorReq := req.GetConnector()
+	pbUpdateMask := req.GetUpdateMask()
+
+	// configuration filed is type google.protobuf.Struct, which needs to be updated as a whole
+	for idx, path := range pbUpdateMask.Paths {
+		if strings.Contains(path, "configuration") {
+			pbUpdateMask.Paths[idx] = "configuration"
+		}
+	}
+
+	if !pbUpdateMask.IsValid(req.GetConnector()) {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] update connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "update_mask",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
+
+	// Remove all OUTPUT_ONLY fields in the requested payload
+	pbUpdateMask, err = checkfield.CheckUpdateOutputOnlyFields(pbUpdateMask, outputOnlyFields)
+	if err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] update connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "OUTPUT_ONLY fields",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
+
+	getResp, _, err := h.getConnector(
+		ctx,
+		&connectorPB.GetConnectorRequest{
+			Name: req.GetConnector().GetName(),
+			View: connectorPB.View_VIEW_FULL.Enum(),
+		}, false)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	// Return error if IMMUTABLE fields are intentionally changed
+	if err := checkfield.CheckUpdateImmutableFields(req.GetConnector(), getResp.GetConnector(), immutableFields); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] update connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "IMMUTABLE fields",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
+
+	mask, err = fieldmask_utils.MaskFromProtoFieldMask(pbUpdateMask, strcase.ToCamel)
+	if err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] update connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "update_mask",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
+
+	if mask.IsEmpty() {
+		return &connectorPB.UpdateConnectorResponse{
+			Connector: getResp.GetConnector(),
+		}, nil
+	}
+
+	pbConnectorToUpdate := getResp.GetConnector()
+	if pbConnectorToUpdate.State == connectorPB.Connector_STATE_CONNECTED {
+		st, err := sterr.CreateErrorPreconditionFailure(
+			"[service] update connector",
+			[]*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "UPDATE",
+					Subject:     fmt.Sprintf("id %s", req.Connector.Id),
+					Description: fmt.Sprintf("Cannot update a connected %s connector", req.Connector.Id),
+				},
+			})
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return nil, st.Err()
+	}
+
+	connID = getResp.GetConnector().GetId()
+
+	dbConnDefID, err := resource.GetRscNameID(getResp.GetConnector().GetConnectorDefinitionName())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	dbConnDef, err := h.connectors.GetConnectorDefinitionById(dbConnDefID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	connDefRscName = fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId())
+
+	if ownerErr != nil {
+		span.SetStatus(1, err.Error())
+		return resp, ownerErr
+	}
+
+	configuration := &structpb.Struct{}
+	proto.Merge(configuration, pbConnectorToUpdate.Configuration)
+
+	// Only the fields mentioned in the field mask will be copied to `pbPipelineToUpdate`, other fields are left intact
+	err = fieldmask_utils.StructToStruct(mask, pbConnectorReq, pbConnectorToUpdate)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	connector.RemoveCredentialFieldsWithMaskString(h.connectors, dbConnDef.Id, req.Connector.Configuration)
+	proto.Merge(configuration, req.Connector.Configuration)
+	pbConnectorToUpdate.Configuration = configuration
+
+	h.auditConnector(ctx, rec, connID, owner, true)
+
+	dbConnector, err := h.service.UpdateConnector(ctx, connID, owner, PBToDBConnector(ctx, pbConnectorToUpdate, owner.GetName(), dbConnDef))
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setAfter(dbConnector)
+
+	resp.Connector = DBToPBConnector(
+		ctx,
+		dbConnector,
+		service.GenOwnerPermalink(owner),
+		connDefRscName)
+
+	connector.MaskCredentialFields(h.connectors, dbConnDef.Id, resp.Connector.Configuration)
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbConnector),
+	)))
+	return resp, nil
+}
+
+func (h *PublicHandler) DeleteConnector(ctx context.Context, req *connectorPB.DeleteConnectorRequest) (resp *connectorPB.DeleteConnectorResponse, err error) {
+
+	eventName := "DeleteConnector"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() { h.writeAudit(ctx, rec, err) }()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var connID string
+
+	// Cast all used types and data
+
+	resp = &connectorPB.DeleteConnectorResponse{}
+	if connID, err = resource.GetRscNameID(req.GetName()); err != nil {
+		return resp, err
+	}
+
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setActor(owner)
+
+	dbConnector, err := h.service.GetConnectorByID(ctx, connID, owner, false)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setBefore(dbConnector)
+
+	if err := h.service.DeleteConnector(ctx, connID, owner); err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbConnector),
+	)))
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs("x-http-code", strconv.Itoa(http.StatusNoContent))); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+func (h *PublicHandler) LookUpConnector(ctx context.Context, req *connectorPB.LookUpConnectorRequest) (resp *connectorPB.LookUpConnectorResponse, err error) {
+
+	eventName := "LookUpConnector"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var isBasicView bool
+
+	var connUID uuid.UUID
+
+	var connDefColID string
+
+	resp = &connectorPB.LookUpConnectorResponse{}
+
+	// Return error if REQUIRED fields are not provided in the requested payload
+	if err := checkfield.CheckRequiredFields(req, lookUpRequiredFields); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] lookup connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "REQUIRED fields",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
+
+	connUIDStr, err := resource.GetPermalinkUID(req.GetPermalink())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	connUID, err = uuid.FromString(connUIDStr)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	isBasicView = (req.GetView() == connectorPB.View_VIEW_BASIC) || (req.GetView() == connectorPB.View_VIEW_UNSPECIFIED)
+	connDefColID = "connector-definitions"
+
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	dbConnector, err := h.service.GetConnectorByUID(ctx, connUID, owner, isBasicView)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	dbConnDef, err := h.connectors.GetConnectorDefinitionByUid(dbConnector.ConnectorDefinitionUID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbConnector),
+	)))
+
+	policies, err := h.service.ListConnectorDefinitionPolicies(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	pbConnector := DBToPBConnector(
+		ctx,
+		dbConnector,
+		dbConnector.Owner,
+		fmt.Sprintf("%s/%s", connDefColID, dbConnDef.GetId()),
+	)
+	setPolicyTombstone(pbConnector, dbConnector.ConnectorDefinitionUID, policies)
+	if !isBasicView {
+		pbConnector.ConnectorDefinition = dbConnDef
+	}
+
+	resp.Connector = pbConnector
+
+	return resp, nil
+}
+
+func (h *PublicHandler) ConnectConnector(ctx context.Context, req *connectorPB.ConnectConnectorRequest) (resp *connectorPB.ConnectConnectorResponse, err error) {
+
+	eventName := "ConnectConnector"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() { h.writeAudit(ctx, rec, err) }()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var connID string
+
+	var connDefRscName string
+
+	resp = &connectorPB.ConnectConnectorResponse{}
+
+	// Return error if REQUIRED fields are not provided in the requested payload
+	if err := checkfield.CheckRequiredFields(req, connectRequiredFields); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] connect connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "REQUIRED fields",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
+
+	connID, err = resource.GetRscNameID(req.GetName())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	getResp, _, err := h.getConnector(
+		ctx,
+		&connectorPB.GetConnectorRequest{
+			Name: req.GetName(),
+			View: connectorPB.View_VIEW_BASIC.Enum(),
+		}, true)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	dbConnDefID, err := resource.GetRscNameID(getResp.GetConnector().GetConnectorDefinitionName())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	dbConnDef, err := h.connectors.GetConnectorDefinitionById(dbConnDefID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	connDefRscName = fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId())
+
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setActor(owner)
+
+	dbConnector, err := h.service.GetConnectorByID(ctx, connID, owner, false)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setBefore(dbConnector)
+
+	state, err := h.service.CheckConnectorByUID(ctx, dbConnector.UID)
+
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			fmt.Sprintf("[handler] connect connector error %v", err),
+			[]*errdetails.BadRequest_FieldViolation{},
+		)
+		span.SetStatus(1, fmt.Sprintf("connect connector error %v", err))
+		return resp, st.Err()
+	}
+	if *state != connectorPB.Connector_STATE_CONNECTED {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] connect connector error not Connector_STATE_CONNECTED",
+			[]*errdetails.BadRequest_FieldViolation{},
+		)
+		span.SetStatus(1, "connect connector error not Connector_STATE_CONNECTED")
+		return resp, st.Err()
+	}
+
+	dbConnector, err = h.service.UpdateConnectorState(ctx, connID, service.GenOwnerPermalink(owner), datamodel.ConnectorState(*state))
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setAfter(dbConnector)
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbConnector),
+	)))
+
+	pbConnector := DBToPBConnector(
+		ctx,
+		dbConnector,
+		dbConnector.Owner,
+		connDefRscName,
+	)
+
+	resp.Connector = pbConnector
+
+	return resp, nil
+}
+
+func (h *PublicHandler) DisconnectConnector(ctx context.Context, req *connectorPB.DisconnectConnectorRequest) (resp *connectorPB.DisconnectConnectorResponse, err error) {
+
+	eventName := "DisconnectConnector"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() { h.writeAudit(ctx, rec, err) }()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var connID string
+
+	var connDefRscName string
+
+	resp = &connectorPB.DisconnectConnectorResponse{}
+
+	// Return error if REQUIRED fields are not provided in the requested payload
+	if err := checkfield.CheckRequiredFields(req, disconnectRequiredFields); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] disconnect connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "REQUIRED fields",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
+
+	connID, err = resource.GetRscNameID(req.GetName())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	getResp, _, err := h.getConnector(
+		ctx,
+		&connectorPB.GetConnectorRequest{
+			Name: req.GetName(),
+			View: connectorPB.View_VIEW_BASIC.Enum(),
+		}, true)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	dbConnDefID, err := resource.GetRscNameID(getResp.GetConnector().GetConnectorDefinitionName())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	dbConnDef, err := h.connectors.GetConnectorDefinitionById(dbConnDefID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	connDefRscName = fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId())
+
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setActor(owner)
+
+	h.auditConnector(ctx, rec, connID, owner, true)
+
+	dbConnector, err := h.service.UpdateConnectorState(ctx, connID, service.GenOwnerPermalink(owner), datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED))
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setAfter(dbConnector)
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbConnector),
+	)))
+
+	pbConnector := DBToPBConnector(
+		ctx,
+		dbConnector,
+		dbConnector.Owner,
+		connDefRscName,
+	)
+
+	resp.Connector = pbConnector
+
+	return resp, nil
+}
+
+func (h *PublicHandler) RenameConnector(ctx context.Context, req *connectorPB.RenameConnectorRequest) (resp *connectorPB.RenameConnectorResponse, err error) {
+
+	eventName := "RenameConnector"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() { h.writeAudit(ctx, rec, err) }()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var connID string
+	var connNewID string
+
+	var connDefRscName string
+
+	resp = &connectorPB.RenameConnectorResponse{}
+
+	// Return error if REQUIRED fields are not provided in the requested payload
+	if err := checkfield.CheckRequiredFields(req, renameRequiredFields); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] rename connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "REQUIRED fields",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
+
+	connID, err = resource.GetRscNameID(req.GetName())
+	if err != nil {
+		return resp, err
+	}
+	connNewID = req.GetNewConnectorId()
+	if len(connNewID) > 8 && connNewID[:8] == "instill-" {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] create connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "connector",
+					Description: "the id can not start with instill-",
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
+
+	getResp, _, err := h.getConnector(
+		ctx,
+		&connectorPB.GetConnectorRequest{
+			Name: req.GetName(),
+			View: connectorPB.View_VIEW_BASIC.Enum(),
+		}, true)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	dbConnDefID, err := resource.GetRscNameID(getResp.GetConnector().GetConnectorDefinitionName())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	dbConnDef, err := h.connectors.GetConnectorDefinitionById(dbConnDefID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	connDefRscName = fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId())
+
+	// Return error if resource ID does not follow RFC-1034
+	if err := checkfield.CheckResourceID(connNewID); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] rename connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "id",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return resp, st.Err()
+	}
+
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setActor(owner)
+
+	h.auditConnector(ctx, rec, connID, owner, true)
+
+	dbConnector, err := h.service.UpdateConnectorID(ctx, connID, owner, connNewID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	rec.setAfter(dbConnector)
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		owner,
+		eventName,
+		custom_otel.SetEventResource(dbConnector),
+	)))
+
+	resp.Connector = DBToPBConnector(
+		ctx,
+		dbConnector,
+		dbConnector.Owner,
+		connDefRscName,
+	)
+
+	return resp, nil
+}
+
+func (h *PublicHandler) CloneConnector(ctx context.Context, req *connectorExtPB.CloneConnectorRequest) (resp *connectorExtPB.CloneConnectorResponse, err error) {
+
+	eventName := "CloneConnector"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
 
-	// Remove all OUTPUT_ONLY fields in the requested payload
-	pbUpdateMask, err = checkfield.CheckUpdateOutputOnlyFields(pbUpdateMask, outputOnlyFields)
-	if err != nil {
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() { h.writeAudit(ctx, rec, err) }()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var connID string
+	var connNewID string
+
+	resp = &connectorExtPB.CloneConnectorResponse{}
+
+	// Return error if REQUIRED fields are not provided in the requested payload
+	if req.GetName() == "" || req.GetNewConnectorId() == "" {
 		st, err := sterr.CreateErrorBadRequest(
-			"[handler] update connector error",
+			"[handler] clone connector error",
 			[]*errdetails.BadRequest_FieldViolation{
 				{
-					Field:       "OUTPUT_ONLY fields",
-					Description: err.Error(),
+					Field:       "REQUIRED fields",
+					Description: "name and new_connector_id are required",
 				},
 			},
 		)
@@ -716,25 +2139,20 @@ func (h *PublicHandler) UpdateConnector(ctx context.Context, req *connectorPB.Up
 		return resp, st.Err()
 	}
 
-	getResp, err := h.getConnector(
-		ctx,
-		&connectorPB.GetConnectorRequest{
-			Name: req.GetConnector().GetName(),
-			View: connectorPB.View_VIEW_FULL.Enum(),
-		}, false)
+	connID, err = resource.GetRscNameID(req.GetName())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	// Return error if IMMUTABLE fields are intentionally changed
-	if err := checkfield.CheckUpdateImmutableFields(req.GetConnector(), getResp.GetConnector(), immutableFields); err != nil {
+	connNewID = req.GetNewConnectorId()
+	if len(connNewID) > 8 && connNewID[:8] == "instill-" {
 		st, err := sterr.CreateErrorBadRequest(
-			"[handler] update connector error",
+			"[handler] clone connector error",
 			[]*errdetails.BadRequest_FieldViolation{
 				{
-					Field:       "IMMUTABLE fields",
-					Description: err.Error(),
+					Field:       "new_connector_id",
+					Description: "the id can not start with instill-",
 				},
 			},
 		)
@@ -745,13 +2163,13 @@ func (h *PublicHandler) UpdateConnector(ctx context.Context, req *connectorPB.Up
 		return resp, st.Err()
 	}
 
-	mask, err = fieldmask_utils.MaskFromProtoFieldMask(pbUpdateMask, strcase.ToCamel)
-	if err != nil {
+	// Return error if resource ID does not follow RFC-1034
+	if err := checkfield.CheckResourceID(connNewID); err != nil {
 		st, err := sterr.CreateErrorBadRequest(
-			"[handler] update connector error",
+			"[handler] clone connector error",
 			[]*errdetails.BadRequest_FieldViolation{
 				{
-					Field:       "update_mask",
+					Field:       "new_connector_id",
 					Description: err.Error(),
 				},
 			},
@@ -763,78 +2181,55 @@ func (h *PublicHandler) UpdateConnector(ctx context.Context, req *connectorPB.Up
 		return resp, st.Err()
 	}
 
-	if mask.IsEmpty() {
-		return &connectorPB.UpdateConnectorResponse{
-			Connector: getResp.GetConnector(),
-		}, nil
-	}
-
-	pbConnectorToUpdate := getResp.GetConnector()
-	if pbConnectorToUpdate.State == connectorPB.Connector_STATE_CONNECTED {
-		st, err := sterr.CreateErrorPreconditionFailure(
-			"[service] update connector",
-			[]*errdetails.PreconditionFailure_Violation{
-				{
-					Type:        "UPDATE",
-					Subject:     fmt.Sprintf("id %s", req.Connector.Id),
-					Description: fmt.Sprintf("Cannot update a connected %s connector", req.Connector.Id),
-				},
-			})
-		if err != nil {
-			logger.Error(err.Error())
-		}
-		span.SetStatus(1, st.Err().Error())
-		return nil, st.Err()
-	}
-
-	connID = getResp.GetConnector().GetId()
-
-	dbConnDefID, err := resource.GetRscNameID(getResp.GetConnector().GetConnectorDefinitionName())
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
+	rec.setActor(owner)
 
-	dbConnDef, err := h.connectors.GetConnectorDefinitionById(dbConnDefID)
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+	visibility := connectorPB.Connector_VISIBILITY_UNSPECIFIED
+	if req.Visibility != nil {
+		if visibility = req.GetVisibility(); visibility == connectorPB.Connector_VISIBILITY_UNSPECIFIED {
+			st, err := sterr.CreateErrorBadRequest(
+				"[handler] clone connector error",
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "visibility",
+						Description: "the visibility can not be VISIBILITY_UNSPECIFIED",
+					},
+				},
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			span.SetStatus(1, st.Err().Error())
+			return resp, st.Err()
+		}
 	}
 
-	connDefRscName = fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId())
-
-	if ownerErr != nil {
-		span.SetStatus(1, err.Error())
-		return resp, ownerErr
+	targetOwner := owner
+	if req.GetTargetOwner() != "" && req.GetTargetOwner() != owner.GetName() {
+		targetOwner, err = resource.GetOwnerByName(ctx, h.service.GetMgmtPrivateServiceClient(), req.GetTargetOwner())
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return resp, err
+		}
 	}
 
-	configuration := &structpb.Struct{}
-	proto.Merge(configuration, pbConnectorToUpdate.Configuration)
-
-	// Only the fields mentioned in the field mask will be copied to `pbPipelineToUpdate`, other fields are left intact
-	err = fieldmask_utils.StructToStruct(mask, pbConnectorReq, pbConnectorToUpdate)
+	dbConnector, err := h.service.CloneConnector(ctx, connID, owner, connNewID, targetOwner, datamodel.ConnectorVisibility(visibility))
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
+	rec.setAfter(dbConnector)
 
-	connector.RemoveCredentialFieldsWithMaskString(h.connectors, dbConnDef.Id, req.Connector.Configuration)
-	proto.Merge(configuration, req.Connector.Configuration)
-	pbConnectorToUpdate.Configuration = configuration
-
-	dbConnector, err := h.service.UpdateConnector(ctx, connID, owner, PBToDBConnector(ctx, pbConnectorToUpdate, owner.GetName(), dbConnDef))
+	dbConnDef, err := h.connectors.GetConnectorDefinitionByUid(dbConnector.ConnectorDefinitionUID)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	resp.Connector = DBToPBConnector(
-		ctx,
-		dbConnector,
-		service.GenOwnerPermalink(owner),
-		connDefRscName)
-
-	connector.MaskCredentialFields(h.connectors, dbConnDef.Id, resp.Connector.Configuration)
 	logger.Info(string(custom_otel.NewLogMessage(
 		span,
 		logUUID.String(),
@@ -842,506 +2237,511 @@ func (h *PublicHandler) UpdateConnector(ctx context.Context, req *connectorPB.Up
 		eventName,
 		custom_otel.SetEventResource(dbConnector),
 	)))
+
+	resp.Connector = DBToPBConnector(
+		ctx,
+		dbConnector,
+		dbConnector.Owner,
+		fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId()),
+	)
+	connector.MaskCredentialFields(h.connectors, dbConnDef.GetId(), resp.Connector.Configuration)
+
 	return resp, nil
 }
 
-func (h *PublicHandler) DeleteConnector(ctx context.Context, req *connectorPB.DeleteConnectorRequest) (resp *connectorPB.DeleteConnectorResponse, err error) {
+func (h *PublicHandler) BatchCreateConnectors(ctx context.Context, req *connectorExtPB.BatchCreateConnectorsRequest) (resp *connectorExtPB.BatchCreateConnectorsResponse, err error) {
 
-	eventName := "DeleteConnector"
+	eventName := "BatchCreateConnectors"
 
 	ctx, span := tracer.Start(ctx, eventName,
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
+	logUUID := logger.RequestID(ctx)
 
-	logger, _ := logger.GetZapLogger(ctx)
+	// The items reaching the service are audited one by one, a batch failing
+	// as a whole is audited once
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() {
+		if err != nil {
+			h.writeAudit(ctx, rec, err)
+		}
+	}()
 
-	var connID string
+	logger, _ := logger.GetZapLogger(ctx)
 
-	// Cast all used types and data
+	resp = &connectorExtPB.BatchCreateConnectorsResponse{}
 
-	resp = &connectorPB.DeleteConnectorResponse{}
-	if connID, err = resource.GetRscNameID(req.GetName()); err != nil {
+	if err := checkBatchSize(logger, len(req.Connectors)); err != nil {
+		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
+	// Validate all items up front, invalid items are reported without reaching the service
+	results := make([]*connectorExtPB.BatchConnectorResult, len(req.Connectors))
+	var dbConnectors []*datamodel.Connector
+	var idxs []int
+	for idx, pbConnector := range req.Connectors {
+		dbConnector, _, err := h.validateCreateConnector(ctx, pbConnector)
+		if err != nil {
+			if req.AllOrNothing {
+				span.SetStatus(1, err.Error())
+				return resp, service.BatchItemError(idx, err)
+			}
+			results[idx] = &connectorExtPB.BatchConnectorResult{Status: status.Convert(err).Proto()}
+			continue
+		}
+		dbConnectors = append(dbConnectors, dbConnector)
+		idxs = append(idxs, idx)
+	}
+
 	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
+	rec.setActor(owner)
 
-	dbConnector, err := h.service.GetConnectorByID(ctx, connID, owner, true)
+	for _, dbConnector := range dbConnectors {
+		dbConnector.Owner = owner.GetName()
+	}
+
+	batchResults, err := h.service.BatchCreateConnectors(ctx, owner, dbConnectors, req.AllOrNothing)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	if err := h.service.DeleteConnector(ctx, connID, owner); err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+	for i, batchResult := range batchResults {
+		results[idxs[i]] = h.batchConnectorResult(ctx, span, logUUID.String(), owner, "CreateConnector", dbConnectors[i].ID, batchResult)
 	}
 
-	logger.Info(string(custom_otel.NewLogMessage(
-		span,
-		logUUID.String(),
-		owner,
-		eventName,
-		custom_otel.SetEventResource(dbConnector),
-	)))
+	resp.Results = results
 
-	if err := grpc.SetHeader(ctx, metadata.Pairs("x-http-code", strconv.Itoa(http.StatusNoContent))); err != nil {
-		return resp, err
-	}
 	return resp, nil
 }
 
-func (h *PublicHandler) LookUpConnector(ctx context.Context, req *connectorPB.LookUpConnectorRequest) (resp *connectorPB.LookUpConnectorResponse, err error) {
+func (h *PublicHandler) BatchDeleteConnectors(ctx context.Context, req *connectorExtPB.BatchDeleteConnectorsRequest) (resp *connectorExtPB.BatchDeleteConnectorsResponse, err error) {
 
-	eventName := "LookUpConnector"
+	eventName := "BatchDeleteConnectors"
 
 	ctx, span := tracer.Start(ctx, eventName,
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
-
-	logger, _ := logger.GetZapLogger(ctx)
-
-	var isBasicView bool
-
-	var connUID uuid.UUID
-
-	var connDefColID string
-
-	resp = &connectorPB.LookUpConnectorResponse{}
+	logUUID := logger.RequestID(ctx)
 
-	// Return error if REQUIRED fields are not provided in the requested payload
-	if err := checkfield.CheckRequiredFields(req, lookUpRequiredFields); err != nil {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] lookup connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "REQUIRED fields",
-					Description: err.Error(),
-				},
-			},
-		)
+	// The items reaching the service are audited one by one, a batch failing
+	// as a whole is audited once
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() {
 		if err != nil {
-			logger.Error(err.Error())
+			h.writeAudit(ctx, rec, err)
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
-	}
+	}()
 
-	connUIDStr, err := resource.GetPermalinkUID(req.GetPermalink())
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
-	}
-	connUID, err = uuid.FromString(connUIDStr)
-	if err != nil {
+	logger, _ := logger.GetZapLogger(ctx)
+
+	resp = &connectorExtPB.BatchDeleteConnectorsResponse{}
+
+	if err := checkBatchSize(logger, len(req.Names)); err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
-	isBasicView = (req.GetView() == connectorPB.View_VIEW_BASIC) || (req.GetView() == connectorPB.View_VIEW_UNSPECIFIED)
-	connDefColID = "connector-definitions"
 
-	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	results := make([]*connectorExtPB.BatchConnectorResult, len(req.Names))
+	connIDs, idxs, err := parseBatchNames(req.Names, req.AllOrNothing, results)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	dbConnector, err := h.service.GetConnectorByUID(ctx, connUID, owner, isBasicView)
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
+	rec.setActor(owner)
 
-	dbConnDef, err := h.connectors.GetConnectorDefinitionByUid(dbConnector.ConnectorDefinitionUID)
+	batchResults, err := h.service.BatchDeleteConnectors(ctx, owner, connIDs, req.AllOrNothing)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	logger.Info(string(custom_otel.NewLogMessage(
-		span,
-		logUUID.String(),
-		owner,
-		eventName,
-		custom_otel.SetEventResource(dbConnector),
-	)))
-
-	pbConnector := DBToPBConnector(
-		ctx,
-		dbConnector,
-		dbConnector.Owner,
-		fmt.Sprintf("%s/%s", connDefColID, dbConnDef.GetId()),
-	)
-	if !isBasicView {
-		pbConnector.ConnectorDefinition = dbConnDef
+	for i, batchResult := range batchResults {
+		h.auditBatchItem(ctx, span, logUUID.String(), owner, "DeleteConnector", connIDs[i], batchResult)
+		if batchResult.Err != nil {
+			results[idxs[i]] = &connectorExtPB.BatchConnectorResult{Status: status.Convert(batchResult.Err).Proto()}
+			continue
+		}
+		logger.Info(string(custom_otel.NewLogMessage(
+			span,
+			logUUID.String(),
+			owner,
+			"DeleteConnector",
+			custom_otel.SetEventResource(batchResult.Connector),
+		)))
+		results[idxs[i]] = &connectorExtPB.BatchConnectorResult{Status: status.New(codes.OK, "").Proto()}
 	}
 
-	resp.Connector = pbConnector
+	resp.Results = results
 
 	return resp, nil
 }
 
-func (h *PublicHandler) ConnectConnector(ctx context.Context, req *connectorPB.ConnectConnectorRequest) (resp *connectorPB.ConnectConnectorResponse, err error) {
+func (h *PublicHandler) BatchConnectConnectors(ctx context.Context, req *connectorExtPB.BatchConnectConnectorsRequest) (resp *connectorExtPB.BatchConnectConnectorsResponse, err error) {
 
-	eventName := "ConnectConnector"
+	eventName := "BatchConnectConnectors"
 
 	ctx, span := tracer.Start(ctx, eventName,
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
+	logUUID := logger.RequestID(ctx)
 
-	logger, _ := logger.GetZapLogger(ctx)
+	// The items reaching the service are audited one by one, a batch failing
+	// as a whole is audited once
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() {
+		if err != nil {
+			h.writeAudit(ctx, rec, err)
+		}
+	}()
 
-	var connID string
+	resp = &connectorExtPB.BatchConnectConnectorsResponse{}
 
-	var connDefRscName string
+	resp.Results, err = h.batchUpdateConnectorState(ctx, span, logUUID.String(), rec, req.Names, req.AllOrNothing, connectorPB.Connector_STATE_CONNECTED, "ConnectConnector")
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
 
-	resp = &connectorPB.ConnectConnectorResponse{}
+	return resp, nil
+}
 
-	// Return error if REQUIRED fields are not provided in the requested payload
-	if err := checkfield.CheckRequiredFields(req, connectRequiredFields); err != nil {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] connect connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "REQUIRED fields",
-					Description: err.Error(),
-				},
-			},
-		)
+func (h *PublicHandler) BatchDisconnectConnectors(ctx context.Context, req *connectorExtPB.BatchDisconnectConnectorsRequest) (resp *connectorExtPB.BatchDisconnectConnectorsResponse, err error) {
+
+	eventName := "BatchDisconnectConnectors"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID := logger.RequestID(ctx)
+
+	// The items reaching the service are audited one by one, a batch failing
+	// as a whole is audited once
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() {
 		if err != nil {
-			logger.Error(err.Error())
+			h.writeAudit(ctx, rec, err)
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
-	}
+	}()
 
-	connID, err = resource.GetRscNameID(req.GetName())
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
-	}
+	resp = &connectorExtPB.BatchDisconnectConnectorsResponse{}
 
-	getResp, err := h.GetConnector(
-		ctx,
-		&connectorPB.GetConnectorRequest{
-			Name: req.GetName(),
-			View: connectorPB.View_VIEW_BASIC.Enum(),
-		})
+	resp.Results, err = h.batchUpdateConnectorState(ctx, span, logUUID.String(), rec, req.Names, req.AllOrNothing, connectorPB.Connector_STATE_DISCONNECTED, "DisconnectConnector")
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	dbConnDefID, err := resource.GetRscNameID(getResp.GetConnector().GetConnectorDefinitionName())
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+	return resp, nil
+}
+
+func (h *PublicHandler) batchUpdateConnectorState(ctx context.Context, span trace.Span, logID string, rec *auditRecord, names []string, allOrNothing bool, state connectorPB.Connector_State, eventName string) ([]*connectorExtPB.BatchConnectorResult, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if err := checkBatchSize(logger, len(names)); err != nil {
+		return nil, err
 	}
 
-	dbConnDef, err := h.connectors.GetConnectorDefinitionById(dbConnDefID)
+	results := make([]*connectorExtPB.BatchConnectorResult, len(names))
+	connIDs, idxs, err := parseBatchNames(names, allOrNothing, results)
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		return nil, err
 	}
 
-	connDefRscName = fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId())
-
 	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		return nil, err
 	}
+	rec.setActor(owner)
 
-	dbConnector, err := h.service.GetConnectorByID(ctx, connID, owner, true)
+	batchResults, err := h.service.BatchUpdateConnectorState(ctx, owner, connIDs, datamodel.ConnectorState(state), allOrNothing)
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		return nil, err
 	}
 
-	state, err := h.service.CheckConnectorByUID(ctx, dbConnector.UID)
-
-	if err != nil {
-		st, _ := sterr.CreateErrorBadRequest(
-			fmt.Sprintf("[handler] connect connector error %v", err),
-			[]*errdetails.BadRequest_FieldViolation{},
-		)
-		span.SetStatus(1, fmt.Sprintf("connect connector error %v", err))
-		return resp, st.Err()
+	for i, batchResult := range batchResults {
+		results[idxs[i]] = h.batchConnectorResult(ctx, span, logID, owner, eventName, connIDs[i], batchResult)
 	}
-	if *state != connectorPB.Connector_STATE_CONNECTED {
-		st, _ := sterr.CreateErrorBadRequest(
-			"[handler] connect connector error not Connector_STATE_CONNECTED",
-			[]*errdetails.BadRequest_FieldViolation{},
-		)
-		span.SetStatus(1, "connect connector error not Connector_STATE_CONNECTED")
-		return resp, st.Err()
+
+	return results, nil
+}
+
+// batchConnectorResult converts the outcome of a batch item, it audits the
+// item and logs the event of a successful item
+func (h *PublicHandler) batchConnectorResult(ctx context.Context, span trace.Span, logID string, owner *mgmtPB.User, eventName string, connID string, batchResult *service.BatchResult) *connectorExtPB.BatchConnectorResult {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	h.auditBatchItem(ctx, span, logID, owner, eventName, connID, batchResult)
+
+	if batchResult.Err != nil {
+		return &connectorExtPB.BatchConnectorResult{Status: status.Convert(batchResult.Err).Proto()}
 	}
 
-	dbConnector, err = h.service.UpdateConnectorState(ctx, connID, service.GenOwnerPermalink(owner), datamodel.ConnectorState(*state))
+	dbConnDef, err := h.connectors.GetConnectorDefinitionByUid(batchResult.Connector.ConnectorDefinitionUID)
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		return &connectorExtPB.BatchConnectorResult{Status: status.Convert(err).Proto()}
 	}
 
 	logger.Info(string(custom_otel.NewLogMessage(
 		span,
-		logUUID.String(),
+		logID,
 		owner,
 		eventName,
-		custom_otel.SetEventResource(dbConnector),
+		custom_otel.SetEventResource(batchResult.Connector),
 	)))
 
 	pbConnector := DBToPBConnector(
 		ctx,
-		dbConnector,
-		dbConnector.Owner,
-		connDefRscName,
+		batchResult.Connector,
+		batchResult.Connector.Owner,
+		fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId()),
 	)
+	connector.MaskCredentialFields(h.connectors, dbConnDef.GetId(), pbConnector.Configuration)
 
-	resp.Connector = pbConnector
+	return &connectorExtPB.BatchConnectorResult{
+		Connector: pbConnector,
+		Status:    status.New(codes.OK, "").Proto(),
+	}
+}
 
-	return resp, nil
+// checkBatchSize returns an error if a batch is empty or exceeds the maximum batch size
+func checkBatchSize(logger *zap.Logger, size int) error {
+	if size > 0 && size <= constant.MaxBatchSize {
+		return nil
+	}
+	st, err := sterr.CreateErrorBadRequest(
+		"[handler] batch error",
+		[]*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       "batch size",
+				Description: fmt.Sprintf("a batch must contain between 1 and %d items, got %d", constant.MaxBatchSize, size),
+			},
+		},
+	)
+	if err != nil {
+		logger.Error(err.Error())
+	}
+	return st.Err()
 }
 
-func (h *PublicHandler) DisconnectConnector(ctx context.Context, req *connectorPB.DisconnectConnectorRequest) (resp *connectorPB.DisconnectConnectorResponse, err error) {
+// parseBatchNames returns the ids of the valid connector names of a batch
+// along with their positions. An invalid name fails the whole batch in
+// all-or-nothing mode and is reported in results otherwise.
+func parseBatchNames(names []string, allOrNothing bool, results []*connectorExtPB.BatchConnectorResult) ([]string, []int, error) {
+	var connIDs []string
+	var idxs []int
+	for idx, name := range names {
+		connID := strings.TrimPrefix(name, "connectors/")
+		if connID == name || connID == "" || strings.Contains(connID, "/") {
+			err := status.Errorf(codes.InvalidArgument, "[handler] invalid connector name %q", name)
+			if allOrNothing {
+				return nil, nil, service.BatchItemError(idx, err)
+			}
+			results[idx] = &connectorExtPB.BatchConnectorResult{Status: status.Convert(err).Proto()}
+			continue
+		}
+		connIDs = append(connIDs, connID)
+		idxs = append(idxs, idx)
+	}
+	return connIDs, idxs, nil
+}
 
-	eventName := "DisconnectConnector"
+func (h *PublicHandler) ExportConnectors(ctx context.Context, req *ExportConnectorsRequest) (resp *ExportConnectorsResponse, err error) {
+
+	eventName := "ExportConnectors"
 
 	ctx, span := tracer.Start(ctx, eventName,
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
+	logUUID := logger.RequestID(ctx)
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var connID string
-
-	var connDefRscName string
-
-	resp = &connectorPB.DisconnectConnectorResponse{}
-
-	// Return error if REQUIRED fields are not provided in the requested payload
-	if err := checkfield.CheckRequiredFields(req, disconnectRequiredFields); err != nil {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] disconnect connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "REQUIRED fields",
-					Description: err.Error(),
-				},
-			},
-		)
-		if err != nil {
-			logger.Error(err.Error())
-		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
-	}
-
-	connID, err = resource.GetRscNameID(req.GetName())
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
-	}
+	resp = &ExportConnectorsResponse{}
 
-	getResp, err := h.GetConnector(
-		ctx,
-		&connectorPB.GetConnectorRequest{
-			Name: req.GetName(),
-			View: connectorPB.View_VIEW_BASIC.Enum(),
-		})
+	format, err := bundle.ParseFormat(req.Format)
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		st := bundleBadRequest(logger, "[handler] export connectors error", "format", err)
+		span.SetStatus(1, st.Error())
+		return resp, st
 	}
 
-	dbConnDefID, err := resource.GetRscNameID(getResp.GetConnector().GetConnectorDefinitionName())
+	credentials, err := bundle.ParseCredentialMode(req.Credentials)
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		st := bundleBadRequest(logger, "[handler] export connectors error", "credentials", err)
+		span.SetStatus(1, st.Error())
+		return resp, st
 	}
 
-	dbConnDef, err := h.connectors.GetConnectorDefinitionById(dbConnDefID)
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	connDefRscName = fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId())
-
-	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
+	b, err := h.service.ExportConnectors(ctx, owner, credentials)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	dbConnector, err := h.service.UpdateConnectorState(ctx, connID, service.GenOwnerPermalink(owner), datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED))
+	resp.Bundle, err = bundle.Marshal(b, format)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
+	resp.ContentType = format.ContentType()
 
 	logger.Info(string(custom_otel.NewLogMessage(
 		span,
 		logUUID.String(),
 		owner,
 		eventName,
-		custom_otel.SetEventResource(dbConnector),
+		custom_otel.SetEventResult(fmt.Sprintf("%d connectors", len(b.Connectors))),
 	)))
 
-	pbConnector := DBToPBConnector(
-		ctx,
-		dbConnector,
-		dbConnector.Owner,
-		connDefRscName,
-	)
-
-	resp.Connector = pbConnector
-
 	return resp, nil
 }
 
-func (h *PublicHandler) RenameConnector(ctx context.Context, req *connectorPB.RenameConnectorRequest) (resp *connectorPB.RenameConnectorResponse, err error) {
+func (h *PublicHandler) ImportConnectors(ctx context.Context, req *ImportConnectorsRequest) (resp *ImportConnectorsResponse, err error) {
 
-	eventName := "RenameConnector"
+	eventName := "ImportConnectors"
 
 	ctx, span := tracer.Start(ctx, eventName,
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
-
-	logger, _ := logger.GetZapLogger(ctx)
+	logUUID := logger.RequestID(ctx)
 
-	var connID string
-	var connNewID string
+	// The imported connectors are audited one by one, an import failing as a
+	// whole is audited once
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() {
+		if err != nil {
+			h.writeAudit(ctx, rec, err)
+		}
+	}()
 
-	var connDefRscName string
+	logger, _ := logger.GetZapLogger(ctx)
 
-	resp = &connectorPB.RenameConnectorResponse{}
+	resp = &ImportConnectorsResponse{}
 
-	// Return error if REQUIRED fields are not provided in the requested payload
-	if err := checkfield.CheckRequiredFields(req, renameRequiredFields); err != nil {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] rename connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "REQUIRED fields",
-					Description: err.Error(),
-				},
-			},
-		)
-		if err != nil {
-			logger.Error(err.Error())
-		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+	format, err := bundle.ParseFormat(req.Format)
+	if err != nil {
+		st := bundleBadRequest(logger, "[handler] import connectors error", "format", err)
+		span.SetStatus(1, st.Error())
+		return resp, st
 	}
 
-	connID, err = resource.GetRscNameID(req.GetName())
+	strategy, err := bundle.ParseConflictStrategy(req.Conflict)
 	if err != nil {
-		return resp, err
-	}
-	connNewID = req.GetNewConnectorId()
-	if len(connNewID) > 8 && connNewID[:8] == "instill-" {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] create connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "connector",
-					Description: "the id can not start with instill-",
-				},
-			},
-		)
-		if err != nil {
-			logger.Error(err.Error())
-		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+		st := bundleBadRequest(logger, "[handler] import connectors error", "conflict", err)
+		span.SetStatus(1, st.Error())
+		return resp, st
 	}
 
-	getResp, err := h.GetConnector(
-		ctx,
-		&connectorPB.GetConnectorRequest{
-			Name: req.GetName(),
-			View: connectorPB.View_VIEW_BASIC.Enum(),
-		})
+	b, err := bundle.Unmarshal(req.Bundle, format)
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		st := bundleBadRequest(logger, "[handler] import connectors error", "bundle", err)
+		span.SetStatus(1, st.Error())
+		return resp, st
 	}
 
-	dbConnDefID, err := resource.GetRscNameID(getResp.GetConnector().GetConnectorDefinitionName())
+	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
+	rec.setActor(owner)
 
-	dbConnDef, err := h.connectors.GetConnectorDefinitionById(dbConnDefID)
+	importResults, applied, err := h.service.ImportConnectors(ctx, owner, b, strategy, req.DryRun)
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
 	}
 
-	connDefRscName = fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId())
+	resp.Applied = applied
+	resp.Results = make([]*ImportConnectorResult, len(importResults))
+	for idx, importResult := range importResults {
+		result := &ImportConnectorResult{
+			Id:          importResult.ID,
+			ConnectorId: importResult.ConnectorID,
+			Action:      string(importResult.Action),
+			Conflict:    importResult.Conflict,
+			Status:      status.Convert(importResult.Err).Proto(),
+		}
+		resp.Results[idx] = result
 
-	// Return error if resource ID does not follow RFC-1034
-	if err := checkfield.CheckResourceID(connNewID); err != nil {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] rename connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Field:       "id",
-					Description: err.Error(),
-				},
-			},
-		)
+		if importResult.Connector == nil {
+			continue
+		}
+
+		dbConnDef, err := h.connectors.GetConnectorDefinitionByUid(importResult.Connector.ConnectorDefinitionUID)
 		if err != nil {
-			logger.Error(err.Error())
+			result.Status = status.Convert(err).Proto()
+			continue
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
-	}
 
-	owner, err := resource.GetOwner(ctx, h.service.GetMgmtPrivateServiceClient())
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
-	}
+		itemEventName := "CreateConnector"
+		if importResult.Action == bundle.ActionOverwrite {
+			itemEventName = "UpdateConnector"
+		}
+		logger.Info(string(custom_otel.NewLogMessage(
+			span,
+			logUUID.String(),
+			owner,
+			itemEventName,
+			custom_otel.SetEventResource(importResult.Connector),
+		)))
+		if applied {
+			h.auditBatchItem(ctx, span, logUUID.String(), owner, itemEventName, importResult.ConnectorID, &service.BatchResult{Connector: importResult.Connector})
+		}
 
-	dbConnector, err := h.service.UpdateConnectorID(ctx, connID, owner, connNewID)
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		result.Connector = DBToPBConnector(
+			ctx,
+			importResult.Connector,
+			importResult.Connector.Owner,
+			fmt.Sprintf("connector-definitions/%s", dbConnDef.GetId()),
+		)
+		connector.MaskCredentialFields(h.connectors, dbConnDef.GetId(), result.Connector.Configuration)
 	}
 
-	logger.Info(string(custom_otel.NewLogMessage(
-		span,
-		logUUID.String(),
-		owner,
-		eventName,
-		custom_otel.SetEventResource(dbConnector),
-	)))
+	return resp, nil
+}
 
-	resp.Connector = DBToPBConnector(
-		ctx,
-		dbConnector,
-		dbConnector.Owner,
-		connDefRscName,
+// bundleBadRequest returns an invalid argument error for a malformed bundle request
+func bundleBadRequest(logger *zap.Logger, message string, field string, err error) error {
+	st, e := sterr.CreateErrorBadRequest(
+		message,
+		[]*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       field,
+				Description: err.Error(),
+			},
+		},
 	)
-
-	return resp, nil
+	if e != nil {
+		logger.Error(e.Error())
+	}
+	return st.Err()
 }
 
 func (h *PublicHandler) WatchConnector(ctx context.Context, req *connectorPB.WatchConnectorRequest) (resp *connectorPB.WatchConnectorResponse, err error) {
@@ -1352,7 +2752,7 @@ func (h *PublicHandler) WatchConnector(ctx context.Context, req *connectorPB.Wat
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
+	logUUID := logger.RequestID(ctx)
 
 	logger, _ := logger.GetZapLogger(ctx)
 
@@ -1390,7 +2790,7 @@ func (h *PublicHandler) WatchConnector(ctx context.Context, req *connectorPB.Wat
 		return resp, err
 	}
 
-	state, err := h.service.GetResourceState(dbConnector.UID)
+	state, err := h.service.GetResourceState(ctx, dbConnector.UID)
 
 	if err != nil {
 		span.SetStatus(1, err.Error())
@@ -1418,7 +2818,7 @@ func (h *PublicHandler) TestConnector(ctx context.Context, req *connectorPB.Test
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
+	logUUID := logger.RequestID(ctx)
 
 	logger, _ := logger.GetZapLogger(ctx)
 
@@ -1470,7 +2870,10 @@ func (h *PublicHandler) ExecuteConnector(ctx context.Context, req *connectorPB.E
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	logUUID, _ := uuid.NewV4()
+	logUUID := logger.RequestID(ctx)
+
+	rec := newAuditRecord(span, logUUID.String(), eventName)
+	defer func() { h.writeAudit(ctx, rec, err) }()
 
 	logger, _ := logger.GetZapLogger(ctx)
 
@@ -1484,6 +2887,9 @@ func (h *PublicHandler) ExecuteConnector(ctx context.Context, req *connectorPB.E
 	if err != nil {
 		return resp, err
 	}
+	rec.setActor(owner)
+	h.auditConnector(ctx, rec, connID, owner, false)
+
 	if outputs, err := h.service.Execute(ctx, connID, owner, req.GetInputs()); err != nil {
 		return nil, err
 	} else {
@@ -0,0 +1,193 @@
+// Package health checks the dependencies of the service, the database and
+// the backends it calls, for its readiness. The results are cached so that
+// the probes do not load the dependencies.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Defaults of the cache TTL and the timeout of the checks
+const (
+	DefaultCacheTTL = 5 * time.Second
+	DefaultTimeout  = 2 * time.Second
+)
+
+// Dependency is a dependency of the service, which is serving if Check
+// returns no error
+type Dependency struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// DependencyStatus is the result of the last check of a dependency
+type DependencyStatus struct {
+	Name      string
+	Serving   bool
+	Message   string
+	CheckTime time.Time
+	Latency   time.Duration
+}
+
+// Checker checks the dependencies, it reuses the results of the last checks
+// for the cache TTL
+type Checker struct {
+	dependencies []Dependency
+	cacheTTL     time.Duration
+	timeout      time.Duration
+
+	mu        sync.Mutex
+	checkTime time.Time
+	statuses  []*DependencyStatus
+	shutdown  bool
+}
+
+// NewChecker returns a checker of the dependencies, each check is cancelled
+// after timeout. The defaults are used for the unset durations.
+func NewChecker(cacheTTL time.Duration, timeout time.Duration, dependencies ...Dependency) *Checker {
+	c := &Checker{dependencies: dependencies}
+	c.Configure(cacheTTL, timeout)
+	return c
+}
+
+// Configure changes the cache TTL and the timeout of the checks, the defaults
+// are used for the unset durations
+func (c *Checker) Configure(cacheTTL time.Duration, timeout time.Duration) {
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cacheTTL = cacheTTL
+	c.timeout = timeout
+}
+
+// Shutdown marks the service as not serving, whatever the dependencies, so
+// that the traffic is drained before it stops
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shutdown = true
+}
+
+// Check returns whether the service is serving, i.e., all the dependencies
+// are serving and it is not shutting down, and the statuses of the
+// dependencies. The dependencies are checked concurrently if the cached
+// results are expired, the concurrent calls wait for the same checks.
+func (c *Checker) Check(ctx context.Context) (bool, []*DependencyStatus) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.statuses == nil || time.Since(c.checkTime) >= c.cacheTTL {
+		c.statuses = c.check(ctx)
+		c.checkTime = time.Now()
+	}
+
+	serving := !c.shutdown
+	statuses := make([]*DependencyStatus, len(c.statuses))
+	for idx, s := range c.statuses {
+		serving = serving && s.Serving
+		status := *s
+		statuses[idx] = &status
+	}
+	return serving, statuses
+}
+
+func (c *Checker) check(ctx context.Context) []*DependencyStatus {
+
+	statuses := make([]*DependencyStatus, len(c.dependencies))
+
+	var wg sync.WaitGroup
+	for idx, dep := range c.dependencies {
+		wg.Add(1)
+		go func(idx int, dep Dependency) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := dep.Check(checkCtx)
+			status := &DependencyStatus{
+				Name:      dep.Name,
+				Serving:   err == nil,
+				CheckTime: start,
+				Latency:   time.Since(start),
+			}
+			if err != nil {
+				status.Message = err.Error()
+			}
+			statuses[idx] = status
+		}(idx, dep)
+	}
+	wg.Wait()
+
+	return statuses
+}
+
+// Watch checks the dependencies at every cache TTL until ctx is done, and
+// sets the statuses of the gRPC health servers: the overall status for the
+// empty service name and the status of each dependency for its name
+func (c *Checker) Watch(ctx context.Context, servers ...*grpchealth.Server) {
+
+	update := func() {
+		serving, statuses := c.Check(ctx)
+		for _, server := range servers {
+			server.SetServingStatus("", servingStatus(serving))
+			for _, s := range statuses {
+				server.SetServingStatus(s.Name, servingStatus(s.Serving))
+			}
+		}
+	}
+
+	update()
+	for {
+		c.mu.Lock()
+		cacheTTL := c.cacheTTL
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cacheTTL):
+			update()
+		}
+	}
+}
+
+func servingStatus(serving bool) healthpb.HealthCheckResponse_ServingStatus {
+	if serving {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+// ConnectionCheck returns the check of a gRPC client connection, which is
+// serving once the connection is ready
+func ConnectionCheck(conn *grpc.ClientConn) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if conn == nil {
+			return fmt.Errorf("no connection")
+		}
+		conn.Connect()
+		for state := conn.GetState(); state != connectivity.Ready; state = conn.GetState() {
+			if state == connectivity.Shutdown || !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("connection to %s is %s", conn.Target(), state)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,158 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// countingCheck counts its calls and fails with the last set error
+type countingCheck struct {
+	calls atomic.Int32
+	err   atomic.Value
+}
+
+func (c *countingCheck) check(ctx context.Context) error {
+	c.calls.Add(1)
+	if err, ok := c.err.Load().(error); ok && err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *countingCheck) fail(err error) {
+	c.err.Store(err)
+}
+
+func TestCheckDependencies(t *testing.T) {
+	database, backend := &countingCheck{}, &countingCheck{}
+	backend.fail(errors.New("connection refused"))
+	c := NewChecker(time.Hour, time.Second,
+		Dependency{Name: "database", Check: database.check},
+		Dependency{Name: "backend", Check: backend.check},
+	)
+
+	serving, statuses := c.Check(context.Background())
+	if serving {
+		t.Fatalf("serving with a failed dependency")
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("statuses: got %d, want 2", len(statuses))
+	}
+	// The statuses are in the order of the dependencies, with the detail of the failure
+	if s := statuses[0]; s.Name != "database" || !s.Serving || s.Message != "" || s.CheckTime.IsZero() {
+		t.Errorf("database status: got %+v", s)
+	}
+	if s := statuses[1]; s.Name != "backend" || s.Serving || s.Message != "connection refused" {
+		t.Errorf("backend status: got %+v", s)
+	}
+
+	// The returned statuses are copies
+	statuses[1].Serving = true
+	if serving, _ := c.Check(context.Background()); serving {
+		t.Fatalf("the cached statuses are modified by the caller")
+	}
+}
+
+func TestCheckCache(t *testing.T) {
+	dep := &countingCheck{}
+	c := NewChecker(time.Hour, time.Second, Dependency{Name: "database", Check: dep.check})
+
+	for i := 0; i < 3; i++ {
+		if serving, _ := c.Check(context.Background()); !serving {
+			t.Fatalf("check %d: not serving", i)
+		}
+	}
+	if calls := dep.calls.Load(); calls != 1 {
+		t.Fatalf("checks within the cache TTL: got %d calls, want 1", calls)
+	}
+
+	// A failure is only seen once the cached result expires
+	dep.fail(errors.New("down"))
+	if serving, _ := c.Check(context.Background()); !serving {
+		t.Fatalf("the cached result is not used")
+	}
+	c.Configure(time.Nanosecond, time.Second)
+	time.Sleep(time.Millisecond)
+	if serving, _ := c.Check(context.Background()); serving {
+		t.Fatalf("the expired result is used")
+	}
+	if calls := dep.calls.Load(); calls != 2 {
+		t.Fatalf("checks after the cache TTL: got %d calls, want 2", calls)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	c := NewChecker(time.Hour, 10*time.Millisecond, Dependency{Name: "slow", Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	start := time.Now()
+	serving, statuses := c.Check(context.Background())
+	if serving || statuses[0].Message != context.DeadlineExceeded.Error() {
+		t.Fatalf("slow dependency: got serving %v and %+v", serving, statuses[0])
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("slow dependency: checked in %s, want the timeout", elapsed)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	c := NewChecker(time.Hour, time.Second, Dependency{Name: "database", Check: (&countingCheck{}).check})
+	c.Shutdown()
+
+	// The dependencies are still reported while the service drains its traffic
+	serving, statuses := c.Check(context.Background())
+	if serving || len(statuses) != 1 || !statuses[0].Serving {
+		t.Fatalf("shutting down: got serving %v and %+v", serving, statuses)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	backend := &countingCheck{}
+	backend.fail(errors.New("down"))
+	c := NewChecker(time.Hour, time.Second,
+		Dependency{Name: "database", Check: (&countingCheck{}).check},
+		Dependency{Name: "backend", Check: backend.check},
+	)
+	server := grpchealth.NewServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Watch(ctx, server)
+		close(done)
+	}()
+
+	want := map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":         healthpb.HealthCheckResponse_NOT_SERVING,
+		"database": healthpb.HealthCheckResponse_SERVING,
+		"backend":  healthpb.HealthCheckResponse_NOT_SERVING,
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for service, status := range want {
+		for {
+			resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+			if err == nil && resp.GetStatus() == status {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("status of %q: got %v and %v, want %v", service, resp.GetStatus(), err, status)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Watch does not return once its context is done")
+	}
+}
@@ -0,0 +1,162 @@
+// Package lifecycle shuts the service down in order: the readiness is moved
+// to NOT_SERVING, the traffic is drained, then the servers and the background
+// workers are stopped, and the telemetry is flushed last.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/instill-ai/connector-backend/pkg/logger"
+)
+
+// Defaults of the drain period and the shutdown timeout
+const (
+	DefaultDrainPeriod = 5 * time.Second
+	DefaultTimeout     = 30 * time.Second
+)
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Manager runs the background workers and the shutdown hooks
+type Manager struct {
+	drainPeriod time.Duration
+	timeout     time.Duration
+
+	// ctx is the context of the workers, cancelled to stop them
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+
+	mu      sync.Mutex
+	drains  []func()
+	stops   []hook
+	flushes []hook
+}
+
+// NewManager returns a lifecycle manager, the defaults are used for the unset
+// durations. The workers run with a context derived from ctx.
+func NewManager(ctx context.Context, drainPeriod time.Duration, timeout time.Duration) *Manager {
+	if drainPeriod <= 0 {
+		drainPeriod = DefaultDrainPeriod
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	m := &Manager{
+		drainPeriod: drainPeriod,
+		timeout:     timeout,
+	}
+	m.ctx, m.cancel = context.WithCancel(ctx)
+	return m
+}
+
+// Go runs a background worker until its context is cancelled at shutdown
+func (m *Manager) Go(fn func(ctx context.Context)) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		fn(m.ctx)
+	}()
+}
+
+// OnDrain registers a hook called when the shutdown starts, e.g., to move
+// the readiness to NOT_SERVING
+func (m *Manager) OnDrain(fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.drains = append(m.drains, fn)
+}
+
+// OnStop registers a hook called after the drain period, the stop hooks are
+// called in order of registration before the workers are stopped
+func (m *Manager) OnStop(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stops = append(m.stops, hook{name: name, fn: fn})
+}
+
+// OnFlush registers a hook called once the workers are stopped, the flush
+// hooks are called in order of registration
+func (m *Manager) OnFlush(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.flushes = append(m.flushes, hook{name: name, fn: fn})
+}
+
+// Shutdown shuts the service down. The stop hooks and the workers share the
+// shutdown timeout, the flush hooks have their own so that the telemetry is
+// flushed even if a server did not stop in time.
+func (m *Manager) Shutdown() {
+
+	logger, _ := logger.GetZapLogger(m.ctx)
+
+	m.mu.Lock()
+	drains, stops, flushes := m.drains, m.stops, m.flushes
+	m.mu.Unlock()
+
+	for _, fn := range drains {
+		fn()
+	}
+	if m.drainPeriod > 0 {
+		logger.Info(fmt.Sprintf("draining the traffic for %v", m.drainPeriod))
+		time.Sleep(m.drainPeriod)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	for _, h := range stops {
+		if err := h.fn(ctx); err != nil {
+			logger.Error(fmt.Sprintf("stop %s: %s", h.name, err.Error()))
+		}
+	}
+
+	m.cancel()
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.Error("stop the workers: the shutdown timeout is exceeded")
+	}
+
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), m.timeout)
+	defer flushCancel()
+
+	for _, h := range flushes {
+		if err := h.fn(flushCtx); err != nil {
+			logger.Error(fmt.Sprintf("flush %s: %s", h.name, err.Error()))
+		}
+	}
+}
+
+// StopGRPC returns the stop hook of a gRPC server, which stops gracefully
+// until the context is done and is then stopped
+func StopGRPC(server interface {
+	GracefulStop()
+	Stop()
+}) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			server.Stop()
+			return ctx.Err()
+		}
+	}
+}
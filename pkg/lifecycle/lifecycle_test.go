@@ -0,0 +1,152 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder records the shutdown steps in order
+type recorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *recorder) record(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.steps...)
+}
+
+func TestShutdownOrder(t *testing.T) {
+	r := &recorder{}
+	m := NewManager(context.Background(), time.Millisecond, time.Second)
+
+	started := make(chan struct{})
+	m.Go(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		r.record("worker")
+	})
+	<-started
+
+	m.OnFlush("telemetry", func(ctx context.Context) error {
+		r.record("flush telemetry")
+		return nil
+	})
+	m.OnStop("grpc", func(ctx context.Context) error {
+		r.record("stop grpc")
+		return nil
+	})
+	m.OnDrain(func() { r.record("drain") })
+	// A failed hook does not stop the shutdown
+	m.OnStop("http", func(ctx context.Context) error {
+		r.record("stop http")
+		return errors.New("failed")
+	})
+
+	m.Shutdown()
+
+	want := []string{"drain", "stop grpc", "stop http", "worker", "flush telemetry"}
+	if got := r.get(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("shutdown steps: got %v, want %v", got, want)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	r := &recorder{}
+	timeout := 50 * time.Millisecond
+	m := NewManager(context.Background(), time.Millisecond, timeout)
+
+	// A stuck stop hook and a worker ignoring its cancellation share the
+	// shutdown timeout
+	m.OnStop("stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		r.record("stop stuck")
+		return ctx.Err()
+	})
+	block := make(chan struct{})
+	defer close(block)
+	m.Go(func(ctx context.Context) {
+		<-block
+	})
+	// The flush hooks have their own timeout
+	m.OnFlush("telemetry", func(ctx context.Context) error {
+		if err := ctx.Err(); err != nil {
+			r.record("flush expired")
+			return err
+		}
+		r.record("flush telemetry")
+		return nil
+	})
+
+	start := time.Now()
+	m.Shutdown()
+	if elapsed := time.Since(start); elapsed > 10*timeout {
+		t.Fatalf("shutdown: took %s, want about the timeout %s", elapsed, timeout)
+	}
+
+	want := []string{"stop stuck", "flush telemetry"}
+	if got := r.get(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("shutdown steps: got %v, want %v", got, want)
+	}
+}
+
+func TestNewManagerDefaults(t *testing.T) {
+	m := NewManager(context.Background(), 0, -1)
+	if m.drainPeriod != DefaultDrainPeriod || m.timeout != DefaultTimeout {
+		t.Fatalf("defaults: got drain period %s and timeout %s", m.drainPeriod, m.timeout)
+	}
+}
+
+// fakeServer is a gRPC server whose graceful stop waits for release
+type fakeServer struct {
+	release chan struct{}
+	stopped chan struct{}
+}
+
+func (s *fakeServer) GracefulStop() {
+	select {
+	case <-s.release:
+	case <-s.stopped:
+	}
+}
+
+func (s *fakeServer) Stop() {
+	close(s.stopped)
+}
+
+func TestStopGRPC(t *testing.T) {
+	graceful := &fakeServer{release: make(chan struct{}), stopped: make(chan struct{})}
+	close(graceful.release)
+	if err := StopGRPC(graceful)(context.Background()); err != nil {
+		t.Fatalf("graceful stop: %v", err)
+	}
+	select {
+	case <-graceful.stopped:
+		t.Fatalf("a gracefully stopped server is stopped")
+	default:
+	}
+
+	// The server is stopped once the context is done
+	stuck := &fakeServer{release: make(chan struct{}), stopped: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := StopGRPC(stuck)(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("stop of a stuck server: got %v, want DeadlineExceeded", err)
+	}
+	select {
+	case <-stuck.stopped:
+	default:
+		t.Fatalf("a stuck server is not stopped")
+	}
+}
@@ -15,60 +15,69 @@ import (
 )
 
 var once sync.Once
-var core zapcore.Core
+var encoderConfig zapcore.EncoderConfig
+var stdoutSyncer, stderrSyncer zapcore.WriteSyncer
 
-// GetZapLogger returns an instance of zap logger
+// level is the minimum level of the logs, which can be changed at runtime
+var level = zap.NewAtomicLevel()
+
+// Level returns the level of the logs, it serves the level as JSON over HTTP
+func Level() zap.AtomicLevel {
+	return level
+}
+
+// GetZapLogger returns an instance of zap logger. The logger of a request
+// context adds the request ID, the trace ID and the owner to the logs, and
+// logs at debug level if the debug logs are enabled for the request.
 func GetZapLogger(ctx context.Context) (*zap.Logger, error) {
 	var err error
 	once.Do(func() {
-		// debug and info level enabler
-		debugInfoLevel := zap.LevelEnablerFunc(func(level zapcore.Level) bool {
-			return level == zapcore.DebugLevel || level == zapcore.InfoLevel
-		})
+		if config.Config.Server.Debug {
+			encoderConfig = zap.NewDevelopmentEncoderConfig()
+			level.SetLevel(zapcore.DebugLevel)
+		} else {
+			encoderConfig = zap.NewProductionEncoderConfig()
+			level.SetLevel(zapcore.InfoLevel)
+		}
 
-		// info level enabler
-		infoLevel := zap.LevelEnablerFunc(func(level zapcore.Level) bool {
-			return level == zapcore.InfoLevel
-		})
+		// write syncers
+		stdoutSyncer = zapcore.Lock(os.Stdout)
+		stderrSyncer = zapcore.Lock(os.Stderr)
 
-		// warn, error and fatal level enabler
-		warnErrorFatalLevel := zap.LevelEnablerFunc(func(level zapcore.Level) bool {
-			return level == zapcore.WarnLevel || level == zapcore.ErrorLevel || level == zapcore.FatalLevel
+		// A reloaded debug setting overrides the level set at runtime
+		config.Subscribe(func(prev *config.AppConfig, next *config.AppConfig) {
+			if prev.Server.Debug == next.Server.Debug {
+				return
+			}
+			if next.Server.Debug {
+				level.SetLevel(zapcore.DebugLevel)
+			} else {
+				level.SetLevel(zapcore.InfoLevel)
+			}
 		})
+	})
 
-		// write syncers
-		stdoutSyncer := zapcore.Lock(os.Stdout)
-		stderrSyncer := zapcore.Lock(os.Stderr)
+	info := requestInfoFromContext(ctx)
+	enabled := func(l zapcore.Level) bool {
+		return level.Enabled(l) || info.isDebug()
+	}
 
-		// tee core
-		if config.Config.Server.Debug {
-			core = zapcore.NewTee(
-				zapcore.NewCore(
-					zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()),
-					stdoutSyncer,
-					debugInfoLevel,
-				),
-				zapcore.NewCore(
-					zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()),
-					stderrSyncer,
-					warnErrorFatalLevel,
-				),
-			)
-		} else {
-			core = zapcore.NewTee(
-				zapcore.NewCore(
-					zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
-					stdoutSyncer,
-					infoLevel,
-				),
-				zapcore.NewCore(
-					zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
-					stderrSyncer,
-					warnErrorFatalLevel,
-				),
-			)
-		}
+	// debug and info level enabler
+	debugInfoLevel := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l < zapcore.WarnLevel && enabled(l)
 	})
+
+	// warn, error and fatal level enabler
+	warnErrorFatalLevel := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l >= zapcore.WarnLevel && enabled(l)
+	})
+
+	// tee core
+	core := zapcore.NewTee(
+		newRequestCore(zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), stdoutSyncer, debugInfoLevel), info),
+		newRequestCore(zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), stderrSyncer, warnErrorFatalLevel), info),
+	)
+
 	// finally construct the logger with the tee core
 	// and add hooks to inject logs to traces
 	logger := zap.New(core).WithOptions(
@@ -97,5 +106,12 @@ func GetZapLogger(ctx context.Context) (*zap.Logger, error) {
 			return nil
 		}))
 
+	if info != nil {
+		logger = logger.With(zap.String("requestID", info.requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		logger = logger.With(zap.String("traceID", spanContext.TraceID().String()))
+	}
+
 	return logger, err
 }
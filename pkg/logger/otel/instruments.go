@@ -0,0 +1,228 @@
+package otel
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// meterName is the instrumentation scope of the connector-backend metrics
+const meterName = "github.com/instill-ai/connector-backend"
+
+// Metric attribute keys
+const (
+	AttrConnectorDefinition = attribute.Key("connector.definition")
+	AttrConnectorTask       = attribute.Key("connector.task")
+	AttrConnectorState      = attribute.Key("connector.state")
+	AttrConnectorType       = attribute.Key("connector.type")
+	AttrOutcome             = attribute.Key("outcome")
+	AttrDBOperation         = attribute.Key("db.operation")
+	AttrDBTable             = attribute.Key("db.sql.table")
+	AttrRPCService          = attribute.Key("rpc.service")
+	AttrRPCMethod           = attribute.Key("rpc.method")
+	AttrRPCStatusCode       = attribute.Key("rpc.grpc.status_code")
+)
+
+// Outcomes of the recorded operations
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// instruments are the metric instruments of the connector operations
+type instruments struct {
+	meter metric.Meter
+	// executeDuration is the latency of the connector executions, the error
+	// rate is the share of the executions with the error outcome
+	executeDuration   metric.Float64Histogram
+	executeInputItems metric.Int64Histogram
+	checkOutcomes     metric.Int64Counter
+	dbQueryDuration   metric.Float64Histogram
+	rpcClientDuration metric.Float64Histogram
+	rpcServerDuration metric.Float64Histogram
+}
+
+// current holds the instruments of the meter provider set up last, they are
+// no-op until SetupMetrics is called
+var current atomic.Pointer[instruments]
+
+func init() {
+	i, err := newInstruments(noop.NewMeterProvider().Meter(meterName))
+	if err != nil {
+		panic(err)
+	}
+	current.Store(i)
+}
+
+func newInstruments(meter metric.Meter) (*instruments, error) {
+
+	i := &instruments{meter: meter}
+	var err error
+
+	if i.executeDuration, err = meter.Float64Histogram(
+		"connector.execute.duration",
+		metric.WithDescription("Duration of the connector executions"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+	if i.executeInputItems, err = meter.Int64Histogram(
+		"connector.execute.input_items",
+		metric.WithDescription("Number of input items of the connector executions"),
+		metric.WithUnit("{item}"),
+	); err != nil {
+		return nil, err
+	}
+	if i.checkOutcomes, err = meter.Int64Counter(
+		"connector.check.count",
+		metric.WithDescription("Connection checks of the connectors by resulting state"),
+		metric.WithUnit("{check}"),
+	); err != nil {
+		return nil, err
+	}
+	if i.dbQueryDuration, err = meter.Float64Histogram(
+		"db.query.duration",
+		metric.WithDescription("Duration of the database queries"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+	if i.rpcClientDuration, err = meter.Float64Histogram(
+		"rpc.client.duration",
+		metric.WithDescription("Duration of the outgoing gRPC calls"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+	if i.rpcServerDuration, err = meter.Float64Histogram(
+		"rpc.server.duration",
+		metric.WithDescription("Duration of the gRPC calls served, including those of the gateway"),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func outcome(err error) attribute.KeyValue {
+	if err != nil {
+		return AttrOutcome.String(OutcomeError)
+	}
+	return AttrOutcome.String(OutcomeSuccess)
+}
+
+// RecordConnectorExecution records the latency and the input items of a
+// connector execution
+func RecordConnectorExecution(ctx context.Context, connDefID string, task string, inputItems int, duration time.Duration, err error) {
+	i := current.Load()
+	attrs := []attribute.KeyValue{AttrConnectorDefinition.String(connDefID), AttrConnectorTask.String(task)}
+	i.executeDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(append(attrs, outcome(err))...))
+	i.executeInputItems.Record(ctx, int64(inputItems), metric.WithAttributes(attrs...))
+}
+
+// RecordConnectorCheck records the state resulting from a connection check
+func RecordConnectorCheck(ctx context.Context, connDefID string, state string) {
+	current.Load().checkOutcomes.Add(ctx, 1, metric.WithAttributes(
+		AttrConnectorDefinition.String(connDefID),
+		AttrConnectorState.String(state),
+	))
+}
+
+// RecordDBQuery records the latency of a database query
+func RecordDBQuery(ctx context.Context, operation string, table string, duration time.Duration, err error) {
+	current.Load().dbQueryDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(
+		AttrDBOperation.String(operation),
+		AttrDBTable.String(table),
+		outcome(err),
+	))
+}
+
+// ConnectorStateCount is the number of connectors of a type in a state
+type ConnectorStateCount struct {
+	ConnectorType string
+	State         string
+	Count         int64
+}
+
+// RegisterConnectorStateGauge registers the gauge of the connectors per
+// state, which calls count at every collection
+func RegisterConnectorStateGauge(count func(ctx context.Context) ([]*ConnectorStateCount, error)) error {
+	i := current.Load()
+	gauge, err := i.meter.Int64ObservableGauge(
+		"connector.state.count",
+		metric.WithDescription("Number of connectors per type and state"),
+		metric.WithUnit("{connector}"),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = i.meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		counts, err := count(ctx)
+		if err != nil {
+			return err
+		}
+		for _, c := range counts {
+			o.ObserveInt64(gauge, c.Count, metric.WithAttributes(
+				AttrConnectorType.String(c.ConnectorType),
+				AttrConnectorState.String(c.State),
+			))
+		}
+		return nil
+	}, gauge)
+	return err
+}
+
+// UnaryClientInterceptor records the latency of the outgoing unary gRPC calls
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	service, name := splitMethodName(method)
+	current.Load().rpcClientDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		AttrRPCService.String(service),
+		AttrRPCMethod.String(name),
+		AttrRPCStatusCode.Int64(int64(status.Code(err))),
+	))
+	return err
+}
+
+// UnaryServerInterceptor records the latency of the unary gRPC calls served
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	recordServerCall(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+// StreamServerInterceptor records the duration of the gRPC streams served
+func StreamServerInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	recordServerCall(stream.Context(), info.FullMethod, start, err)
+	return err
+}
+
+func recordServerCall(ctx context.Context, fullMethod string, start time.Time, err error) {
+	service, name := splitMethodName(fullMethod)
+	current.Load().rpcServerDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		AttrRPCService.String(service),
+		AttrRPCMethod.String(name),
+		AttrRPCStatusCode.Int64(int64(status.Code(err))),
+	))
+}
+
+// splitMethodName splits a full method name, e.g., /package.Service/Method
+func splitMethodName(fullMethod string) (string, string) {
+	for idx := len(fullMethod) - 1; idx > 0; idx-- {
+		if fullMethod[idx] == '/' {
+			return fullMethod[1:idx], fullMethod[idx+1:]
+		}
+	}
+	return "unknown", fullMethod
+}
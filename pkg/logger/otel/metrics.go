@@ -5,32 +5,55 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/instill-ai/connector-backend/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 )
 
-func SetupMetrics(ctx context.Context, serviceName string) (*sdkmetric.MeterProvider, error) {
+// prometheusHandler serves the metrics of the Prometheus exporter, nil if it is disabled
+var prometheusHandler atomic.Pointer[http.Handler]
+
+// SetupMetrics sets up the meter provider and the instruments of the
+// connector operations. The metrics are exported to the collector if the logs
+// are external and the OTLP exporter is enabled, to the standard output if
+// the logs are not external, and served to Prometheus by PrometheusHandler if
+// the Prometheus exporter is enabled. The additional readers, e.g., a
+// sdkmetric.NewManualReader in tests, collect the same metrics.
+func SetupMetrics(ctx context.Context, serviceName string, readers ...sdkmetric.Reader) (*sdkmetric.MeterProvider, error) {
+	metricsConfig := config.Config.Log.Metrics
+
 	var exporter sdkmetric.Exporter
 	var err error
 	if config.Config.Log.External {
-		exporter, err = otlpmetricgrpc.New(
-			ctx,
-			otlpmetricgrpc.WithEndpoint(fmt.Sprintf("%s:%s", config.Config.Log.OtelCollector.Host, config.Config.Log.OtelCollector.Port)),
-			otlpmetricgrpc.WithInsecure(),
-		)
-		if err != nil {
-			return nil, err
+		if metricsConfig.OTLP {
+			exporter, err = otlpmetricgrpc.New(
+				ctx,
+				otlpmetricgrpc.WithEndpoint(fmt.Sprintf("%s:%s", config.Config.Log.OtelCollector.Host, config.Config.Log.OtelCollector.Port)),
+				otlpmetricgrpc.WithInsecure(),
+			)
+			if err != nil {
+				return nil, err
+			}
 		}
 	} else {
+		var w io.Writer = io.Discard
+		if metricsConfig.Stdout {
+			w = os.Stdout
+		}
 		exporter, err = stdoutmetric.New(
-			stdoutmetric.WithEncoder(json.NewEncoder(io.Discard)),
+			stdoutmetric.WithEncoder(json.NewEncoder(w)),
 			stdoutmetric.WithoutTimestamps(),
 		)
 		if err != nil {
@@ -44,15 +67,49 @@ func SetupMetrics(ctx context.Context, serviceName string) (*sdkmetric.MeterProv
 		semconv.ServiceNameKey.String(serviceName),
 	)
 
-	mp := sdkmetric.NewMeterProvider(
+	options := []sdkmetric.Option{
 		sdkmetric.WithResource(resource),
-		sdkmetric.WithReader(
-			// collects and exports metric data every 10 seconds.
+	}
+	if exporter != nil {
+		// collects and exports metric data every 10 seconds.
+		options = append(options, sdkmetric.WithReader(
 			sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(10*time.Second)),
-		),
-	)
+		))
+	}
+	if metricsConfig.Prometheus {
+		registry := prometheus.NewRegistry()
+		reader, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdkmetric.WithReader(reader))
+		handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+		prometheusHandler.Store(&handler)
+	} else {
+		prometheusHandler.Store(nil)
+	}
+	for _, reader := range readers {
+		options = append(options, sdkmetric.WithReader(reader))
+	}
+	mp := sdkmetric.NewMeterProvider(options...)
 
 	otel.SetMeterProvider(mp)
 
+	i, err := newInstruments(mp.Meter(meterName))
+	if err != nil {
+		return nil, err
+	}
+	current.Store(i)
+
 	return mp, nil
 }
+
+// PrometheusHandler returns the handler serving the metrics in the
+// Prometheus text format, false if the Prometheus exporter is disabled
+func PrometheusHandler() (http.Handler, bool) {
+	handler := prometheusHandler.Load()
+	if handler == nil {
+		return nil, false
+	}
+	return *handler, true
+}
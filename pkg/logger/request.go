@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type requestInfoKey struct{}
+
+// requestInfo is the information of a request added to its logs, the owner
+// and the debug logs are set once the owner is resolved
+type requestInfo struct {
+	requestID string
+	mu        sync.RWMutex
+	owner     string
+	debug     bool
+}
+
+// NewRequestContext returns the context of a request with the given ID, a
+// new one if requestID is nil
+func NewRequestContext(ctx context.Context, requestID uuid.UUID) context.Context {
+	if requestID == uuid.Nil {
+		requestID, _ = uuid.NewV4()
+	}
+	return context.WithValue(ctx, requestInfoKey{}, &requestInfo{requestID: requestID.String()})
+}
+
+// RequestID returns the ID of the request of the context, a new one if the
+// context is not a request context
+func RequestID(ctx context.Context) uuid.UUID {
+	if info := requestInfoFromContext(ctx); info != nil {
+		return uuid.FromStringOrNil(info.requestID)
+	}
+	requestID, _ := uuid.NewV4()
+	return requestID
+}
+
+// SetOwner sets the owner permalink added to the logs of the request
+func SetOwner(ctx context.Context, owner string) {
+	if info := requestInfoFromContext(ctx); info != nil {
+		info.mu.Lock()
+		info.owner = owner
+		info.mu.Unlock()
+	}
+}
+
+// EnableDebug enables the debug logs of the request, whatever the level
+func EnableDebug(ctx context.Context) {
+	if info := requestInfoFromContext(ctx); info != nil {
+		info.mu.Lock()
+		info.debug = true
+		info.mu.Unlock()
+	}
+}
+
+func requestInfoFromContext(ctx context.Context) *requestInfo {
+	if ctx == nil {
+		return nil
+	}
+	info, _ := ctx.Value(requestInfoKey{}).(*requestInfo)
+	return info
+}
+
+func (info *requestInfo) isDebug() bool {
+	if info == nil {
+		return false
+	}
+	info.mu.RLock()
+	defer info.mu.RUnlock()
+	return info.debug
+}
+
+func (info *requestInfo) getOwner() string {
+	if info == nil {
+		return ""
+	}
+	info.mu.RLock()
+	defer info.mu.RUnlock()
+	return info.owner
+}
+
+// requestCore adds the owner of the request to the logs when they are
+// written, as it is resolved after the logger is built
+type requestCore struct {
+	zapcore.Core
+	info *requestInfo
+}
+
+func newRequestCore(core zapcore.Core, info *requestInfo) zapcore.Core {
+	if info == nil {
+		return core
+	}
+	return &requestCore{Core: core, info: info}
+}
+
+func (c *requestCore) With(fields []zapcore.Field) zapcore.Core {
+	return &requestCore{Core: c.Core.With(fields), info: c.info}
+}
+
+func (c *requestCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *requestCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if owner := c.info.getOwner(); owner != "" {
+		fields = append(fields, zap.String("owner", owner))
+	}
+	return c.Core.Write(entry, fields)
+}
@@ -2,16 +2,22 @@ package middleware
 
 import (
 	"context"
+	"strconv"
 
+	"github.com/gofrs/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/instill-ai/connector-backend/internal/resource"
 	"github.com/instill-ai/connector-backend/pkg/constant"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/service"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
 )
 
 // RecoveryInterceptor - panic handler
@@ -21,8 +27,8 @@ func RecoveryInterceptorOpt() grpc_recovery.Option {
 	})
 }
 
-// CustomInterceptor - append metadatas for unary
-func UnaryAppendMetadataInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+// AppendMetadata appends the custom metadatas to the incoming context
+func AppendMetadata(ctx context.Context) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Error(codes.Internal, "can not extract metadata")
@@ -30,7 +36,16 @@ func UnaryAppendMetadataInterceptor(ctx context.Context, req interface{}, info *
 
 	md.Append(constant.HeaderOwnerIDKey, constant.DefaultOwnerID)
 
-	newCtx := metadata.NewIncomingContext(ctx, md)
+	return metadata.NewIncomingContext(ctx, md), nil
+}
+
+// CustomInterceptor - append metadatas for unary
+func UnaryAppendMetadataInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	newCtx, err := AppendMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	h, err := handler(newCtx, req)
 
 	return h, err
@@ -38,18 +53,48 @@ func UnaryAppendMetadataInterceptor(ctx context.Context, req interface{}, info *
 
 // CustomInterceptor - append metadatas for stream
 func StreamAppendMetadataInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	md, ok := metadata.FromIncomingContext(stream.Context())
-	if !ok {
-		return status.Error(codes.Internal, "can not extract metadata")
+	newCtx, err := AppendMetadata(stream.Context())
+	if err != nil {
+		return err
 	}
 
-	md.Append(constant.HeaderOwnerIDKey, constant.DefaultOwnerID)
-
-	newCtx := metadata.NewIncomingContext(stream.Context(), md)
 	wrapped := grpc_middleware.WrapServerStream(stream)
 	wrapped.WrappedContext = newCtx
 
-	err := handler(srv, wrapped)
+	err = handler(srv, wrapped)
 
 	return err
 }
+
+// NewRequestContext returns the context of an incoming request, whose logs
+// carry the request ID of the x-request-id header, a new one if it is not a
+// UUID. The debug logs of the request are enabled by the x-debug-log header
+// of an admin only.
+func NewRequestContext(ctx context.Context, client mgmtPB.MgmtPrivateServiceClient) context.Context {
+	requestID := uuid.FromStringOrNil(resource.GetRequestSingleHeader(ctx, constant.HeaderRequestIDKey))
+	ctx = logger.NewRequestContext(ctx, requestID)
+
+	if debug, _ := strconv.ParseBool(resource.GetRequestSingleHeader(ctx, constant.HeaderDebugLogKey)); debug {
+		if owner, err := resource.GetOwner(ctx, client); err == nil && service.IsAdmin(owner) {
+			logger.EnableDebug(ctx)
+		}
+	}
+
+	return ctx
+}
+
+// UnaryRequestContextInterceptor - set up the request context for unary
+func UnaryRequestContextInterceptor(client mgmtPB.MgmtPrivateServiceClient) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(NewRequestContext(ctx, client), req)
+	}
+}
+
+// StreamRequestContextInterceptor - set up the request context for stream
+func StreamRequestContextInterceptor(client mgmtPB.MgmtPrivateServiceClient) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = NewRequestContext(stream.Context(), client)
+		return handler(srv, wrapped)
+	}
+}
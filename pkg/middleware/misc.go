@@ -116,7 +116,7 @@ func ErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.
 			switch v := s.Details()[0].(type) {
 			case *errdetails.PreconditionFailure:
 				switch v.Violations[0].Type {
-				case "UPDATE", "DELETE", "STATE", "RENAME":
+				case "CREATE", "UPDATE", "DELETE", "STATE", "RENAME", "CLONE":
 					httpStatus = http.StatusUnprocessableEntity
 				}
 			}
@@ -146,6 +146,11 @@ func CustomMatcher(key string) (string, bool) {
 		return key, true
 	}
 
+	switch strings.ToLower(key) {
+	case constant.HeaderRequestIDKey, constant.HeaderDebugLogKey:
+		return strings.ToLower(key), true
+	}
+
 	switch key {
 	case "request-id":
 		return key, true
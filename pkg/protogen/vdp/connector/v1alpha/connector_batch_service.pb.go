@@ -0,0 +1,829 @@
+// The Go code is generated from the pkg/protogen directory, with the
+// instill-ai/protobufs repository as an import path, by
+//
+//   protoc -I . -I <protobufs> \
+//     --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//     vdp/connector/v1alpha/connector_batch_service.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: vdp/connector/v1alpha/connector_batch_service.proto
+
+package connectorv1alpha
+
+import (
+	v1alpha "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// BatchConnectorResult represents the outcome of a single item of a batch
+// request
+type BatchConnectorResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Connector is the resulting connector, unset if the item failed or was
+	// deleted
+	Connector *v1alpha.Connector `protobuf:"bytes,1,opt,name=connector,proto3" json:"connector,omitempty"`
+	// Status is the status of the item
+	Status *status.Status `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *BatchConnectorResult) Reset() {
+	*x = BatchConnectorResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchConnectorResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConnectorResult) ProtoMessage() {}
+
+func (x *BatchConnectorResult) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConnectorResult.ProtoReflect.Descriptor instead.
+func (*BatchConnectorResult) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BatchConnectorResult) GetConnector() *v1alpha.Connector {
+	if x != nil {
+		return x.Connector
+	}
+	return nil
+}
+
+func (x *BatchConnectorResult) GetStatus() *status.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+// BatchCreateConnectorsRequest represents a request to create connectors in
+// batch
+type BatchCreateConnectorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Connectors to create
+	Connectors []*v1alpha.Connector `protobuf:"bytes,1,rep,name=connectors,proto3" json:"connectors,omitempty"`
+	// AllOrNothing creates either all the connectors or none of them
+	AllOrNothing bool `protobuf:"varint,2,opt,name=all_or_nothing,json=allOrNothing,proto3" json:"all_or_nothing,omitempty"`
+}
+
+func (x *BatchCreateConnectorsRequest) Reset() {
+	*x = BatchCreateConnectorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCreateConnectorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateConnectorsRequest) ProtoMessage() {}
+
+func (x *BatchCreateConnectorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateConnectorsRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateConnectorsRequest) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BatchCreateConnectorsRequest) GetConnectors() []*v1alpha.Connector {
+	if x != nil {
+		return x.Connectors
+	}
+	return nil
+}
+
+func (x *BatchCreateConnectorsRequest) GetAllOrNothing() bool {
+	if x != nil {
+		return x.AllOrNothing
+	}
+	return false
+}
+
+// BatchCreateConnectorsResponse represents a response for creating
+// connectors in batch
+type BatchCreateConnectorsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Results in the order of the requested connectors
+	Results []*BatchConnectorResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *BatchCreateConnectorsResponse) Reset() {
+	*x = BatchCreateConnectorsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCreateConnectorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateConnectorsResponse) ProtoMessage() {}
+
+func (x *BatchCreateConnectorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateConnectorsResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateConnectorsResponse) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchCreateConnectorsResponse) GetResults() []*BatchConnectorResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// BatchDeleteConnectorsRequest represents a request to delete connectors in
+// batch
+type BatchDeleteConnectorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Names of the connectors to delete, e.g., connectors/{id}
+	Names []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	// AllOrNothing deletes either all the connectors or none of them
+	AllOrNothing bool `protobuf:"varint,2,opt,name=all_or_nothing,json=allOrNothing,proto3" json:"all_or_nothing,omitempty"`
+}
+
+func (x *BatchDeleteConnectorsRequest) Reset() {
+	*x = BatchDeleteConnectorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchDeleteConnectorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteConnectorsRequest) ProtoMessage() {}
+
+func (x *BatchDeleteConnectorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteConnectorsRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteConnectorsRequest) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchDeleteConnectorsRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *BatchDeleteConnectorsRequest) GetAllOrNothing() bool {
+	if x != nil {
+		return x.AllOrNothing
+	}
+	return false
+}
+
+// BatchDeleteConnectorsResponse represents a response for deleting
+// connectors in batch
+type BatchDeleteConnectorsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Results in the order of the requested names
+	Results []*BatchConnectorResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *BatchDeleteConnectorsResponse) Reset() {
+	*x = BatchDeleteConnectorsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchDeleteConnectorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteConnectorsResponse) ProtoMessage() {}
+
+func (x *BatchDeleteConnectorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteConnectorsResponse.ProtoReflect.Descriptor instead.
+func (*BatchDeleteConnectorsResponse) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchDeleteConnectorsResponse) GetResults() []*BatchConnectorResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// BatchConnectConnectorsRequest represents a request to connect connectors in
+// batch
+type BatchConnectConnectorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Names of the connectors to connect, e.g., connectors/{id}
+	Names []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	// AllOrNothing connects either all the connectors or none of them
+	AllOrNothing bool `protobuf:"varint,2,opt,name=all_or_nothing,json=allOrNothing,proto3" json:"all_or_nothing,omitempty"`
+}
+
+func (x *BatchConnectConnectorsRequest) Reset() {
+	*x = BatchConnectConnectorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchConnectConnectorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConnectConnectorsRequest) ProtoMessage() {}
+
+func (x *BatchConnectConnectorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConnectConnectorsRequest.ProtoReflect.Descriptor instead.
+func (*BatchConnectConnectorsRequest) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchConnectConnectorsRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *BatchConnectConnectorsRequest) GetAllOrNothing() bool {
+	if x != nil {
+		return x.AllOrNothing
+	}
+	return false
+}
+
+// BatchConnectConnectorsResponse represents a response for connecting
+// connectors in batch
+type BatchConnectConnectorsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Results in the order of the requested names
+	Results []*BatchConnectorResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *BatchConnectConnectorsResponse) Reset() {
+	*x = BatchConnectConnectorsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchConnectConnectorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchConnectConnectorsResponse) ProtoMessage() {}
+
+func (x *BatchConnectConnectorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchConnectConnectorsResponse.ProtoReflect.Descriptor instead.
+func (*BatchConnectConnectorsResponse) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchConnectConnectorsResponse) GetResults() []*BatchConnectorResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// BatchDisconnectConnectorsRequest represents a request to disconnect
+// connectors in batch
+type BatchDisconnectConnectorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Names of the connectors to disconnect, e.g., connectors/{id}
+	Names []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	// AllOrNothing disconnects either all the connectors or none of them
+	AllOrNothing bool `protobuf:"varint,2,opt,name=all_or_nothing,json=allOrNothing,proto3" json:"all_or_nothing,omitempty"`
+}
+
+func (x *BatchDisconnectConnectorsRequest) Reset() {
+	*x = BatchDisconnectConnectorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchDisconnectConnectorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDisconnectConnectorsRequest) ProtoMessage() {}
+
+func (x *BatchDisconnectConnectorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDisconnectConnectorsRequest.ProtoReflect.Descriptor instead.
+func (*BatchDisconnectConnectorsRequest) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchDisconnectConnectorsRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *BatchDisconnectConnectorsRequest) GetAllOrNothing() bool {
+	if x != nil {
+		return x.AllOrNothing
+	}
+	return false
+}
+
+// BatchDisconnectConnectorsResponse represents a response for disconnecting
+// connectors in batch
+type BatchDisconnectConnectorsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Results in the order of the requested names
+	Results []*BatchConnectorResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *BatchDisconnectConnectorsResponse) Reset() {
+	*x = BatchDisconnectConnectorsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchDisconnectConnectorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDisconnectConnectorsResponse) ProtoMessage() {}
+
+func (x *BatchDisconnectConnectorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDisconnectConnectorsResponse.ProtoReflect.Descriptor instead.
+func (*BatchDisconnectConnectorsResponse) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchDisconnectConnectorsResponse) GetResults() []*BatchConnectorResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+var File_vdp_connector_v1alpha_connector_batch_service_proto protoreflect.FileDescriptor
+
+var file_vdp_connector_v1alpha_connector_batch_service_proto_rawDesc = []byte{
+	0x0a, 0x33, 0x76, 0x64, 0x70, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2f,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x1a, 0x17, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x25, 0x76, 0x64, 0x70, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2f, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x82, 0x01, 0x0a,
+	0x14, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x3e, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x22, 0x86, 0x01, 0x0a, 0x1c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x40, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x6c, 0x6c, 0x5f, 0x6f, 0x72, 0x5f, 0x6e,
+	0x6f, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x61, 0x6c,
+	0x6c, 0x4f, 0x72, 0x4e, 0x6f, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x22, 0x66, 0x0a, 0x1d, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x76,
+	0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x22, 0x5a, 0x0a, 0x1c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x6c, 0x6c, 0x5f,
+	0x6f, 0x72, 0x5f, 0x6e, 0x6f, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x4f, 0x72, 0x4e, 0x6f, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x22, 0x66,
+	0x0a, 0x1d, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x45, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x2b, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x5b, 0x0a, 0x1d, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x24, 0x0a,
+	0x0e, 0x61, 0x6c, 0x6c, 0x5f, 0x6f, 0x72, 0x5f, 0x6e, 0x6f, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x4f, 0x72, 0x4e, 0x6f, 0x74, 0x68,
+	0x69, 0x6e, 0x67, 0x22, 0x67, 0x0a, 0x1e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x5e, 0x0a, 0x20,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x6c, 0x6c, 0x5f, 0x6f, 0x72,
+	0x5f, 0x6e, 0x6f, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c,
+	0x61, 0x6c, 0x6c, 0x4f, 0x72, 0x4e, 0x6f, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x22, 0x6a, 0x0a, 0x21,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x45, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x32, 0xc2, 0x04, 0x0a, 0x15, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x84, 0x01, 0x0a, 0x15, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x33, 0x2e, 0x76,
+	0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x34, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x84, 0x01, 0x0a, 0x15, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x73, 0x12, 0x33, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x34, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x87, 0x01, 0x0a, 0x16, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x34, 0x2e, 0x76, 0x64,
+	0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x35, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x90, 0x01, 0x0a, 0x19, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x43, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x37, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x38, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x5d, 0x5a,
+	0x5b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x6e, 0x73, 0x74,
+	0x69, 0x6c, 0x6c, 0x2d, 0x61, 0x69, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x2d, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x67, 0x65, 0x6e, 0x2f, 0x76, 0x64, 0x70, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x3b, 0x63, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescOnce sync.Once
+	file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescData = file_vdp_connector_v1alpha_connector_batch_service_proto_rawDesc
+)
+
+func file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescGZIP() []byte {
+	file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescOnce.Do(func() {
+		file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescData)
+	})
+	return file_vdp_connector_v1alpha_connector_batch_service_proto_rawDescData
+}
+
+var file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_vdp_connector_v1alpha_connector_batch_service_proto_goTypes = []interface{}{
+	(*BatchConnectorResult)(nil),              // 0: vdp.connector.v1alpha.BatchConnectorResult
+	(*BatchCreateConnectorsRequest)(nil),      // 1: vdp.connector.v1alpha.BatchCreateConnectorsRequest
+	(*BatchCreateConnectorsResponse)(nil),     // 2: vdp.connector.v1alpha.BatchCreateConnectorsResponse
+	(*BatchDeleteConnectorsRequest)(nil),      // 3: vdp.connector.v1alpha.BatchDeleteConnectorsRequest
+	(*BatchDeleteConnectorsResponse)(nil),     // 4: vdp.connector.v1alpha.BatchDeleteConnectorsResponse
+	(*BatchConnectConnectorsRequest)(nil),     // 5: vdp.connector.v1alpha.BatchConnectConnectorsRequest
+	(*BatchConnectConnectorsResponse)(nil),    // 6: vdp.connector.v1alpha.BatchConnectConnectorsResponse
+	(*BatchDisconnectConnectorsRequest)(nil),  // 7: vdp.connector.v1alpha.BatchDisconnectConnectorsRequest
+	(*BatchDisconnectConnectorsResponse)(nil), // 8: vdp.connector.v1alpha.BatchDisconnectConnectorsResponse
+	(*v1alpha.Connector)(nil),                 // 9: vdp.connector.v1alpha.Connector
+	(*status.Status)(nil),                     // 10: google.rpc.Status
+}
+var file_vdp_connector_v1alpha_connector_batch_service_proto_depIdxs = []int32{
+	9,  // 0: vdp.connector.v1alpha.BatchConnectorResult.connector:type_name -> vdp.connector.v1alpha.Connector
+	10, // 1: vdp.connector.v1alpha.BatchConnectorResult.status:type_name -> google.rpc.Status
+	9,  // 2: vdp.connector.v1alpha.BatchCreateConnectorsRequest.connectors:type_name -> vdp.connector.v1alpha.Connector
+	0,  // 3: vdp.connector.v1alpha.BatchCreateConnectorsResponse.results:type_name -> vdp.connector.v1alpha.BatchConnectorResult
+	0,  // 4: vdp.connector.v1alpha.BatchDeleteConnectorsResponse.results:type_name -> vdp.connector.v1alpha.BatchConnectorResult
+	0,  // 5: vdp.connector.v1alpha.BatchConnectConnectorsResponse.results:type_name -> vdp.connector.v1alpha.BatchConnectorResult
+	0,  // 6: vdp.connector.v1alpha.BatchDisconnectConnectorsResponse.results:type_name -> vdp.connector.v1alpha.BatchConnectorResult
+	1,  // 7: vdp.connector.v1alpha.ConnectorBatchService.BatchCreateConnectors:input_type -> vdp.connector.v1alpha.BatchCreateConnectorsRequest
+	3,  // 8: vdp.connector.v1alpha.ConnectorBatchService.BatchDeleteConnectors:input_type -> vdp.connector.v1alpha.BatchDeleteConnectorsRequest
+	5,  // 9: vdp.connector.v1alpha.ConnectorBatchService.BatchConnectConnectors:input_type -> vdp.connector.v1alpha.BatchConnectConnectorsRequest
+	7,  // 10: vdp.connector.v1alpha.ConnectorBatchService.BatchDisconnectConnectors:input_type -> vdp.connector.v1alpha.BatchDisconnectConnectorsRequest
+	2,  // 11: vdp.connector.v1alpha.ConnectorBatchService.BatchCreateConnectors:output_type -> vdp.connector.v1alpha.BatchCreateConnectorsResponse
+	4,  // 12: vdp.connector.v1alpha.ConnectorBatchService.BatchDeleteConnectors:output_type -> vdp.connector.v1alpha.BatchDeleteConnectorsResponse
+	6,  // 13: vdp.connector.v1alpha.ConnectorBatchService.BatchConnectConnectors:output_type -> vdp.connector.v1alpha.BatchConnectConnectorsResponse
+	8,  // 14: vdp.connector.v1alpha.ConnectorBatchService.BatchDisconnectConnectors:output_type -> vdp.connector.v1alpha.BatchDisconnectConnectorsResponse
+	11, // [11:15] is the sub-list for method output_type
+	7,  // [7:11] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_vdp_connector_v1alpha_connector_batch_service_proto_init() }
+func file_vdp_connector_v1alpha_connector_batch_service_proto_init() {
+	if File_vdp_connector_v1alpha_connector_batch_service_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchConnectorResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCreateConnectorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCreateConnectorsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchDeleteConnectorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchDeleteConnectorsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchConnectConnectorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchConnectConnectorsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchDisconnectConnectorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchDisconnectConnectorsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_vdp_connector_v1alpha_connector_batch_service_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vdp_connector_v1alpha_connector_batch_service_proto_goTypes,
+		DependencyIndexes: file_vdp_connector_v1alpha_connector_batch_service_proto_depIdxs,
+		MessageInfos:      file_vdp_connector_v1alpha_connector_batch_service_proto_msgTypes,
+	}.Build()
+	File_vdp_connector_v1alpha_connector_batch_service_proto = out.File
+	file_vdp_connector_v1alpha_connector_batch_service_proto_rawDesc = nil
+	file_vdp_connector_v1alpha_connector_batch_service_proto_goTypes = nil
+	file_vdp_connector_v1alpha_connector_batch_service_proto_depIdxs = nil
+}
@@ -0,0 +1,246 @@
+// The Go code is generated from the pkg/protogen directory, with the
+// instill-ai/protobufs repository as an import path, by
+//
+//   protoc -I . -I <protobufs> \
+//     --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//     vdp/connector/v1alpha/connector_batch_service.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: vdp/connector/v1alpha/connector_batch_service.proto
+
+package connectorv1alpha
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ConnectorBatchService_BatchCreateConnectors_FullMethodName     = "/vdp.connector.v1alpha.ConnectorBatchService/BatchCreateConnectors"
+	ConnectorBatchService_BatchDeleteConnectors_FullMethodName     = "/vdp.connector.v1alpha.ConnectorBatchService/BatchDeleteConnectors"
+	ConnectorBatchService_BatchConnectConnectors_FullMethodName    = "/vdp.connector.v1alpha.ConnectorBatchService/BatchConnectConnectors"
+	ConnectorBatchService_BatchDisconnectConnectors_FullMethodName = "/vdp.connector.v1alpha.ConnectorBatchService/BatchDisconnectConnectors"
+)
+
+// ConnectorBatchServiceClient is the client API for ConnectorBatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConnectorBatchServiceClient interface {
+	// BatchCreateConnectors method receives a BatchCreateConnectorsRequest
+	// message and returns a BatchCreateConnectorsResponse message.
+	BatchCreateConnectors(ctx context.Context, in *BatchCreateConnectorsRequest, opts ...grpc.CallOption) (*BatchCreateConnectorsResponse, error)
+	// BatchDeleteConnectors method receives a BatchDeleteConnectorsRequest
+	// message and returns a BatchDeleteConnectorsResponse message.
+	BatchDeleteConnectors(ctx context.Context, in *BatchDeleteConnectorsRequest, opts ...grpc.CallOption) (*BatchDeleteConnectorsResponse, error)
+	// BatchConnectConnectors method receives a BatchConnectConnectorsRequest
+	// message and returns a BatchConnectConnectorsResponse message.
+	BatchConnectConnectors(ctx context.Context, in *BatchConnectConnectorsRequest, opts ...grpc.CallOption) (*BatchConnectConnectorsResponse, error)
+	// BatchDisconnectConnectors method receives a
+	// BatchDisconnectConnectorsRequest message and returns a
+	// BatchDisconnectConnectorsResponse message.
+	BatchDisconnectConnectors(ctx context.Context, in *BatchDisconnectConnectorsRequest, opts ...grpc.CallOption) (*BatchDisconnectConnectorsResponse, error)
+}
+
+type connectorBatchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConnectorBatchServiceClient(cc grpc.ClientConnInterface) ConnectorBatchServiceClient {
+	return &connectorBatchServiceClient{cc}
+}
+
+func (c *connectorBatchServiceClient) BatchCreateConnectors(ctx context.Context, in *BatchCreateConnectorsRequest, opts ...grpc.CallOption) (*BatchCreateConnectorsResponse, error) {
+	out := new(BatchCreateConnectorsResponse)
+	err := c.cc.Invoke(ctx, ConnectorBatchService_BatchCreateConnectors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectorBatchServiceClient) BatchDeleteConnectors(ctx context.Context, in *BatchDeleteConnectorsRequest, opts ...grpc.CallOption) (*BatchDeleteConnectorsResponse, error) {
+	out := new(BatchDeleteConnectorsResponse)
+	err := c.cc.Invoke(ctx, ConnectorBatchService_BatchDeleteConnectors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectorBatchServiceClient) BatchConnectConnectors(ctx context.Context, in *BatchConnectConnectorsRequest, opts ...grpc.CallOption) (*BatchConnectConnectorsResponse, error) {
+	out := new(BatchConnectConnectorsResponse)
+	err := c.cc.Invoke(ctx, ConnectorBatchService_BatchConnectConnectors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectorBatchServiceClient) BatchDisconnectConnectors(ctx context.Context, in *BatchDisconnectConnectorsRequest, opts ...grpc.CallOption) (*BatchDisconnectConnectorsResponse, error) {
+	out := new(BatchDisconnectConnectorsResponse)
+	err := c.cc.Invoke(ctx, ConnectorBatchService_BatchDisconnectConnectors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConnectorBatchServiceServer is the server API for ConnectorBatchService service.
+// All implementations must embed UnimplementedConnectorBatchServiceServer
+// for forward compatibility
+type ConnectorBatchServiceServer interface {
+	// BatchCreateConnectors method receives a BatchCreateConnectorsRequest
+	// message and returns a BatchCreateConnectorsResponse message.
+	BatchCreateConnectors(context.Context, *BatchCreateConnectorsRequest) (*BatchCreateConnectorsResponse, error)
+	// BatchDeleteConnectors method receives a BatchDeleteConnectorsRequest
+	// message and returns a BatchDeleteConnectorsResponse message.
+	BatchDeleteConnectors(context.Context, *BatchDeleteConnectorsRequest) (*BatchDeleteConnectorsResponse, error)
+	// BatchConnectConnectors method receives a BatchConnectConnectorsRequest
+	// message and returns a BatchConnectConnectorsResponse message.
+	BatchConnectConnectors(context.Context, *BatchConnectConnectorsRequest) (*BatchConnectConnectorsResponse, error)
+	// BatchDisconnectConnectors method receives a
+	// BatchDisconnectConnectorsRequest message and returns a
+	// BatchDisconnectConnectorsResponse message.
+	BatchDisconnectConnectors(context.Context, *BatchDisconnectConnectorsRequest) (*BatchDisconnectConnectorsResponse, error)
+	mustEmbedUnimplementedConnectorBatchServiceServer()
+}
+
+// UnimplementedConnectorBatchServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedConnectorBatchServiceServer struct {
+}
+
+func (UnimplementedConnectorBatchServiceServer) BatchCreateConnectors(context.Context, *BatchCreateConnectorsRequest) (*BatchCreateConnectorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateConnectors not implemented")
+}
+func (UnimplementedConnectorBatchServiceServer) BatchDeleteConnectors(context.Context, *BatchDeleteConnectorsRequest) (*BatchDeleteConnectorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteConnectors not implemented")
+}
+func (UnimplementedConnectorBatchServiceServer) BatchConnectConnectors(context.Context, *BatchConnectConnectorsRequest) (*BatchConnectConnectorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchConnectConnectors not implemented")
+}
+func (UnimplementedConnectorBatchServiceServer) BatchDisconnectConnectors(context.Context, *BatchDisconnectConnectorsRequest) (*BatchDisconnectConnectorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDisconnectConnectors not implemented")
+}
+func (UnimplementedConnectorBatchServiceServer) mustEmbedUnimplementedConnectorBatchServiceServer() {}
+
+// UnsafeConnectorBatchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConnectorBatchServiceServer will
+// result in compilation errors.
+type UnsafeConnectorBatchServiceServer interface {
+	mustEmbedUnimplementedConnectorBatchServiceServer()
+}
+
+func RegisterConnectorBatchServiceServer(s grpc.ServiceRegistrar, srv ConnectorBatchServiceServer) {
+	s.RegisterService(&ConnectorBatchService_ServiceDesc, srv)
+}
+
+func _ConnectorBatchService_BatchCreateConnectors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateConnectorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorBatchServiceServer).BatchCreateConnectors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConnectorBatchService_BatchCreateConnectors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorBatchServiceServer).BatchCreateConnectors(ctx, req.(*BatchCreateConnectorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConnectorBatchService_BatchDeleteConnectors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteConnectorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorBatchServiceServer).BatchDeleteConnectors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConnectorBatchService_BatchDeleteConnectors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorBatchServiceServer).BatchDeleteConnectors(ctx, req.(*BatchDeleteConnectorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConnectorBatchService_BatchConnectConnectors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchConnectConnectorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorBatchServiceServer).BatchConnectConnectors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConnectorBatchService_BatchConnectConnectors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorBatchServiceServer).BatchConnectConnectors(ctx, req.(*BatchConnectConnectorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConnectorBatchService_BatchDisconnectConnectors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDisconnectConnectorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorBatchServiceServer).BatchDisconnectConnectors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConnectorBatchService_BatchDisconnectConnectors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorBatchServiceServer).BatchDisconnectConnectors(ctx, req.(*BatchDisconnectConnectorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConnectorBatchService_ServiceDesc is the grpc.ServiceDesc for ConnectorBatchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConnectorBatchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vdp.connector.v1alpha.ConnectorBatchService",
+	HandlerType: (*ConnectorBatchServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BatchCreateConnectors",
+			Handler:    _ConnectorBatchService_BatchCreateConnectors_Handler,
+		},
+		{
+			MethodName: "BatchDeleteConnectors",
+			Handler:    _ConnectorBatchService_BatchDeleteConnectors_Handler,
+		},
+		{
+			MethodName: "BatchConnectConnectors",
+			Handler:    _ConnectorBatchService_BatchConnectConnectors_Handler,
+		},
+		{
+			MethodName: "BatchDisconnectConnectors",
+			Handler:    _ConnectorBatchService_BatchDisconnectConnectors_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vdp/connector/v1alpha/connector_batch_service.proto",
+}
@@ -0,0 +1,289 @@
+// The Go code is generated from the pkg/protogen directory, with the
+// instill-ai/protobufs repository as an import path, by
+//
+//   protoc -I . -I <protobufs> \
+//     --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//     vdp/connector/v1alpha/connector_clone_service.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: vdp/connector/v1alpha/connector_clone_service.proto
+
+package connectorv1alpha
+
+import (
+	v1alpha "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CloneConnectorRequest represents a request to clone a connector
+type CloneConnectorRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the connector to clone, e.g., connectors/{id}
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// NewConnectorId is the ID of the cloned connector
+	NewConnectorId string `protobuf:"bytes,2,opt,name=new_connector_id,json=newConnectorId,proto3" json:"new_connector_id,omitempty"`
+	// TargetOwner is the owner of the cloned connector, e.g., users/{id}.
+	// The cloned connector belongs to the caller if not set.
+	TargetOwner *string `protobuf:"bytes,3,opt,name=target_owner,json=targetOwner,proto3,oneof" json:"target_owner,omitempty"`
+	// Visibility of the cloned connector, the visibility of the cloned one if
+	// not set
+	Visibility *v1alpha.Connector_Visibility `protobuf:"varint,4,opt,name=visibility,proto3,enum=vdp.connector.v1alpha.Connector_Visibility,oneof" json:"visibility,omitempty"`
+}
+
+func (x *CloneConnectorRequest) Reset() {
+	*x = CloneConnectorRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_clone_service_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloneConnectorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneConnectorRequest) ProtoMessage() {}
+
+func (x *CloneConnectorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_clone_service_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneConnectorRequest.ProtoReflect.Descriptor instead.
+func (*CloneConnectorRequest) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_clone_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CloneConnectorRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CloneConnectorRequest) GetNewConnectorId() string {
+	if x != nil {
+		return x.NewConnectorId
+	}
+	return ""
+}
+
+func (x *CloneConnectorRequest) GetTargetOwner() string {
+	if x != nil && x.TargetOwner != nil {
+		return *x.TargetOwner
+	}
+	return ""
+}
+
+func (x *CloneConnectorRequest) GetVisibility() v1alpha.Connector_Visibility {
+	if x != nil && x.Visibility != nil {
+		return *x.Visibility
+	}
+	return v1alpha.Connector_Visibility(0)
+}
+
+// CloneConnectorResponse represents a response for cloning a connector
+type CloneConnectorResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The cloned connector
+	Connector *v1alpha.Connector `protobuf:"bytes,1,opt,name=connector,proto3" json:"connector,omitempty"`
+}
+
+func (x *CloneConnectorResponse) Reset() {
+	*x = CloneConnectorResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vdp_connector_v1alpha_connector_clone_service_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloneConnectorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneConnectorResponse) ProtoMessage() {}
+
+func (x *CloneConnectorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vdp_connector_v1alpha_connector_clone_service_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneConnectorResponse.ProtoReflect.Descriptor instead.
+func (*CloneConnectorResponse) Descriptor() ([]byte, []int) {
+	return file_vdp_connector_v1alpha_connector_clone_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CloneConnectorResponse) GetConnector() *v1alpha.Connector {
+	if x != nil {
+		return x.Connector
+	}
+	return nil
+}
+
+var File_vdp_connector_v1alpha_connector_clone_service_proto protoreflect.FileDescriptor
+
+var file_vdp_connector_v1alpha_connector_clone_service_proto_rawDesc = []byte{
+	0x0a, 0x33, 0x76, 0x64, 0x70, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2f,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x5f, 0x63, 0x6c, 0x6f, 0x6e, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x1a, 0x25, 0x76, 0x64,
+	0x70, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2f, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0xef, 0x01, 0x0a, 0x15, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x28, 0x0a, 0x10, 0x6e, 0x65, 0x77, 0x5f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x6e, 0x65, 0x77,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0c, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x88, 0x01, 0x01, 0x12, 0x50, 0x0a, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2b, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x56, 0x69, 0x73, 0x69, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x48, 0x01, 0x52, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x76, 0x69, 0x73, 0x69, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x79, 0x22, 0x58, 0x0a, 0x16, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x43, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3e, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x76, 0x64, 0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x32,
+	0x88, 0x01, 0x0a, 0x15, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x43, 0x6c, 0x6f,
+	0x6e, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x6f, 0x0a, 0x0e, 0x43, 0x6c, 0x6f,
+	0x6e, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x2c, 0x2e, 0x76, 0x64,
+	0x70, 0x2e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x2e, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x76, 0x64, 0x70, 0x2e,
+	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x2e, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x5d, 0x5a, 0x5b, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x69, 0x6e, 0x73, 0x74, 0x69, 0x6c, 0x6c,
+	0x2d, 0x61, 0x69, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2d, 0x62, 0x61,
+	0x63, 0x6b, 0x65, 0x6e, 0x64, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x67,
+	0x65, 0x6e, 0x2f, 0x76, 0x64, 0x70, 0x2f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x3b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_vdp_connector_v1alpha_connector_clone_service_proto_rawDescOnce sync.Once
+	file_vdp_connector_v1alpha_connector_clone_service_proto_rawDescData = file_vdp_connector_v1alpha_connector_clone_service_proto_rawDesc
+)
+
+func file_vdp_connector_v1alpha_connector_clone_service_proto_rawDescGZIP() []byte {
+	file_vdp_connector_v1alpha_connector_clone_service_proto_rawDescOnce.Do(func() {
+		file_vdp_connector_v1alpha_connector_clone_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_vdp_connector_v1alpha_connector_clone_service_proto_rawDescData)
+	})
+	return file_vdp_connector_v1alpha_connector_clone_service_proto_rawDescData
+}
+
+var file_vdp_connector_v1alpha_connector_clone_service_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_vdp_connector_v1alpha_connector_clone_service_proto_goTypes = []interface{}{
+	(*CloneConnectorRequest)(nil),     // 0: vdp.connector.v1alpha.CloneConnectorRequest
+	(*CloneConnectorResponse)(nil),    // 1: vdp.connector.v1alpha.CloneConnectorResponse
+	(v1alpha.Connector_Visibility)(0), // 2: vdp.connector.v1alpha.Connector.Visibility
+	(*v1alpha.Connector)(nil),         // 3: vdp.connector.v1alpha.Connector
+}
+var file_vdp_connector_v1alpha_connector_clone_service_proto_depIdxs = []int32{
+	2, // 0: vdp.connector.v1alpha.CloneConnectorRequest.visibility:type_name -> vdp.connector.v1alpha.Connector.Visibility
+	3, // 1: vdp.connector.v1alpha.CloneConnectorResponse.connector:type_name -> vdp.connector.v1alpha.Connector
+	0, // 2: vdp.connector.v1alpha.ConnectorCloneService.CloneConnector:input_type -> vdp.connector.v1alpha.CloneConnectorRequest
+	1, // 3: vdp.connector.v1alpha.ConnectorCloneService.CloneConnector:output_type -> vdp.connector.v1alpha.CloneConnectorResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_vdp_connector_v1alpha_connector_clone_service_proto_init() }
+func file_vdp_connector_v1alpha_connector_clone_service_proto_init() {
+	if File_vdp_connector_v1alpha_connector_clone_service_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_vdp_connector_v1alpha_connector_clone_service_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloneConnectorRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vdp_connector_v1alpha_connector_clone_service_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloneConnectorResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_vdp_connector_v1alpha_connector_clone_service_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_vdp_connector_v1alpha_connector_clone_service_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vdp_connector_v1alpha_connector_clone_service_proto_goTypes,
+		DependencyIndexes: file_vdp_connector_v1alpha_connector_clone_service_proto_depIdxs,
+		MessageInfos:      file_vdp_connector_v1alpha_connector_clone_service_proto_msgTypes,
+	}.Build()
+	File_vdp_connector_v1alpha_connector_clone_service_proto = out.File
+	file_vdp_connector_v1alpha_connector_clone_service_proto_rawDesc = nil
+	file_vdp_connector_v1alpha_connector_clone_service_proto_goTypes = nil
+	file_vdp_connector_v1alpha_connector_clone_service_proto_depIdxs = nil
+}
@@ -0,0 +1,121 @@
+// The Go code is generated from the pkg/protogen directory, with the
+// instill-ai/protobufs repository as an import path, by
+//
+//   protoc -I . -I <protobufs> \
+//     --go_out=. --go_opt=paths=source_relative \
+//     --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//     vdp/connector/v1alpha/connector_clone_service.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: vdp/connector/v1alpha/connector_clone_service.proto
+
+package connectorv1alpha
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ConnectorCloneService_CloneConnector_FullMethodName = "/vdp.connector.v1alpha.ConnectorCloneService/CloneConnector"
+)
+
+// ConnectorCloneServiceClient is the client API for ConnectorCloneService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConnectorCloneServiceClient interface {
+	// CloneConnector method receives a CloneConnectorRequest message and returns
+	// a CloneConnectorResponse message.
+	CloneConnector(ctx context.Context, in *CloneConnectorRequest, opts ...grpc.CallOption) (*CloneConnectorResponse, error)
+}
+
+type connectorCloneServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConnectorCloneServiceClient(cc grpc.ClientConnInterface) ConnectorCloneServiceClient {
+	return &connectorCloneServiceClient{cc}
+}
+
+func (c *connectorCloneServiceClient) CloneConnector(ctx context.Context, in *CloneConnectorRequest, opts ...grpc.CallOption) (*CloneConnectorResponse, error) {
+	out := new(CloneConnectorResponse)
+	err := c.cc.Invoke(ctx, ConnectorCloneService_CloneConnector_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConnectorCloneServiceServer is the server API for ConnectorCloneService service.
+// All implementations must embed UnimplementedConnectorCloneServiceServer
+// for forward compatibility
+type ConnectorCloneServiceServer interface {
+	// CloneConnector method receives a CloneConnectorRequest message and returns
+	// a CloneConnectorResponse message.
+	CloneConnector(context.Context, *CloneConnectorRequest) (*CloneConnectorResponse, error)
+	mustEmbedUnimplementedConnectorCloneServiceServer()
+}
+
+// UnimplementedConnectorCloneServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedConnectorCloneServiceServer struct {
+}
+
+func (UnimplementedConnectorCloneServiceServer) CloneConnector(context.Context, *CloneConnectorRequest) (*CloneConnectorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneConnector not implemented")
+}
+func (UnimplementedConnectorCloneServiceServer) mustEmbedUnimplementedConnectorCloneServiceServer() {}
+
+// UnsafeConnectorCloneServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConnectorCloneServiceServer will
+// result in compilation errors.
+type UnsafeConnectorCloneServiceServer interface {
+	mustEmbedUnimplementedConnectorCloneServiceServer()
+}
+
+func RegisterConnectorCloneServiceServer(s grpc.ServiceRegistrar, srv ConnectorCloneServiceServer) {
+	s.RegisterService(&ConnectorCloneService_ServiceDesc, srv)
+}
+
+func _ConnectorCloneService_CloneConnector_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneConnectorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectorCloneServiceServer).CloneConnector(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConnectorCloneService_CloneConnector_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectorCloneServiceServer).CloneConnector(ctx, req.(*CloneConnectorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConnectorCloneService_ServiceDesc is the grpc.ServiceDesc for ConnectorCloneService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConnectorCloneService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vdp.connector.v1alpha.ConnectorCloneService",
+	HandlerType: (*ConnectorCloneServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CloneConnector",
+			Handler:    _ConnectorCloneService_CloneConnector_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vdp/connector/v1alpha/connector_clone_service.proto",
+}
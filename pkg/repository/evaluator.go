@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	expr "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+// Evaluator evaluates a filter on a connector, or on any resource resolved by
+// Fields, in memory. It supports the subset of the transpiler used on
+// connector columns: comparisons, logical operators, enum identifiers and
+// timestamps. NULL columns follow the SQL three-valued logic, represented by
+// nil. The has operator `:` additionally matches a substring of a string or an
+// element of a list of strings, ignoring case.
+type Evaluator struct {
+	filter filtering.Filter
+}
+
+// Fields resolves the identifiers of a filter to the values of a resource,
+// nil for NULL. The values are strings, float64, time.Time, bool or []string.
+type Fields func(name string) (interface{}, error)
+
+// NewEvaluator returns an evaluator of the filter
+func NewEvaluator(filter filtering.Filter) Evaluator {
+	return Evaluator{
+		filter: filter,
+	}
+}
+
+// Evaluate reports whether the connector matches the filter
+func (e *Evaluator) Evaluate(connector *datamodel.Connector) (bool, error) {
+	return e.EvaluateFields(connectorFields(connector))
+}
+
+// EvaluateFields reports whether the resource whose identifiers are resolved
+// by fields matches the filter
+func (e *Evaluator) EvaluateFields(fields Fields) (bool, error) {
+	if e.filter.CheckedExpr == nil {
+		return true, nil
+	}
+	v, err := e.evaluateExpr(e.filter.CheckedExpr.Expr, fields)
+	if err != nil {
+		return false, err
+	}
+	if v == nil {
+		return false, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("filter is not a boolean expression")
+	}
+	return b, nil
+}
+
+func (e *Evaluator) evaluateExpr(ex *expr.Expr, fields Fields) (interface{}, error) {
+	switch ex.ExprKind.(type) {
+	case *expr.Expr_CallExpr:
+		return e.evaluateCallExpr(ex, fields)
+	case *expr.Expr_IdentExpr:
+		return e.evaluateIdentExpr(ex, fields)
+	case *expr.Expr_ConstExpr:
+		return e.evaluateConstExpr(ex)
+	default:
+		return nil, fmt.Errorf("unsupported expr: %v", ex)
+	}
+}
+
+func (e *Evaluator) evaluateConstExpr(ex *expr.Expr) (interface{}, error) {
+	switch kind := ex.GetConstExpr().ConstantKind.(type) {
+	case *expr.Constant_BoolValue:
+		return kind.BoolValue, nil
+	case *expr.Constant_DoubleValue:
+		return kind.DoubleValue, nil
+	case *expr.Constant_Int64Value:
+		return float64(kind.Int64Value), nil
+	case *expr.Constant_StringValue:
+		return kind.StringValue, nil
+	case *expr.Constant_Uint64Value:
+		return float64(kind.Uint64Value), nil
+	default:
+		return nil, fmt.Errorf("unsupported const expr: %v", kind)
+	}
+}
+
+func (e *Evaluator) evaluateIdentExpr(ex *expr.Expr, fields Fields) (interface{}, error) {
+
+	identExpr := ex.GetIdentExpr()
+	identType, ok := e.filter.CheckedExpr.TypeMap[ex.Id]
+	if !ok {
+		return nil, fmt.Errorf("unknown type of ident expr %d", ex.Id)
+	}
+	if messageType := identType.GetMessageType(); messageType != "" {
+		if enumType, err := protoregistry.GlobalTypes.FindEnumByName(protoreflect.FullName(messageType)); err == nil {
+			if enumValue := enumType.Descriptor().Values().ByName(protoreflect.Name(identExpr.Name)); enumValue != nil {
+				return string(enumValue.Name()), nil
+			}
+		}
+	}
+
+	return fields(identExpr.Name)
+}
+
+// connectorFields resolves the identifiers of the connector columns
+func connectorFields(connector *datamodel.Connector) Fields {
+	return func(name string) (interface{}, error) {
+		switch name {
+		case "uid":
+			return connector.UID.String(), nil
+		case "id":
+			return connector.ID, nil
+		case "owner":
+			return connector.Owner, nil
+		case "connector_definition_uid":
+			return connector.ConnectorDefinitionUID.String(), nil
+		case "description":
+			if !connector.Description.Valid {
+				return nil, nil
+			}
+			return connector.Description.String, nil
+		case "tombstone":
+			return connector.Tombstone, nil
+		case "connector_type":
+			return connectorPB.ConnectorType(connector.ConnectorType).String(), nil
+		case "state":
+			return connectorPB.Connector_State(connector.State).String(), nil
+		case "visibility":
+			return connectorPB.Connector_Visibility(connector.Visibility).String(), nil
+		case "task":
+			return connector.Task, nil
+		case "create_time":
+			return connector.CreateTime, nil
+		case "update_time":
+			return connector.UpdateTime, nil
+		default:
+			return nil, fmt.Errorf("unknown column %s", name)
+		}
+	}
+}
+
+func (e *Evaluator) evaluateCallExpr(ex *expr.Expr, fields Fields) (interface{}, error) {
+
+	callExpr := ex.GetCallExpr()
+
+	switch callExpr.Function {
+	case filtering.FunctionTimestamp:
+		return e.evaluateTimestampCallExpr(ex)
+	case filtering.FunctionNot:
+		if len(callExpr.Args) != 1 {
+			return nil, fmt.Errorf("unexpected number of arguments to `%s` expression: %d", callExpr.Function, len(callExpr.Args))
+		}
+		v, err := e.evaluateBool(callExpr.Args[0], fields)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		return !*v, nil
+	case filtering.FunctionAnd, filtering.FunctionOr:
+		if len(callExpr.Args) != 2 {
+			return nil, fmt.Errorf("unexpected number of arguments to `%s`: %d", callExpr.Function, len(callExpr.Args))
+		}
+		lhs, err := e.evaluateBool(callExpr.Args[0], fields)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := e.evaluateBool(callExpr.Args[1], fields)
+		if err != nil {
+			return nil, err
+		}
+		// The absorbing element wins over NULL
+		absorbing := callExpr.Function == filtering.FunctionOr
+		if (lhs != nil && *lhs == absorbing) || (rhs != nil && *rhs == absorbing) {
+			return absorbing, nil
+		}
+		if lhs == nil || rhs == nil {
+			return nil, nil
+		}
+		return !absorbing, nil
+	case filtering.FunctionEquals, filtering.FunctionNotEquals,
+		filtering.FunctionLessThan, filtering.FunctionLessEquals,
+		filtering.FunctionGreaterThan, filtering.FunctionGreaterEquals:
+		return e.evaluateComparisonCallExpr(ex, fields)
+	case filtering.FunctionHas:
+		return e.evaluateHasCallExpr(ex, fields)
+	default:
+		return nil, fmt.Errorf("unsupported function call: %s", callExpr.Function)
+	}
+}
+
+func (e *Evaluator) evaluateBool(ex *expr.Expr, fields Fields) (*bool, error) {
+	v, err := e.evaluateExpr(ex, fields)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("expected a boolean expression: %v", ex)
+	}
+	return &b, nil
+}
+
+func (e *Evaluator) evaluateComparisonCallExpr(ex *expr.Expr, fields Fields) (interface{}, error) {
+
+	callExpr := ex.GetCallExpr()
+	if len(callExpr.Args) != 2 {
+		return nil, fmt.Errorf("unexpected number of arguments to `%s`: %d", callExpr.Function, len(callExpr.Args))
+	}
+	lhs, err := e.evaluateExpr(callExpr.Args[0], fields)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := e.evaluateExpr(callExpr.Args[1], fields)
+	if err != nil {
+		return nil, err
+	}
+	if lhs == nil || rhs == nil {
+		return nil, nil
+	}
+
+	var cmp int
+	switch l := lhs.(type) {
+	case string:
+		r, ok := rhs.(string)
+		if !ok {
+			return nil, fmt.Errorf("mismatched types in `%s`: %T and %T", callExpr.Function, lhs, rhs)
+		}
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	case float64:
+		r, ok := rhs.(float64)
+		if !ok {
+			return nil, fmt.Errorf("mismatched types in `%s`: %T and %T", callExpr.Function, lhs, rhs)
+		}
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	case time.Time:
+		r, ok := rhs.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("mismatched types in `%s`: %T and %T", callExpr.Function, lhs, rhs)
+		}
+		switch {
+		case l.Before(r):
+			cmp = -1
+		case l.After(r):
+			cmp = 1
+		}
+	case bool:
+		r, ok := rhs.(bool)
+		if !ok {
+			return nil, fmt.Errorf("mismatched types in `%s`: %T and %T", callExpr.Function, lhs, rhs)
+		}
+		if callExpr.Function != filtering.FunctionEquals && callExpr.Function != filtering.FunctionNotEquals {
+			return nil, fmt.Errorf("unsupported comparison `%s` of booleans", callExpr.Function)
+		}
+		if l != r {
+			cmp = 1
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type in `%s`: %T", callExpr.Function, lhs)
+	}
+
+	switch callExpr.Function {
+	case filtering.FunctionEquals:
+		return cmp == 0, nil
+	case filtering.FunctionNotEquals:
+		return cmp != 0, nil
+	case filtering.FunctionLessThan:
+		return cmp < 0, nil
+	case filtering.FunctionLessEquals:
+		return cmp <= 0, nil
+	case filtering.FunctionGreaterThan:
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func (e *Evaluator) evaluateHasCallExpr(ex *expr.Expr, fields Fields) (interface{}, error) {
+
+	callExpr := ex.GetCallExpr()
+	if len(callExpr.Args) != 2 {
+		return nil, fmt.Errorf("unexpected number of arguments to `%s`: %d", callExpr.Function, len(callExpr.Args))
+	}
+	lhs, err := e.evaluateExpr(callExpr.Args[0], fields)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := e.evaluateExpr(callExpr.Args[1], fields)
+	if err != nil {
+		return nil, err
+	}
+	if lhs == nil || rhs == nil {
+		return nil, nil
+	}
+	r, ok := rhs.(string)
+	if !ok {
+		return nil, fmt.Errorf("unsupported type in `%s`: %T", callExpr.Function, rhs)
+	}
+
+	switch l := lhs.(type) {
+	case string:
+		return strings.Contains(strings.ToLower(l), strings.ToLower(r)), nil
+	case []string:
+		for _, elem := range l {
+			if strings.EqualFold(elem, r) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return nil, fmt.Errorf("unsupported type in `%s`: %T", callExpr.Function, lhs)
+	}
+}
+
+func (e *Evaluator) evaluateTimestampCallExpr(ex *expr.Expr) (interface{}, error) {
+
+	callExpr := ex.GetCallExpr()
+	if len(callExpr.Args) != 1 {
+		return nil, fmt.Errorf(
+			"unexpected number of arguments to `%s`: %d", callExpr.Function, len(callExpr.Args),
+		)
+	}
+	constArg, ok := callExpr.Args[0].ExprKind.(*expr.Expr_ConstExpr)
+	if !ok {
+		return nil, fmt.Errorf("expected constant string arg to %s", callExpr.Function)
+	}
+	stringArg, ok := constArg.ConstExpr.ConstantKind.(*expr.Constant_StringValue)
+	if !ok {
+		return nil, fmt.Errorf("expected constant string arg to %s", callExpr.Function)
+	}
+	timeArg, err := time.Parse(time.RFC3339, stringArg.StringValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid string arg to %s: %w", callExpr.Function, err)
+	}
+	return timeArg, nil
+}
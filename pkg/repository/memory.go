@@ -0,0 +1,816 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/gogo/status"
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"gorm.io/gorm"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/paginate"
+	"github.com/instill-ai/x/sterr"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// memoryRepository keeps the connectors in memory. It behaves as the
+// database repository for pagination, soft deletes, uniqueness errors and
+// the filters supported by the Evaluator.
+type memoryRepository struct {
+	mu    *sync.Mutex
+	store *memoryStore
+	// inTx marks the repository of a transaction, whose mutex is already held
+	inTx bool
+}
+
+type memoryStore struct {
+	// connectors includes the soft deleted connectors
+	connectors []*datamodel.Connector
+	// policies are the connector definition policies by definition uid
+	policies map[uuid.UUID]*datamodel.ConnectorDefinitionPolicy
+	// builtins are the builtin connector definitions by uid
+	builtins map[uuid.UUID]*datamodel.BuiltinConnectorDefinition
+	// meterings are the execution counters by owner, connector and window
+	meterings map[meteringKey]*datamodel.ExecutionMetering
+	// auditEvents are the audit events in creation order
+	auditEvents []*datamodel.AuditEvent
+}
+
+type meteringKey struct {
+	owner        string
+	connectorUID uuid.UUID
+	windowStart  int64
+}
+
+// NewMemoryRepository initiates an in-memory repository instance
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		mu: &sync.Mutex{},
+		store: &memoryStore{
+			policies:  map[uuid.UUID]*datamodel.ConnectorDefinitionPolicy{},
+			builtins:  map[uuid.UUID]*datamodel.BuiltinConnectorDefinition{},
+			meterings: map[meteringKey]*datamodel.ExecutionMetering{},
+		},
+	}
+}
+
+func (r *memoryRepository) lock() func() {
+	if r.inTx {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+func (r *memoryRepository) Transaction(ctx context.Context, fn func(r Repository) error) error {
+
+	unlock := r.lock()
+	defer unlock()
+
+	tx := &memoryRepository{
+		mu: r.mu,
+		store: &memoryStore{
+			connectors: make([]*datamodel.Connector, 0, len(r.store.connectors)),
+			policies:   make(map[uuid.UUID]*datamodel.ConnectorDefinitionPolicy, len(r.store.policies)),
+			builtins:   make(map[uuid.UUID]*datamodel.BuiltinConnectorDefinition, len(r.store.builtins)),
+			meterings:  make(map[meteringKey]*datamodel.ExecutionMetering, len(r.store.meterings)),
+			// The events are never updated, the transaction only appends to its copy of the slice
+			auditEvents: append([]*datamodel.AuditEvent{}, r.store.auditEvents...),
+		},
+		inTx: true,
+	}
+	for _, connector := range r.store.connectors {
+		tx.store.connectors = append(tx.store.connectors, copyConnector(connector, false))
+	}
+	for uid, policy := range r.store.policies {
+		p := *policy
+		tx.store.policies[uid] = &p
+	}
+	for uid, def := range r.store.builtins {
+		tx.store.builtins[uid] = copyBuiltinConnectorDefinition(def)
+	}
+	for key, metering := range r.store.meterings {
+		m := *metering
+		tx.store.meterings[key] = &m
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.store.connectors = tx.store.connectors
+	r.store.policies = tx.store.policies
+	r.store.builtins = tx.store.builtins
+	r.store.meterings = tx.store.meterings
+	r.store.auditEvents = tx.store.auditEvents
+	return nil
+}
+
+func (r *memoryRepository) CreateConnector(ctx context.Context, connector *datamodel.Connector) error {
+
+	unlock := r.lock()
+	defer unlock()
+
+	if connector.Configuration == nil {
+		return memoryError(ctx, codes.Internal, "create connector", `null value in column "configuration"`, "", connector.Owner)
+	}
+	if r.store.first(func(c *datamodel.Connector) bool { return c.Owner == connector.Owner && c.ID == connector.ID }) != nil {
+		return memoryError(ctx, codes.AlreadyExists, "create connector", "UNIQUE constraint failed: connector.owner, connector.id", fmt.Sprintf("id %s", connector.ID), connector.Owner)
+	}
+
+	uid, err := uuid.NewV4()
+	if err != nil {
+		return memoryError(ctx, codes.Internal, "create connector", err.Error(), "", connector.Owner)
+	}
+	now := time.Now().UTC()
+	connector.UID = uid
+	if connector.CreateTime.IsZero() {
+		connector.CreateTime = now
+	}
+	if connector.UpdateTime.IsZero() {
+		connector.UpdateTime = now
+	}
+	connector.DeleteTime = gorm.DeletedAt{}
+
+	r.store.connectors = append(r.store.connectors, copyConnector(connector, false))
+	return nil
+}
+
+func (r *memoryRepository) ListConnectors(ctx context.Context, ownerPermalink string, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter) ([]*datamodel.Connector, int64, string, error) {
+	return r.listConnectors(ctx, ownerPermalink, func(c *datamodel.Connector) bool {
+		return isVisible(c, ownerPermalink)
+	}, pageSize, pageToken, isBasicView, filter)
+}
+
+func (r *memoryRepository) ListConnectorsAdmin(ctx context.Context, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter) ([]*datamodel.Connector, int64, string, error) {
+	return r.listConnectors(ctx, "admin", func(c *datamodel.Connector) bool {
+		return true
+	}, pageSize, pageToken, isBasicView, filter)
+}
+
+func (r *memoryRepository) listConnectors(ctx context.Context, ownerPermalink string, visible func(c *datamodel.Connector) bool, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter) (connectors []*datamodel.Connector, totalSize int64, nextPageToken string, err error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	evaluator := NewEvaluator(filter)
+
+	var matched []*datamodel.Connector
+	for _, c := range r.store.live() {
+		if !visible(c) {
+			continue
+		}
+		ok, err := evaluator.Evaluate(c)
+		if err != nil {
+			return nil, 0, "", status.Errorf(codes.Internal, err.Error())
+		}
+		if ok {
+			matched = append(matched, c)
+		}
+	}
+	totalSize = int64(len(matched))
+
+	// Order by create_time DESC, uid DESC
+	sort.Slice(matched, func(i, j int) bool {
+		return isBefore(matched[j], matched[i].CreateTime, matched[i].UID.String())
+	})
+
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	page := matched
+	if pageToken != "" {
+		createdAt, uid, err := paginate.DecodeToken(pageToken)
+		if err != nil {
+			st, err := sterr.CreateErrorBadRequest(
+				fmt.Sprintf("[db] list connector error: %s", err.Error()),
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "page_token",
+						Description: fmt.Sprintf("Invalid page token: %s", err.Error()),
+					},
+				},
+			)
+			if err != nil {
+				logger, _ := logger.GetZapLogger(ctx)
+				logger.Error(err.Error())
+			}
+			return nil, 0, "", st.Err()
+		}
+		page = nil
+		for _, c := range matched {
+			if isBefore(c, createdAt, uid) {
+				page = append(page, c)
+			}
+		}
+	}
+	if int64(len(page)) > pageSize {
+		page = page[:pageSize]
+	}
+
+	for _, c := range page {
+		connectors = append(connectors, copyConnector(c, isBasicView))
+	}
+
+	if len(connectors) > 0 {
+		last := connectors[len(connectors)-1]
+		if last.UID == matched[len(matched)-1].UID {
+			nextPageToken = ""
+		} else {
+			nextPageToken = paginate.EncodeToken(last.CreateTime, last.UID.String())
+		}
+	}
+
+	return connectors, totalSize, nextPageToken, nil
+}
+
+func (r *memoryRepository) GetConnectorByID(ctx context.Context, id string, ownerPermalink string, isBasicView bool) (*datamodel.Connector, error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	// The connector of the owner comes before a public connector of another
+	// owner with the same id
+	c := r.store.first(func(c *datamodel.Connector) bool { return c.ID == id && c.Owner == ownerPermalink })
+	if c == nil {
+		c = r.store.first(func(c *datamodel.Connector) bool { return c.ID == id && isVisible(c, ownerPermalink) })
+	}
+	if c == nil {
+		return nil, memoryError(ctx, codes.NotFound, "get connector by id", gorm.ErrRecordNotFound.Error(), "", ownerPermalink)
+	}
+	return copyConnector(c, isBasicView), nil
+}
+
+func (r *memoryRepository) GetConnectorByUID(ctx context.Context, uid uuid.UUID, ownerPermalink string, isBasicView bool) (*datamodel.Connector, error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	c := r.store.first(func(c *datamodel.Connector) bool { return c.UID == uid && isVisible(c, ownerPermalink) })
+	if c == nil {
+		return nil, memoryError(ctx, codes.NotFound, "get connector by uid", gorm.ErrRecordNotFound.Error(), uid.String(), ownerPermalink)
+	}
+	return copyConnector(c, isBasicView), nil
+}
+
+func (r *memoryRepository) GetConnectorByUIDAdmin(ctx context.Context, uid uuid.UUID, isBasicView bool) (*datamodel.Connector, error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	c := r.store.first(func(c *datamodel.Connector) bool { return c.UID == uid })
+	if c == nil {
+		return nil, memoryError(ctx, codes.NotFound, "get connector by uid", gorm.ErrRecordNotFound.Error(), uid.String(), "admin")
+	}
+	return copyConnector(c, isBasicView), nil
+}
+
+// UpdateConnector updates the non-zero fields of connector, as GORM does
+// with a struct
+func (r *memoryRepository) UpdateConnector(ctx context.Context, id string, ownerPermalink string, connector *datamodel.Connector) error {
+
+	unlock := r.lock()
+	defer unlock()
+
+	c := r.store.first(func(c *datamodel.Connector) bool { return c.ID == id && c.Owner == ownerPermalink })
+	if c == nil {
+		return memoryError(ctx, codes.NotFound, "update connector", "Not found", "", ownerPermalink)
+	}
+
+	owner, newID := c.Owner, c.ID
+	if connector.Owner != "" {
+		owner = connector.Owner
+	}
+	if connector.ID != "" {
+		newID = connector.ID
+	}
+	if r.store.first(func(o *datamodel.Connector) bool { return o != c && o.Owner == owner && o.ID == newID }) != nil {
+		return memoryError(ctx, codes.AlreadyExists, "update connector", "UNIQUE constraint failed: connector.owner, connector.id", "", ownerPermalink)
+	}
+
+	c.ID = newID
+	c.Owner = owner
+	if connector.ConnectorDefinitionUID != uuid.Nil {
+		c.ConnectorDefinitionUID = connector.ConnectorDefinitionUID
+	}
+	if connector.Description.Valid || connector.Description.String != "" {
+		c.Description = connector.Description
+	}
+	if connector.Tombstone {
+		c.Tombstone = true
+	}
+	if connector.Configuration != nil {
+		c.Configuration = append([]byte{}, connector.Configuration...)
+	}
+	if connector.ConnectorType != 0 {
+		c.ConnectorType = connector.ConnectorType
+	}
+	if connector.State != 0 {
+		c.State = connector.State
+	}
+	if connector.Visibility != 0 {
+		c.Visibility = connector.Visibility
+	}
+	if connector.Task != "" {
+		c.Task = connector.Task
+	}
+	c.UpdateTime = time.Now().UTC()
+
+	return nil
+}
+
+func (r *memoryRepository) DeleteConnector(ctx context.Context, id string, ownerPermalink string) error {
+
+	unlock := r.lock()
+	defer unlock()
+
+	c := r.store.first(func(c *datamodel.Connector) bool { return c.ID == id && c.Owner == ownerPermalink })
+	if c == nil {
+		return memoryError(ctx, codes.NotFound, "delete connector", "Not found", "", ownerPermalink)
+	}
+	c.DeleteTime = gorm.DeletedAt{Time: time.Now().UTC(), Valid: true}
+
+	return nil
+}
+
+func (r *memoryRepository) UpdateConnectorID(ctx context.Context, id string, ownerPermalink string, newID string) error {
+	return r.update(ctx, "update connector id", func(c *datamodel.Connector) bool {
+		return c.ID == id && c.Owner == ownerPermalink
+	}, "", ownerPermalink, func(c *datamodel.Connector) error {
+		if r.store.first(func(o *datamodel.Connector) bool { return o != c && o.Owner == c.Owner && o.ID == newID }) != nil {
+			return memoryError(ctx, codes.AlreadyExists, "update connector id", "UNIQUE constraint failed: connector.owner, connector.id", "", ownerPermalink)
+		}
+		c.ID = newID
+		return nil
+	})
+}
+
+func (r *memoryRepository) UpdateConnectorStateByID(ctx context.Context, id string, ownerPermalink string, state datamodel.ConnectorState) error {
+	return r.update(ctx, "update connector state by id", func(c *datamodel.Connector) bool {
+		return c.ID == id && c.Owner == ownerPermalink
+	}, "", ownerPermalink, func(c *datamodel.Connector) error {
+		setState(c, state)
+		return nil
+	})
+}
+
+func (r *memoryRepository) UpdateConnectorStateByUID(ctx context.Context, uid uuid.UUID, ownerPermalink string, state datamodel.ConnectorState) error {
+	return r.update(ctx, "update connector state by uid", func(c *datamodel.Connector) bool {
+		return c.UID == uid && c.Owner == ownerPermalink
+	}, uid.String(), ownerPermalink, func(c *datamodel.Connector) error {
+		setState(c, state)
+		return nil
+	})
+}
+
+func (r *memoryRepository) UpdateConnectorStateErrorByUID(ctx context.Context, uid uuid.UUID, ownerPermalink string, reason string) error {
+	return r.update(ctx, "update connector state error by uid", func(c *datamodel.Connector) bool {
+		return c.UID == uid && c.Owner == ownerPermalink
+	}, uid.String(), ownerPermalink, func(c *datamodel.Connector) error {
+		c.State = datamodel.ConnectorState(connectorPB.Connector_STATE_ERROR)
+		c.StateReason = sql.NullString{String: reason, Valid: true}
+		return nil
+	})
+}
+
+// setState sets the state of a connector as stateUpdates does
+func setState(c *datamodel.Connector, state datamodel.ConnectorState) {
+	c.State = state
+	if state != datamodel.ConnectorState(connectorPB.Connector_STATE_ERROR) {
+		c.StateReason = sql.NullString{}
+	}
+}
+
+func (r *memoryRepository) UpdateConnectorTaskByID(ctx context.Context, id string, ownerPermalink string, task string) error {
+	if task == "" {
+		task = "TASK_UNSPECIFIED"
+	}
+	return r.update(ctx, "update connector task by id", func(c *datamodel.Connector) bool {
+		return c.ID == id && c.Owner == ownerPermalink
+	}, "", ownerPermalink, func(c *datamodel.Connector) error {
+		c.Task = task
+		return nil
+	})
+}
+
+func (r *memoryRepository) ListConnectorDefinitionPolicies(ctx context.Context) ([]*datamodel.ConnectorDefinitionPolicy, error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	policies := make([]*datamodel.ConnectorDefinitionPolicy, 0, len(r.store.policies))
+	for _, policy := range r.store.policies {
+		p := *policy
+		policies = append(policies, &p)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].ConnectorDefinitionID < policies[j].ConnectorDefinitionID
+	})
+
+	return policies, nil
+}
+
+func (r *memoryRepository) GetConnectorDefinitionPolicy(ctx context.Context, connDefUID uuid.UUID) (*datamodel.ConnectorDefinitionPolicy, error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	policy, ok := r.store.policies[connDefUID]
+	if !ok {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get connector definition policy error: %s", gorm.ErrRecordNotFound.Error()),
+			"connector_definition_policy",
+			connDefUID.String(),
+			"",
+			gorm.ErrRecordNotFound.Error(),
+		)
+		if err != nil {
+			logger, _ := logger.GetZapLogger(ctx)
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	p := *policy
+
+	return &p, nil
+}
+
+func (r *memoryRepository) UpsertConnectorDefinitionPolicy(ctx context.Context, policy *datamodel.ConnectorDefinitionPolicy) error {
+
+	unlock := r.lock()
+	defer unlock()
+
+	now := time.Now().UTC()
+	p := *policy
+	if existing, ok := r.store.policies[policy.ConnectorDefinitionUID]; ok {
+		p.CreateTime = existing.CreateTime
+	} else if p.CreateTime.IsZero() {
+		p.CreateTime = now
+	}
+	p.UpdateTime = now
+	r.store.policies[policy.ConnectorDefinitionUID] = &p
+
+	policy.CreateTime = p.CreateTime
+	policy.UpdateTime = p.UpdateTime
+
+	return nil
+}
+
+func (r *memoryRepository) CountConnectorsByDefinitionUID(ctx context.Context, connDefUID uuid.UUID) (int64, error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	var count int64
+	for _, c := range r.store.live() {
+		if c.ConnectorDefinitionUID == connDefUID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *memoryRepository) AggregateConnectorUsage(ctx context.Context) ([]*datamodel.ConnectorUsageCount, error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	type group struct {
+		owner         string
+		connectorType datamodel.ConnectorType
+		state         datamodel.ConnectorState
+		visibility    datamodel.ConnectorVisibility
+		connDefUID    uuid.UUID
+	}
+	counts := map[group]*datamodel.ConnectorUsageCount{}
+	result := []*datamodel.ConnectorUsageCount{}
+	for _, c := range r.store.live() {
+		g := group{c.Owner, c.ConnectorType, c.State, c.Visibility, c.ConnectorDefinitionUID}
+		count, ok := counts[g]
+		if !ok {
+			count = &datamodel.ConnectorUsageCount{
+				Owner:                  c.Owner,
+				ConnectorType:          c.ConnectorType,
+				State:                  c.State,
+				Visibility:             c.Visibility,
+				ConnectorDefinitionUID: c.ConnectorDefinitionUID,
+			}
+			counts[g] = count
+			result = append(result, count)
+		}
+		count.Count++
+	}
+
+	return result, nil
+}
+
+func (r *memoryRepository) ListBuiltinConnectorDefinitions(ctx context.Context) ([]*datamodel.BuiltinConnectorDefinition, error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	defs := make([]*datamodel.BuiltinConnectorDefinition, 0, len(r.store.builtins))
+	for _, def := range r.store.builtins {
+		defs = append(defs, copyBuiltinConnectorDefinition(def))
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		if !defs[i].CreateTime.Equal(defs[j].CreateTime) {
+			return defs[i].CreateTime.Before(defs[j].CreateTime)
+		}
+		return defs[i].ID < defs[j].ID
+	})
+
+	return defs, nil
+}
+
+func (r *memoryRepository) UpsertBuiltinConnectorDefinition(ctx context.Context, def *datamodel.BuiltinConnectorDefinition) error {
+
+	unlock := r.lock()
+	defer unlock()
+
+	now := time.Now().UTC()
+	d := copyBuiltinConnectorDefinition(def)
+	if existing, ok := r.store.builtins[def.UID]; ok {
+		d.CreateTime = existing.CreateTime
+	} else if d.CreateTime.IsZero() {
+		d.CreateTime = now
+	}
+	d.UpdateTime = now
+	r.store.builtins[def.UID] = d
+
+	def.CreateTime = d.CreateTime
+	def.UpdateTime = d.UpdateTime
+
+	return nil
+}
+
+func (r *memoryRepository) DeleteBuiltinConnectorDefinition(ctx context.Context, uid uuid.UUID) error {
+
+	unlock := r.lock()
+	defer unlock()
+
+	if _, ok := r.store.builtins[uid]; !ok {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] delete builtin connector definition error: %s", "Not found"),
+			"builtin_connector_definition",
+			uid.String(),
+			"",
+			"Not found",
+		)
+		if err != nil {
+			logger, _ := logger.GetZapLogger(ctx)
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	delete(r.store.builtins, uid)
+
+	return nil
+}
+
+// copyBuiltinConnectorDefinition returns a copy of def which shares no spec with it
+func copyBuiltinConnectorDefinition(def *datamodel.BuiltinConnectorDefinition) *datamodel.BuiltinConnectorDefinition {
+	d := *def
+	d.Spec = append([]byte{}, def.Spec...)
+	return &d
+}
+
+func (r *memoryRepository) IncrementExecutionMetering(ctx context.Context, metering *datamodel.ExecutionMetering) error {
+
+	unlock := r.lock()
+	defer unlock()
+
+	key := meteringKey{owner: metering.Owner, connectorUID: metering.ConnectorUID, windowStart: metering.WindowStart.UnixNano()}
+	m, ok := r.store.meterings[key]
+	if !ok {
+		m = &datamodel.ExecutionMetering{
+			Owner:                  metering.Owner,
+			ConnectorUID:           metering.ConnectorUID,
+			ConnectorDefinitionUID: metering.ConnectorDefinitionUID,
+			WindowStart:            metering.WindowStart.UTC(),
+		}
+		r.store.meterings[key] = m
+	}
+	m.Executions += metering.Executions
+	m.InputItems += metering.InputItems
+	m.PayloadBytes += metering.PayloadBytes
+
+	return nil
+}
+
+func (r *memoryRepository) ListExecutionMetering(ctx context.Context, ownerPermalink string, since time.Time) ([]*datamodel.ExecutionMetering, error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	meterings := []*datamodel.ExecutionMetering{}
+	for _, metering := range r.store.meterings {
+		if metering.Owner == ownerPermalink && !metering.WindowStart.Before(since) {
+			m := *metering
+			meterings = append(meterings, &m)
+		}
+	}
+	sort.Slice(meterings, func(i, j int) bool {
+		if !meterings[i].WindowStart.Equal(meterings[j].WindowStart) {
+			return meterings[i].WindowStart.Before(meterings[j].WindowStart)
+		}
+		return meterings[i].ConnectorUID.String() < meterings[j].ConnectorUID.String()
+	})
+
+	return meterings, nil
+}
+
+func (r *memoryRepository) CreateAuditEvent(ctx context.Context, event *datamodel.AuditEvent) error {
+
+	unlock := r.lock()
+	defer unlock()
+
+	if event.UID == uuid.Nil {
+		uid, err := uuid.NewV4()
+		if err != nil {
+			return memoryError(ctx, codes.Internal, "create audit event", err.Error(), "", event.Actor)
+		}
+		event.UID = uid
+	}
+	if event.CreateTime.IsZero() {
+		event.CreateTime = time.Now().UTC()
+	}
+	r.store.auditEvents = append(r.store.auditEvents, copyAuditEvent(event))
+
+	return nil
+}
+
+func (r *memoryRepository) ListAuditEvents(ctx context.Context, filter AuditEventFilter, pageSize int64, pageToken string) ([]*datamodel.AuditEvent, string, error) {
+
+	unlock := r.lock()
+	defer unlock()
+
+	var createdAt time.Time
+	var uid string
+	if pageToken != "" {
+		var err error
+		if createdAt, uid, err = paginate.DecodeToken(pageToken); err != nil {
+			st, err := sterr.CreateErrorBadRequest(
+				fmt.Sprintf("[db] list audit event error: %s", err.Error()),
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "page_token",
+						Description: fmt.Sprintf("Invalid page token: %s", err.Error()),
+					},
+				},
+			)
+			if err != nil {
+				logger, _ := logger.GetZapLogger(ctx)
+				logger.Error(err.Error())
+			}
+			return nil, "", st.Err()
+		}
+	}
+
+	// before orders the events by create_time DESC, uid DESC
+	before := func(e *datamodel.AuditEvent, createTime time.Time, uid string) bool {
+		if !e.CreateTime.Equal(createTime) {
+			return e.CreateTime.Before(createTime)
+		}
+		return e.UID.String() < uid
+	}
+
+	var matched []*datamodel.AuditEvent
+	for _, e := range r.store.auditEvents {
+		switch {
+		case !filter.StartTime.IsZero() && e.CreateTime.Before(filter.StartTime),
+			!filter.EndTime.IsZero() && !e.CreateTime.Before(filter.EndTime),
+			filter.Actor != "" && e.Actor != filter.Actor,
+			filter.ConnectorUID != uuid.Nil && (!e.ConnectorUID.Valid || e.ConnectorUID.UUID != filter.ConnectorUID),
+			pageToken != "" && !before(e, createdAt, uid):
+			continue
+		}
+		matched = append(matched, e)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return before(matched[j], matched[i].CreateTime, matched[i].UID.String())
+	})
+
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	nextPageToken := ""
+	if int64(len(matched)) > pageSize {
+		matched = matched[:pageSize]
+		last := matched[len(matched)-1]
+		nextPageToken = paginate.EncodeToken(last.CreateTime, last.UID.String())
+	}
+
+	events := make([]*datamodel.AuditEvent, len(matched))
+	for idx, e := range matched {
+		events[idx] = copyAuditEvent(e)
+	}
+
+	return events, nextPageToken, nil
+}
+
+// copyAuditEvent returns a copy of event which shares no diff with it
+func copyAuditEvent(event *datamodel.AuditEvent) *datamodel.AuditEvent {
+	e := *event
+	if event.Diff != nil {
+		e.Diff = append([]byte{}, event.Diff...)
+	}
+	return &e
+}
+
+// update applies set to the connector matching match and bumps its update time
+func (r *memoryRepository) update(ctx context.Context, operation string, match func(c *datamodel.Connector) bool, uid string, ownerPermalink string, set func(c *datamodel.Connector) error) error {
+
+	unlock := r.lock()
+	defer unlock()
+
+	c := r.store.first(match)
+	if c == nil {
+		return memoryError(ctx, codes.NotFound, operation, "Not found", uid, ownerPermalink)
+	}
+	if err := set(c); err != nil {
+		return err
+	}
+	c.UpdateTime = time.Now().UTC()
+
+	return nil
+}
+
+// live returns the connectors which are not soft deleted
+func (s *memoryStore) live() []*datamodel.Connector {
+	connectors := make([]*datamodel.Connector, 0, len(s.connectors))
+	for _, c := range s.connectors {
+		if !c.DeleteTime.Valid {
+			connectors = append(connectors, c)
+		}
+	}
+	return connectors
+}
+
+// first returns the live connector with the lowest uid matching match, as
+// First does with the primary key ordering
+func (s *memoryStore) first(match func(c *datamodel.Connector) bool) *datamodel.Connector {
+	var found *datamodel.Connector
+	for _, c := range s.live() {
+		if match(c) && (found == nil || c.UID.String() < found.UID.String()) {
+			found = c
+		}
+	}
+	return found
+}
+
+func isVisible(c *datamodel.Connector, ownerPermalink string) bool {
+	return c.Owner == ownerPermalink || c.Visibility == datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC)
+}
+
+// isBefore reports whether (create_time, uid) of c is lower than (createTime, uid)
+func isBefore(c *datamodel.Connector, createTime time.Time, uid string) bool {
+	if !c.CreateTime.Equal(createTime) {
+		return c.CreateTime.Before(createTime)
+	}
+	return c.UID.String() < uid
+}
+
+func copyConnector(c *datamodel.Connector, isBasicView bool) *datamodel.Connector {
+	connector := *c
+	if isBasicView || c.Configuration == nil {
+		connector.Configuration = nil
+	} else {
+		connector.Configuration = append([]byte{}, c.Configuration...)
+	}
+	return &connector
+}
+
+func memoryError(ctx context.Context, code codes.Code, operation string, message string, uid string, ownerPermalink string) error {
+	st, err := sterr.CreateErrorResourceInfo(
+		code,
+		fmt.Sprintf("[db] %s error: %s", operation, message),
+		"connector",
+		uid,
+		ownerPermalink,
+		message,
+	)
+	if err != nil {
+		logger, _ := logger.GetZapLogger(ctx)
+		logger.Error(err.Error())
+	}
+	return st.Err()
+}
@@ -0,0 +1,20 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/connector-backend/pkg/repository/repositorytest"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.Repository {
+		return repository.NewMemoryRepository()
+	})
+}
+
+func BenchmarkMemoryConnectorUsage(b *testing.B) {
+	repositorytest.BenchmarkConnectorUsage(b, func(b *testing.B) repository.Repository {
+		return repository.NewMemoryRepository()
+	})
+}
@@ -0,0 +1,55 @@
+//go:build postgres
+
+package repository_test
+
+import (
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/connector-backend/pkg/repository/repositorytest"
+)
+
+// The Postgres tests run with the postgres build tag on the migrated
+// database of TEST_POSTGRES_DSN, e.g.,
+//
+//	TEST_POSTGRES_DSN="host=localhost user=postgres password=password dbname=connector port=5432 sslmode=disable" \
+//		go test -tags postgres ./pkg/repository/...
+//
+// The tables are truncated before every test.
+func openPostgres(tb testing.TB) *gorm.DB {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		tb.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	postgresDB, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  dsn,
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{})
+	if err != nil {
+		tb.Fatalf("open the Postgres database: %v", err)
+	}
+	if err := postgresDB.Exec("TRUNCATE connector, connector_definition_policy, builtin_connector_definition, execution_metering, audit_event").Error; err != nil {
+		tb.Fatalf("truncate the tables: %v", err)
+	}
+	tb.Cleanup(func() {
+		sqlDB, _ := postgresDB.DB()
+		sqlDB.Close()
+	})
+	return postgresDB
+}
+
+func TestPostgresRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.Repository {
+		return repository.NewRepository(openPostgres(t))
+	})
+}
+
+func BenchmarkPostgresConnectorUsage(b *testing.B) {
+	repositorytest.BenchmarkConnectorUsage(b, func(b *testing.B) repository.Repository {
+		return repository.NewRepository(openPostgres(b))
+	})
+}
@@ -2,13 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/gogo/status"
-	"github.com/jackc/pgx/v5/pgconn"
 	"go.einride.tech/aip/filtering"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
@@ -32,6 +33,10 @@ const MaxPageSize = 100
 // Repository interface
 type Repository interface {
 
+	// Transaction runs fn with a repository bound to a single database
+	// transaction, which is committed if fn returns nil and rolled back otherwise
+	Transaction(ctx context.Context, fn func(r Repository) error) error
+
 	// Connector
 	CreateConnector(ctx context.Context, connector *datamodel.Connector) error
 	ListConnectors(ctx context.Context, ownerPermalink string, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter) ([]*datamodel.Connector, int64, string, error)
@@ -42,10 +47,44 @@ type Repository interface {
 	UpdateConnectorID(ctx context.Context, id string, ownerPermalink string, newID string) error
 	UpdateConnectorStateByID(ctx context.Context, id string, ownerPermalink string, state datamodel.ConnectorState) error
 	UpdateConnectorStateByUID(ctx context.Context, uid uuid.UUID, ownerPermalink string, state datamodel.ConnectorState) error
+	// UpdateConnectorStateErrorByUID sets the state of a connector to
+	// STATE_ERROR with its reason, the other state updates clear the reason
+	UpdateConnectorStateErrorByUID(ctx context.Context, uid uuid.UUID, ownerPermalink string, reason string) error
 	UpdateConnectorTaskByID(ctx context.Context, id string, ownerPermalink string, task string) error
 
 	ListConnectorsAdmin(ctx context.Context, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter) ([]*datamodel.Connector, int64, string, error)
 	GetConnectorByUIDAdmin(ctx context.Context, uid uuid.UUID, isBasicView bool) (*datamodel.Connector, error)
+
+	// AggregateConnectorUsage counts the live connectors grouped by owner, type, state, visibility and definition, in no particular order
+	AggregateConnectorUsage(ctx context.Context) ([]*datamodel.ConnectorUsageCount, error)
+
+	// Connector definition policy
+	ListConnectorDefinitionPolicies(ctx context.Context) ([]*datamodel.ConnectorDefinitionPolicy, error)
+	GetConnectorDefinitionPolicy(ctx context.Context, connDefUID uuid.UUID) (*datamodel.ConnectorDefinitionPolicy, error)
+	UpsertConnectorDefinitionPolicy(ctx context.Context, policy *datamodel.ConnectorDefinitionPolicy) error
+	CountConnectorsByDefinitionUID(ctx context.Context, connDefUID uuid.UUID) (int64, error)
+
+	// Builtin connector definition
+	ListBuiltinConnectorDefinitions(ctx context.Context) ([]*datamodel.BuiltinConnectorDefinition, error)
+	UpsertBuiltinConnectorDefinition(ctx context.Context, def *datamodel.BuiltinConnectorDefinition) error
+	DeleteBuiltinConnectorDefinition(ctx context.Context, uid uuid.UUID) error
+
+	// Execution metering
+	IncrementExecutionMetering(ctx context.Context, metering *datamodel.ExecutionMetering) error
+	ListExecutionMetering(ctx context.Context, ownerPermalink string, since time.Time) ([]*datamodel.ExecutionMetering, error)
+
+	// Audit events, which are never updated nor deleted
+	CreateAuditEvent(ctx context.Context, event *datamodel.AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter AuditEventFilter, pageSize int64, pageToken string) ([]*datamodel.AuditEvent, string, error)
+}
+
+// AuditEventFilter selects the audit events created in [StartTime, EndTime)
+// by Actor on the connector ConnectorUID, the zero fields match all events
+type AuditEventFilter struct {
+	StartTime    time.Time
+	EndTime      time.Time
+	Actor        string
+	ConnectorUID uuid.UUID
 }
 
 type repository struct {
@@ -59,28 +98,43 @@ func NewRepository(db *gorm.DB) Repository {
 	}
 }
 
+func (r *repository) Transaction(ctx context.Context, fn func(r Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{db: tx})
+	})
+}
+
 func (r *repository) CreateConnector(ctx context.Context, connector *datamodel.Connector) error {
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	if result := r.db.Model(&datamodel.Connector{}).Create(connector); result.Error != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(result.Error, &pgErr) {
-			if pgErr.Code == "23505" {
-				st, err := sterr.CreateErrorResourceInfo(
-					codes.AlreadyExists,
-					fmt.Sprintf("[db] create connector error: %s", pgErr.Message),
-					"connector",
-					fmt.Sprintf("id %s", connector.ID),
-					connector.Owner,
-					pgErr.Message,
-				)
-				if err != nil {
-					logger.Error(err.Error())
-				}
-				return st.Err()
+	if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).Create(connector); result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			st, err := sterr.CreateErrorResourceInfo(
+				codes.AlreadyExists,
+				fmt.Sprintf("[db] create connector error: %s", result.Error.Error()),
+				"connector",
+				fmt.Sprintf("id %s", connector.ID),
+				connector.Owner,
+				result.Error.Error(),
+			)
+			if err != nil {
+				logger.Error(err.Error())
 			}
+			return st.Err()
 		}
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] create connector error: %s", result.Error.Error()),
+			"connector",
+			fmt.Sprintf("id %s", connector.ID),
+			connector.Owner,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
 	}
 	return nil
 }
@@ -95,12 +149,12 @@ func (r *repository) ListConnectors(ctx context.Context, ownerPermalink string,
 	}
 
 	if expr == nil {
-		r.db.Model(&datamodel.Connector{}).Where("owner = ? or visibility = ?", ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC)).Count(&totalSize)
+		r.db.WithContext(ctx).Model(&datamodel.Connector{}).Where("owner = ? or visibility = ?", ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC)).Count(&totalSize)
 	} else {
-		r.db.Model(&datamodel.Connector{}).Where("(owner = ? or visibility = ?) and ?", ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC), expr).Count(&totalSize)
+		r.db.WithContext(ctx).Model(&datamodel.Connector{}).Where("(owner = ? or visibility = ?) and (?)", ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC), expr).Count(&totalSize)
 	}
 
-	queryBuilder := r.db.Model(&datamodel.Connector{}).Order("create_time DESC, uid DESC").Where("owner = ? or visibility = ?", ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC))
+	queryBuilder := r.db.WithContext(ctx).Model(&datamodel.Connector{}).Order("create_time DESC, uid DESC").Where("owner = ? or visibility = ?", ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC))
 
 	if pageSize == 0 {
 		pageSize = DefaultPageSize
@@ -128,7 +182,7 @@ func (r *repository) ListConnectors(ctx context.Context, ownerPermalink string,
 			return nil, 0, "", st.Err()
 		}
 
-		queryBuilder = queryBuilder.Where("(create_time,uid) < (?::timestamp, ?)", createdAt, uid)
+		queryBuilder = queryBuilder.Where(r.paginationCondition(), createdAt.UTC(), uid)
 	}
 
 	if isBasicView {
@@ -180,7 +234,7 @@ func (r *repository) ListConnectors(ctx context.Context, ownerPermalink string,
 		lastUID := (connectors)[len(connectors)-1].UID
 		lastItem := &datamodel.Connector{}
 		if expr == nil {
-			if result := r.db.Model(&datamodel.Connector{}).
+			if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 				Where("owner = ? or visibility = ?", ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC)).
 				Order("create_time ASC, uid ASC").Limit(1).Find(lastItem); result.Error != nil {
 				st, err := sterr.CreateErrorResourceInfo(
@@ -197,8 +251,8 @@ func (r *repository) ListConnectors(ctx context.Context, ownerPermalink string,
 				return nil, 0, "", st.Err()
 			}
 		} else {
-			if result := r.db.Model(&datamodel.Connector{}).
-				Where("(owner = ? or visibility = ?) and ?", ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC), expr).
+			if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
+				Where("(owner = ? or visibility = ?) and (?)", ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC), expr).
 				Order("create_time ASC, uid ASC").Limit(1).Find(lastItem); result.Error != nil {
 				st, err := sterr.CreateErrorResourceInfo(
 					codes.Internal,
@@ -234,12 +288,12 @@ func (r *repository) ListConnectorsAdmin(ctx context.Context, pageSize int64, pa
 	}
 
 	if expr == nil {
-		r.db.Model(&datamodel.Connector{}).Count(&totalSize)
+		r.db.WithContext(ctx).Model(&datamodel.Connector{}).Count(&totalSize)
 	} else {
-		r.db.Model(&datamodel.Connector{}).Where("?", expr).Count(&totalSize)
+		r.db.WithContext(ctx).Model(&datamodel.Connector{}).Where("?", expr).Count(&totalSize)
 	}
 
-	queryBuilder := r.db.Model(&datamodel.Connector{}).Order("create_time DESC, uid DESC")
+	queryBuilder := r.db.WithContext(ctx).Model(&datamodel.Connector{}).Order("create_time DESC, uid DESC")
 
 	if pageSize == 0 {
 		pageSize = DefaultPageSize
@@ -271,7 +325,7 @@ func (r *repository) ListConnectorsAdmin(ctx context.Context, pageSize int64, pa
 			return nil, 0, "", st.Err()
 		}
 
-		queryBuilder = queryBuilder.Where("(create_time,uid) < (?::timestamp, ?)", createdAt, uid)
+		queryBuilder = queryBuilder.Where(r.paginationCondition(), createdAt.UTC(), uid)
 	}
 
 	if isBasicView {
@@ -319,7 +373,7 @@ func (r *repository) ListConnectorsAdmin(ctx context.Context, pageSize int64, pa
 		lastUID := (connectors)[len(connectors)-1].UID
 		lastItem := &datamodel.Connector{}
 		if expr == nil {
-			if result := r.db.Model(&datamodel.Connector{}).
+			if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 				Order("create_time ASC, uid ASC").Limit(1).Find(lastItem); result.Error != nil {
 				st, err := sterr.CreateErrorResourceInfo(
 					codes.Internal,
@@ -335,7 +389,7 @@ func (r *repository) ListConnectorsAdmin(ctx context.Context, pageSize int64, pa
 				return nil, 0, "", st.Err()
 			}
 		} else {
-			if result := r.db.Model(&datamodel.Connector{}).
+			if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 				Where("?", expr).
 				Order("create_time ASC, uid ASC").Limit(1).Find(lastItem); result.Error != nil {
 				st, err := sterr.CreateErrorResourceInfo(
@@ -369,14 +423,23 @@ func (r *repository) GetConnectorByID(ctx context.Context, id string, ownerPerma
 
 	var connector datamodel.Connector
 
-	queryBuilder := r.db.Model(&datamodel.Connector{}).
-		Where("id = ? AND (owner = ? or visibility = ?)", id, ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC))
+	// The connector of the owner comes before a public connector of another
+	// owner with the same id
+	queryBuilder := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
+		Where("id = ? AND (owner = ? or visibility = ?)", id, ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC)).
+		Clauses(clause.OrderBy{Expression: clause.Expr{SQL: "owner = ? DESC, uid", Vars: []interface{}{ownerPermalink}, WithoutParentheses: true}}).
+		Limit(1)
 
 	if isBasicView {
 		queryBuilder.Omit("configuration")
 	}
 
-	if result := queryBuilder.First(&connector); result.Error != nil {
+	// First would replace the order by the primary key
+	result := queryBuilder.Find(&connector)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = gorm.ErrRecordNotFound
+	}
+	if result.Error != nil {
 		st, err := sterr.CreateErrorResourceInfo(
 			codes.NotFound,
 			fmt.Sprintf("[db] get connector by id error: %s", result.Error.Error()),
@@ -399,7 +462,7 @@ func (r *repository) GetConnectorByUID(ctx context.Context, uid uuid.UUID, owner
 
 	var connector datamodel.Connector
 
-	queryBuilder := r.db.Model(&datamodel.Connector{}).
+	queryBuilder := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 		Where("uid = ? AND (owner = ? or visibility = ?)", uid, ownerPermalink, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC))
 
 	if isBasicView {
@@ -429,7 +492,7 @@ func (r *repository) GetConnectorByUIDAdmin(ctx context.Context, uid uuid.UUID,
 
 	var connector datamodel.Connector
 
-	queryBuilder := r.db.Model(&datamodel.Connector{}).
+	queryBuilder := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 		Where("uid = ?", uid)
 
 	if isBasicView {
@@ -457,9 +520,23 @@ func (r *repository) UpdateConnector(ctx context.Context, id string, ownerPermal
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	if result := r.db.Model(&datamodel.Connector{}).
+	if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 		Where("id = ? AND owner = ?", id, ownerPermalink).
 		Updates(connector); result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			st, err := sterr.CreateErrorResourceInfo(
+				codes.AlreadyExists,
+				fmt.Sprintf("[db] update connector error: %s", result.Error.Error()),
+				"connector",
+				"",
+				ownerPermalink,
+				result.Error.Error(),
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return st.Err()
+		}
 		st, err := sterr.CreateErrorResourceInfo(
 			codes.Internal,
 			fmt.Sprintf("[db] update connector error: %s", result.Error.Error()),
@@ -493,7 +570,7 @@ func (r *repository) DeleteConnector(ctx context.Context, id string, ownerPermal
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	result := r.db.Model(&datamodel.Connector{}).
+	result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 		Where("id = ? AND owner = ? ", id, ownerPermalink).
 		Delete(&datamodel.Connector{})
 
@@ -534,9 +611,23 @@ func (r *repository) UpdateConnectorID(ctx context.Context, id string, ownerPerm
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	if result := r.db.Model(&datamodel.Connector{}).
+	if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 		Where("id = ? AND owner = ? ", id, ownerPermalink).
 		Update("id", newID); result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			st, err := sterr.CreateErrorResourceInfo(
+				codes.AlreadyExists,
+				fmt.Sprintf("[db] update connector id error: %s", result.Error.Error()),
+				"connector",
+				"",
+				ownerPermalink,
+				result.Error.Error(),
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return st.Err()
+		}
 		st, err := sterr.CreateErrorResourceInfo(
 			codes.Internal,
 			fmt.Sprintf("[db] update connector id error: %s", result.Error.Error()),
@@ -570,9 +661,9 @@ func (r *repository) UpdateConnectorStateByID(ctx context.Context, id string, ow
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	if result := r.db.Model(&datamodel.Connector{}).
+	if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 		Where("id = ? AND owner = ?", id, ownerPermalink).
-		Update("state", state); result.Error != nil {
+		Updates(stateUpdates(state)); result.Error != nil {
 		st, err := sterr.CreateErrorResourceInfo(
 			codes.Internal,
 			fmt.Sprintf("[db] update connector state by id error: %s", result.Error.Error()),
@@ -609,7 +700,7 @@ func (r *repository) UpdateConnectorTaskByID(ctx context.Context, id string, own
 	if task == "" {
 		task = "TASK_UNSPECIFIED"
 	}
-	if result := r.db.Model(&datamodel.Connector{}).
+	if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 		Where("id = ? AND owner = ?", id, ownerPermalink).
 		Update("task", task); result.Error != nil {
 		st, err := sterr.CreateErrorResourceInfo(
@@ -645,9 +736,9 @@ func (r *repository) UpdateConnectorStateByUID(ctx context.Context, uid uuid.UUI
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	if result := r.db.Model(&datamodel.Connector{}).
+	if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
 		Where("uid = ? AND owner = ?", uid, ownerPermalink).
-		Update("state", state); result.Error != nil {
+		Updates(stateUpdates(state)); result.Error != nil {
 		st, err := sterr.CreateErrorResourceInfo(
 			codes.Internal,
 			fmt.Sprintf("[db] update connector state by uid error: %s", result.Error.Error()),
@@ -678,9 +769,461 @@ func (r *repository) UpdateConnectorStateByUID(ctx context.Context, uid uuid.UUI
 	return nil
 }
 
+// stateUpdates returns the columns of a state update, the reason of an error
+// state is cleared when the connector leaves it
+func stateUpdates(state datamodel.ConnectorState) map[string]interface{} {
+	updates := map[string]interface{}{"state": state}
+	if state != datamodel.ConnectorState(connectorPB.Connector_STATE_ERROR) {
+		updates["state_reason"] = sql.NullString{}
+	}
+	return updates
+}
+
+func (r *repository) UpdateConnectorStateErrorByUID(ctx context.Context, uid uuid.UUID, ownerPermalink string, reason string) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
+		Where("uid = ? AND owner = ?", uid, ownerPermalink).
+		Updates(map[string]interface{}{
+			"state":        datamodel.ConnectorState(connectorPB.Connector_STATE_ERROR),
+			"state_reason": sql.NullString{String: reason, Valid: true},
+		}); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] update connector state error by uid error: %s", result.Error.Error()),
+			"connector",
+			uid.String(),
+			ownerPermalink,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	} else if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] update connector state error by uid error: %s", "Not found"),
+			"connector",
+			uid.String(),
+			ownerPermalink,
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) ListConnectorDefinitionPolicies(ctx context.Context) ([]*datamodel.ConnectorDefinitionPolicy, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var policies []*datamodel.ConnectorDefinitionPolicy
+	if result := r.db.WithContext(ctx).Model(&datamodel.ConnectorDefinitionPolicy{}).Order("connector_definition_id").Find(&policies); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list connector definition policies error: %s", result.Error.Error()),
+			"connector_definition_policy",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	return policies, nil
+}
+
+func (r *repository) GetConnectorDefinitionPolicy(ctx context.Context, connDefUID uuid.UUID) (*datamodel.ConnectorDefinitionPolicy, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var policy datamodel.ConnectorDefinitionPolicy
+	if result := r.db.WithContext(ctx).Model(&datamodel.ConnectorDefinitionPolicy{}).
+		Where("connector_definition_uid = ?", connDefUID).
+		First(&policy); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get connector definition policy error: %s", result.Error.Error()),
+			"connector_definition_policy",
+			connDefUID.String(),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	return &policy, nil
+}
+
+func (r *repository) UpsertConnectorDefinitionPolicy(ctx context.Context, policy *datamodel.ConnectorDefinitionPolicy) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.WithContext(ctx).Model(&datamodel.ConnectorDefinitionPolicy{}).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "connector_definition_uid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"connector_definition_id", "state", "message", "update_time"}),
+		}).
+		Create(policy); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] upsert connector definition policy error: %s", result.Error.Error()),
+			"connector_definition_policy",
+			policy.ConnectorDefinitionUID.String(),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	return nil
+}
+
 // TranspileFilter transpiles a parsed AIP filter expression to GORM DB clauses
 func (r *repository) transpileFilter(filter filtering.Filter) (*clause.Expr, error) {
 	return (&Transpiler{
 		filter: filter,
 	}).Transpile()
 }
+
+// paginationCondition returns the keyset pagination condition on
+// (create_time, uid), the timestamp cast is specific to Postgres
+func (r *repository) paginationCondition() string {
+	if r.db.Dialector.Name() == "postgres" {
+		return "(create_time,uid) < (?::timestamp, ?)"
+	}
+	return "(create_time,uid) < (?, ?)"
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation of
+// Postgres or SQLite. The Postgres error is matched on its SQLSTATE, which
+// the pgconn errors of pgx v4 (used by the GORM driver) and v5 both expose.
+func isUniqueViolation(err error) bool {
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		return pgErr.SQLState() == "23505"
+	}
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+func (r *repository) CountConnectorsByDefinitionUID(ctx context.Context, connDefUID uuid.UUID) (int64, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var count int64
+	if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
+		Where("connector_definition_uid = ?", connDefUID).
+		Count(&count); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] count connectors by definition uid error: %s", result.Error.Error()),
+			"connector",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return 0, st.Err()
+	}
+
+	return count, nil
+}
+
+func (r *repository) AggregateConnectorUsage(ctx context.Context) ([]*datamodel.ConnectorUsageCount, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var counts []*datamodel.ConnectorUsageCount
+	if result := r.db.WithContext(ctx).Model(&datamodel.Connector{}).
+		Select("owner, connector_type, state, visibility, connector_definition_uid, COUNT(*) AS count").
+		Group("owner, connector_type, state, visibility, connector_definition_uid").
+		Scan(&counts); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] aggregate connector usage error: %s", result.Error.Error()),
+			"connector",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	return counts, nil
+}
+
+func (r *repository) ListBuiltinConnectorDefinitions(ctx context.Context) ([]*datamodel.BuiltinConnectorDefinition, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var defs []*datamodel.BuiltinConnectorDefinition
+	if result := r.db.WithContext(ctx).Model(&datamodel.BuiltinConnectorDefinition{}).Order("create_time, id").Find(&defs); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list builtin connector definitions error: %s", result.Error.Error()),
+			"builtin_connector_definition",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	return defs, nil
+}
+
+func (r *repository) UpsertBuiltinConnectorDefinition(ctx context.Context, def *datamodel.BuiltinConnectorDefinition) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.WithContext(ctx).Model(&datamodel.BuiltinConnectorDefinition{}).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "uid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"id", "spec", "update_time"}),
+		}).
+		Create(def); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] upsert builtin connector definition error: %s", result.Error.Error()),
+			"builtin_connector_definition",
+			def.ID,
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteBuiltinConnectorDefinition(ctx context.Context, uid uuid.UUID) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	result := r.db.WithContext(ctx).Model(&datamodel.BuiltinConnectorDefinition{}).
+		Where("uid = ?", uid).
+		Delete(&datamodel.BuiltinConnectorDefinition{})
+
+	if result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] delete builtin connector definition error: %s", result.Error.Error()),
+			"builtin_connector_definition",
+			uid.String(),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] delete builtin connector definition error: %s", "Not found"),
+			"builtin_connector_definition",
+			uid.String(),
+			"",
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	return nil
+}
+
+// IncrementExecutionMetering adds the counters of metering to those of its
+// owner, connector and window
+func (r *repository) IncrementExecutionMetering(ctx context.Context, metering *datamodel.ExecutionMetering) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.WithContext(ctx).Model(&datamodel.ExecutionMetering{}).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "owner"}, {Name: "connector_uid"}, {Name: "window_start"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"executions":    gorm.Expr("execution_metering.executions + excluded.executions"),
+				"input_items":   gorm.Expr("execution_metering.input_items + excluded.input_items"),
+				"payload_bytes": gorm.Expr("execution_metering.payload_bytes + excluded.payload_bytes"),
+			}),
+		}).
+		Create(metering); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] increment execution metering error: %s", result.Error.Error()),
+			"execution_metering",
+			metering.ConnectorUID.String(),
+			metering.Owner,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	return nil
+}
+
+// ListExecutionMetering returns the counters of the owner in the windows
+// starting at since or later, ordered by window and connector
+func (r *repository) ListExecutionMetering(ctx context.Context, ownerPermalink string, since time.Time) ([]*datamodel.ExecutionMetering, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var meterings []*datamodel.ExecutionMetering
+	if result := r.db.WithContext(ctx).Model(&datamodel.ExecutionMetering{}).
+		Where("owner = ? AND window_start >= ?", ownerPermalink, since).
+		Order("window_start, connector_uid").
+		Find(&meterings); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list execution metering error: %s", result.Error.Error()),
+			"execution_metering",
+			"",
+			ownerPermalink,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	return meterings, nil
+}
+
+// CreateAuditEvent appends an audit event, its uid and create time are set if empty
+func (r *repository) CreateAuditEvent(ctx context.Context, event *datamodel.AuditEvent) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if event.UID == uuid.Nil {
+		uid, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		event.UID = uid
+	}
+
+	if result := r.db.WithContext(ctx).Model(&datamodel.AuditEvent{}).Create(event); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] create audit event error: %s", result.Error.Error()),
+			"audit_event",
+			event.UID.String(),
+			event.Actor,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	return nil
+}
+
+// ListAuditEvents returns a page of the audit events matching filter, from
+// the newest, and the token of the next page, empty on the last page
+func (r *repository) ListAuditEvents(ctx context.Context, filter AuditEventFilter, pageSize int64, pageToken string) ([]*datamodel.AuditEvent, string, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	// One more event is fetched to tell whether there is a next page
+	queryBuilder := r.db.WithContext(ctx).Model(&datamodel.AuditEvent{}).Order("create_time DESC, uid DESC").Limit(int(pageSize) + 1)
+
+	if !filter.StartTime.IsZero() {
+		queryBuilder = queryBuilder.Where("create_time >= ?", filter.StartTime.UTC())
+	}
+	if !filter.EndTime.IsZero() {
+		queryBuilder = queryBuilder.Where("create_time < ?", filter.EndTime.UTC())
+	}
+	if filter.Actor != "" {
+		queryBuilder = queryBuilder.Where("actor = ?", filter.Actor)
+	}
+	if filter.ConnectorUID != uuid.Nil {
+		queryBuilder = queryBuilder.Where("connector_uid = ?", filter.ConnectorUID)
+	}
+
+	if pageToken != "" {
+		createdAt, uid, err := paginate.DecodeToken(pageToken)
+		if err != nil {
+			st, err := sterr.CreateErrorBadRequest(
+				fmt.Sprintf("[db] list audit event error: %s", err.Error()),
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "page_token",
+						Description: fmt.Sprintf("Invalid page token: %s", err.Error()),
+					},
+				},
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return nil, "", st.Err()
+		}
+		queryBuilder = queryBuilder.Where(r.paginationCondition(), createdAt.UTC(), uid)
+	}
+
+	var events []*datamodel.AuditEvent
+	if result := queryBuilder.Find(&events); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list audit event error: %s", result.Error.Error()),
+			"audit_event",
+			"",
+			filter.Actor,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, "", st.Err()
+	}
+
+	nextPageToken := ""
+	if int64(len(events)) > pageSize {
+		events = events[:pageSize]
+		last := events[len(events)-1]
+		nextPageToken = paginate.EncodeToken(last.CreateTime, last.UID.String())
+	}
+
+	return events, nextPageToken, nil
+}
@@ -0,0 +1,32 @@
+package repository_test
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/instill-ai/connector-backend/pkg/db"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/connector-backend/pkg/repository/repositorytest"
+)
+
+func openSQLite(tb testing.TB) *gorm.DB {
+	sqliteDB, err := db.OpenSQLite(":memory:")
+	if err != nil {
+		tb.Fatalf("open the SQLite database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close(sqliteDB) })
+	return sqliteDB
+}
+
+func TestSQLiteRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.Repository {
+		return repository.NewRepository(openSQLite(t))
+	})
+}
+
+func BenchmarkSQLiteConnectorUsage(b *testing.B) {
+	repositorytest.BenchmarkConnectorUsage(b, func(b *testing.B) repository.Repository {
+		return repository.NewRepository(openSQLite(b))
+	})
+}
@@ -0,0 +1,822 @@
+// Package repositorytest provides a conformance suite for the implementations
+// of repository.Repository, which checks that they behave as the Postgres
+// repository. The suite is run from the tests of an implementation, e.g.,
+//
+//	func TestMemoryRepository(t *testing.T) {
+//		repositorytest.Run(t, func(t *testing.T) repository.Repository {
+//			return repository.NewMemoryRepository()
+//		})
+//	}
+//
+// The factory is called for every test and must return an empty repository,
+// e.g., a repository on a freshly migrated Postgres database or on a new
+// SQLite ":memory:" database.
+//
+// BenchmarkConnectorUsage is run from the benchmarks of an implementation in
+// the same way.
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+const (
+	owner      = "users/2a06c2f7-8da9-4046-91ea-240f88a5d000"
+	otherOwner = "users/2a06c2f7-8da9-4046-91ea-240f88a5d001"
+)
+
+// Run runs the conformance suite on the repositories returned by newRepository
+func Run(t *testing.T, newRepository func(t *testing.T) repository.Repository) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, r repository.Repository)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"AlreadyExists", testAlreadyExists},
+		{"SoftDelete", testSoftDelete},
+		{"Visibility", testVisibility},
+		{"Pagination", testPagination},
+		{"Filter", testFilter},
+		{"Update", testUpdate},
+		{"Transaction", testTransaction},
+		{"DefinitionPolicy", testDefinitionPolicy},
+		{"BuiltinConnectorDefinition", testBuiltinConnectorDefinition},
+		{"ConnectorUsage", testConnectorUsage},
+		{"ExecutionMetering", testExecutionMetering},
+		{"AuditEvents", testAuditEvents},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepository(t))
+		})
+	}
+}
+
+func newConnector(ownerPermalink string, id string) *datamodel.Connector {
+	return &datamodel.Connector{
+		ID:                     id,
+		Owner:                  ownerPermalink,
+		ConnectorDefinitionUID: uuid.Must(uuid.NewV4()),
+		Description:            sql.NullString{String: "description of " + id, Valid: true},
+		Configuration:          []byte(`{"key": "value", "nested": {"n": 1}}`),
+		ConnectorType:          datamodel.ConnectorType(connectorPB.ConnectorType_CONNECTOR_TYPE_DESTINATION),
+		State:                  datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED),
+		Visibility:             datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PRIVATE),
+		Task:                   "TASK_UNSPECIFIED",
+	}
+}
+
+func create(t testing.TB, r repository.Repository, connector *datamodel.Connector) *datamodel.Connector {
+	t.Helper()
+	if err := r.CreateConnector(context.Background(), connector); err != nil {
+		t.Fatalf("create connector %s: %v", connector.ID, err)
+	}
+	return connector
+}
+
+func parseFilter(t testing.TB, filter string) filtering.Filter {
+	t.Helper()
+	var connType connectorPB.ConnectorType
+	declarations, err := filtering.NewDeclarations([]filtering.DeclarationOption{
+		filtering.DeclareStandardFunctions(),
+		filtering.DeclareEnumIdent("connector_type", connType.Type()),
+		filtering.DeclareIdent("id", filtering.TypeString),
+		filtering.DeclareIdent("create_time", filtering.TypeTimestamp),
+	}...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := filtering.ParseFilter(filterRequest(filter), declarations)
+	if err != nil {
+		t.Fatalf("parse filter %q: %v", filter, err)
+	}
+	return f
+}
+
+type filterRequest string
+
+func (f filterRequest) GetFilter() string {
+	return string(f)
+}
+
+func expectCode(t *testing.T, err error, code codes.Code, what string) {
+	t.Helper()
+	if status.Code(err) != code {
+		t.Fatalf("%s: expected code %s, got %v", what, code, err)
+	}
+}
+
+func equalJSON(a []byte, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func ids(connectors []*datamodel.Connector) []string {
+	ids := make([]string, 0, len(connectors))
+	for _, c := range connectors {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func listAll(t *testing.T, r repository.Repository, ownerPermalink string, filter string) []*datamodel.Connector {
+	t.Helper()
+	var all []*datamodel.Connector
+	pageToken := ""
+	for {
+		connectors, _, nextPageToken, err := r.ListConnectors(context.Background(), ownerPermalink, repository.MaxPageSize, pageToken, true, parseFilter(t, filter))
+		if err != nil {
+			t.Fatalf("list connectors with filter %q: %v", filter, err)
+		}
+		all = append(all, connectors...)
+		if nextPageToken == "" {
+			return all
+		}
+		pageToken = nextPageToken
+	}
+}
+
+func testCreateAndGet(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	created := create(t, r, newConnector(owner, "conn"))
+	if created.UID == uuid.Nil || created.CreateTime.IsZero() || created.UpdateTime.IsZero() {
+		t.Fatalf("create connector: uid and timestamps not set: %+v", created)
+	}
+
+	byID, err := r.GetConnectorByID(ctx, "conn", owner, false)
+	if err != nil {
+		t.Fatalf("get connector by id: %v", err)
+	}
+	if byID.UID != created.UID || byID.Owner != owner || byID.Description != created.Description ||
+		byID.ConnectorType != created.ConnectorType || byID.State != created.State ||
+		byID.Visibility != created.Visibility || byID.Task != created.Task ||
+		byID.ConnectorDefinitionUID != created.ConnectorDefinitionUID {
+		t.Fatalf("get connector by id: got %+v, want %+v", byID, created)
+	}
+	if !equalJSON(byID.Configuration, created.Configuration) {
+		t.Fatalf("get connector by id: got configuration %s, want %s", byID.Configuration, created.Configuration)
+	}
+	if !byID.CreateTime.Round(time.Millisecond).Equal(created.CreateTime.Round(time.Millisecond)) {
+		t.Fatalf("get connector by id: got create time %s, want %s", byID.CreateTime, created.CreateTime)
+	}
+
+	byUID, err := r.GetConnectorByUID(ctx, created.UID, owner, true)
+	if err != nil {
+		t.Fatalf("get connector by uid: %v", err)
+	}
+	if byUID.ID != "conn" || len(byUID.Configuration) != 0 {
+		t.Fatalf("get connector by uid: expected the basic view of conn, got %+v", byUID)
+	}
+
+	if _, err := r.GetConnectorByUIDAdmin(ctx, created.UID, false); err != nil {
+		t.Fatalf("get connector by uid admin: %v", err)
+	}
+
+	_, err = r.GetConnectorByID(ctx, "missing", owner, false)
+	expectCode(t, err, codes.NotFound, "get missing connector by id")
+	_, err = r.GetConnectorByUID(ctx, uuid.Must(uuid.NewV4()), owner, false)
+	expectCode(t, err, codes.NotFound, "get missing connector by uid")
+}
+
+func testAlreadyExists(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	create(t, r, newConnector(owner, "conn"))
+	create(t, r, newConnector(owner, "other"))
+
+	expectCode(t, r.CreateConnector(ctx, newConnector(owner, "conn")), codes.AlreadyExists, "create duplicated connector")
+
+	// The id is unique per owner
+	create(t, r, newConnector(otherOwner, "conn"))
+
+	expectCode(t, r.UpdateConnectorID(ctx, "other", owner, "conn"), codes.AlreadyExists, "rename to a used id")
+	expectCode(t, r.UpdateConnector(ctx, "other", owner, &datamodel.Connector{ID: "conn"}), codes.AlreadyExists, "update to a used id")
+
+	if err := r.UpdateConnectorID(ctx, "other", owner, "renamed"); err != nil {
+		t.Fatalf("rename connector: %v", err)
+	}
+	if _, err := r.GetConnectorByID(ctx, "renamed", owner, true); err != nil {
+		t.Fatalf("get renamed connector: %v", err)
+	}
+	expectCode(t, r.UpdateConnectorID(ctx, "missing", owner, "new"), codes.NotFound, "rename missing connector")
+}
+
+func testSoftDelete(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	created := create(t, r, newConnector(owner, "conn"))
+
+	if err := r.DeleteConnector(ctx, "conn", owner); err != nil {
+		t.Fatalf("delete connector: %v", err)
+	}
+	_, err := r.GetConnectorByID(ctx, "conn", owner, false)
+	expectCode(t, err, codes.NotFound, "get deleted connector by id")
+	_, err = r.GetConnectorByUIDAdmin(ctx, created.UID, false)
+	expectCode(t, err, codes.NotFound, "get deleted connector by uid admin")
+	expectCode(t, r.DeleteConnector(ctx, "conn", owner), codes.NotFound, "delete deleted connector")
+	expectCode(t, r.UpdateConnectorStateByID(ctx, "conn", owner, datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED)), codes.NotFound, "update deleted connector state")
+	if got := listAll(t, r, owner, ""); len(got) != 0 {
+		t.Fatalf("list connectors: deleted connectors listed: %v", ids(got))
+	}
+
+	// The id of a deleted connector can be reused
+	recreated := create(t, r, newConnector(owner, "conn"))
+	if recreated.UID == created.UID {
+		t.Fatalf("recreate connector: uid %s reused", created.UID)
+	}
+	expectCode(t, r.DeleteConnector(ctx, "missing", owner), codes.NotFound, "delete missing connector")
+}
+
+func testVisibility(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	create(t, r, newConnector(owner, "mine"))
+	public := newConnector(otherOwner, "public")
+	public.Visibility = datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC)
+	create(t, r, public)
+	create(t, r, newConnector(otherOwner, "private"))
+
+	if got := fmt.Sprint(ids(listAll(t, r, owner, ""))); got != "[public mine]" {
+		t.Fatalf("list connectors: got %s, want [public mine]", got)
+	}
+	if _, err := r.GetConnectorByID(ctx, "public", owner, true); err != nil {
+		t.Fatalf("get public connector of another owner: %v", err)
+	}
+	_, err := r.GetConnectorByID(ctx, "private", owner, true)
+	expectCode(t, err, codes.NotFound, "get private connector of another owner")
+
+	connectors, totalSize, _, err := r.ListConnectorsAdmin(ctx, repository.MaxPageSize, "", true, filtering.Filter{})
+	if err != nil {
+		t.Fatalf("list connectors admin: %v", err)
+	}
+	if totalSize != 3 || len(connectors) != 3 {
+		t.Fatalf("list connectors admin: got %d connectors and a total size of %d, want 3", len(connectors), totalSize)
+	}
+
+	// The connector of the owner shadows a public connector with the same id
+	for _, ownerPermalink := range []string{owner, otherOwner} {
+		shadowed := newConnector(ownerPermalink, "shadowed")
+		if ownerPermalink == otherOwner {
+			shadowed.Visibility = datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC)
+		}
+		create(t, r, shadowed)
+	}
+	for _, ownerPermalink := range []string{owner, otherOwner} {
+		got, err := r.GetConnectorByID(ctx, "shadowed", ownerPermalink, true)
+		if err != nil {
+			t.Fatalf("get shadowed connector: %v", err)
+		}
+		if got.Owner != ownerPermalink {
+			t.Fatalf("get shadowed connector of %s: got the connector of %s", ownerPermalink, got.Owner)
+		}
+	}
+}
+
+func testPagination(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	const n = 25
+	for i := 0; i < n; i++ {
+		create(t, r, newConnector(owner, fmt.Sprintf("conn-%02d", i)))
+	}
+
+	connectors, totalSize, _, err := r.ListConnectors(ctx, owner, 0, "", true, filtering.Filter{})
+	if err != nil {
+		t.Fatalf("list connectors: %v", err)
+	}
+	if len(connectors) != repository.DefaultPageSize || totalSize != n {
+		t.Fatalf("list connectors: got %d connectors and a total size of %d, want %d and %d", len(connectors), totalSize, repository.DefaultPageSize, n)
+	}
+
+	seen := map[string]bool{}
+	var all []*datamodel.Connector
+	pageToken := ""
+	for page := 0; ; page++ {
+		connectors, totalSize, nextPageToken, err := r.ListConnectors(ctx, owner, 10, pageToken, false, filtering.Filter{})
+		if err != nil {
+			t.Fatalf("list connectors page %d: %v", page, err)
+		}
+		if totalSize != n {
+			t.Fatalf("list connectors page %d: got a total size of %d, want %d", page, totalSize, n)
+		}
+		for _, c := range connectors {
+			if seen[c.ID] {
+				t.Fatalf("list connectors page %d: %s listed twice", page, c.ID)
+			}
+			seen[c.ID] = true
+		}
+		all = append(all, connectors...)
+		if nextPageToken == "" {
+			if page != 2 || len(connectors) != 5 {
+				t.Fatalf("list connectors: ended on page %d of %d connectors, want page 2 of 5", page, len(connectors))
+			}
+			break
+		}
+		if page == 2 {
+			t.Fatalf("list connectors: page token after the last page")
+		}
+		pageToken = nextPageToken
+	}
+
+	// Ordered by create_time DESC, uid DESC
+	for i := 1; i < len(all); i++ {
+		prev, cur := all[i-1], all[i]
+		if cur.CreateTime.After(prev.CreateTime) || (cur.CreateTime.Equal(prev.CreateTime) && cur.UID.String() > prev.UID.String()) {
+			t.Fatalf("list connectors: %s listed after %s", cur.ID, prev.ID)
+		}
+	}
+	if len(all) != n {
+		t.Fatalf("list connectors: got %d connectors, want %d", len(all), n)
+	}
+
+	_, _, _, err = r.ListConnectors(ctx, owner, 10, "invalid", false, filtering.Filter{})
+	expectCode(t, err, codes.InvalidArgument, "list connectors with an invalid page token")
+}
+
+func testFilter(t *testing.T, r repository.Repository) {
+	add := func(ownerPermalink string, id string, connectorType connectorPB.ConnectorType, visibility connectorPB.Connector_Visibility) {
+		c := newConnector(ownerPermalink, id)
+		c.ConnectorType = datamodel.ConnectorType(connectorType)
+		c.Visibility = datamodel.ConnectorVisibility(visibility)
+		create(t, r, c)
+	}
+	add(owner, "ai", connectorPB.ConnectorType_CONNECTOR_TYPE_AI, connectorPB.Connector_VISIBILITY_PRIVATE)
+	add(owner, "source", connectorPB.ConnectorType_CONNECTOR_TYPE_SOURCE, connectorPB.Connector_VISIBILITY_PRIVATE)
+	add(owner, "destination", connectorPB.ConnectorType_CONNECTOR_TYPE_DESTINATION, connectorPB.Connector_VISIBILITY_PRIVATE)
+	add(otherOwner, "other-ai", connectorPB.ConnectorType_CONNECTOR_TYPE_AI, connectorPB.Connector_VISIBILITY_PRIVATE)
+	add(otherOwner, "other-source", connectorPB.ConnectorType_CONNECTOR_TYPE_SOURCE, connectorPB.Connector_VISIBILITY_PUBLIC)
+
+	time.Sleep(10 * time.Millisecond)
+	after := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	add(owner, "late", connectorPB.ConnectorType_CONNECTOR_TYPE_AI, connectorPB.Connector_VISIBILITY_PRIVATE)
+
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{"", "[late other-source destination source ai]"},
+		{"connector_type = CONNECTOR_TYPE_AI", "[late ai]"},
+		{"connector_type != CONNECTOR_TYPE_AI", "[other-source destination source]"},
+		{"connector_type = CONNECTOR_TYPE_AI OR connector_type = CONNECTOR_TYPE_SOURCE", "[late other-source source ai]"},
+		{"NOT connector_type = CONNECTOR_TYPE_AI", "[other-source destination source]"},
+		{`connector_type = CONNECTOR_TYPE_AI AND (id = "ai" OR id = "late")`, "[late ai]"},
+		{`id = "other-ai"`, "[]"},
+		{fmt.Sprintf("create_time > timestamp(%q)", after.Format(time.RFC3339Nano)), "[late]"},
+	}
+	for _, tt := range tests {
+		got := listAll(t, r, owner, tt.filter)
+		if fmt.Sprint(ids(got)) != tt.want {
+			t.Errorf("list connectors with filter %q: got %v, want %s", tt.filter, ids(got), tt.want)
+		}
+		_, totalSize, _, err := r.ListConnectors(context.Background(), owner, 1, "", true, parseFilter(t, tt.filter))
+		if err != nil {
+			t.Fatalf("list connectors with filter %q: %v", tt.filter, err)
+		}
+		if int(totalSize) != len(got) {
+			t.Errorf("list connectors with filter %q: got a total size of %d, want %d", tt.filter, totalSize, len(got))
+		}
+	}
+}
+
+func testUpdate(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	created := create(t, r, newConnector(owner, "conn"))
+
+	time.Sleep(10 * time.Millisecond)
+	if err := r.UpdateConnector(ctx, "conn", owner, &datamodel.Connector{
+		Description:   sql.NullString{String: "updated", Valid: true},
+		Configuration: []byte(`{"key": "updated"}`),
+	}); err != nil {
+		t.Fatalf("update connector: %v", err)
+	}
+	updated, err := r.GetConnectorByID(ctx, "conn", owner, false)
+	if err != nil {
+		t.Fatalf("get updated connector: %v", err)
+	}
+	if updated.Description.String != "updated" || !equalJSON(updated.Configuration, []byte(`{"key": "updated"}`)) {
+		t.Fatalf("update connector: got %+v", updated)
+	}
+	// Zero fields are left unchanged
+	if updated.State != created.State || updated.Visibility != created.Visibility || updated.ConnectorDefinitionUID != created.ConnectorDefinitionUID {
+		t.Fatalf("update connector: zero fields updated: %+v", updated)
+	}
+	if !updated.UpdateTime.After(created.UpdateTime) {
+		t.Fatalf("update connector: update time %s not after %s", updated.UpdateTime, created.UpdateTime)
+	}
+
+	connected := datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED)
+	if err := r.UpdateConnectorStateByID(ctx, "conn", owner, connected); err != nil {
+		t.Fatalf("update connector state by id: %v", err)
+	}
+	errored := datamodel.ConnectorState(connectorPB.Connector_STATE_ERROR)
+	if err := r.UpdateConnectorStateByUID(ctx, created.UID, owner, errored); err != nil {
+		t.Fatalf("update connector state by uid: %v", err)
+	}
+	if err := r.UpdateConnectorTaskByID(ctx, "conn", owner, ""); err != nil {
+		t.Fatalf("update connector task by id: %v", err)
+	}
+	updated, err = r.GetConnectorByUID(ctx, created.UID, owner, true)
+	if err != nil {
+		t.Fatalf("get updated connector: %v", err)
+	}
+	if updated.State != errored || updated.Task != "TASK_UNSPECIFIED" {
+		t.Fatalf("update connector state and task: got state %v and task %s", updated.State, updated.Task)
+	}
+
+	// The reason of an error state is kept while the connector is in the state
+	if err := r.UpdateConnectorStateErrorByUID(ctx, created.UID, owner, "incompatible configuration"); err != nil {
+		t.Fatalf("update connector state error by uid: %v", err)
+	}
+	if err := r.UpdateConnectorStateByUID(ctx, created.UID, owner, errored); err != nil {
+		t.Fatalf("update connector state by uid: %v", err)
+	}
+	updated, err = r.GetConnectorByID(ctx, "conn", owner, true)
+	if err != nil {
+		t.Fatalf("get errored connector: %v", err)
+	}
+	if updated.State != errored || updated.StateReason.String != "incompatible configuration" {
+		t.Fatalf("update connector state error: got state %v and reason %q", updated.State, updated.StateReason.String)
+	}
+	if err := r.UpdateConnectorStateByID(ctx, "conn", owner, connected); err != nil {
+		t.Fatalf("update connector state by id: %v", err)
+	}
+	updated, err = r.GetConnectorByID(ctx, "conn", owner, true)
+	if err != nil {
+		t.Fatalf("get connected connector: %v", err)
+	}
+	if updated.State != connected || updated.StateReason.Valid {
+		t.Fatalf("leave the error state: got state %v and reason %q", updated.State, updated.StateReason.String)
+	}
+
+	expectCode(t, r.UpdateConnector(ctx, "missing", owner, &datamodel.Connector{Task: "TASK_UNSPECIFIED"}), codes.NotFound, "update missing connector")
+	expectCode(t, r.UpdateConnectorStateErrorByUID(ctx, uuid.Must(uuid.NewV4()), owner, "reason"), codes.NotFound, "update missing connector state error by uid")
+	expectCode(t, r.UpdateConnectorStateByUID(ctx, uuid.Must(uuid.NewV4()), owner, connected), codes.NotFound, "update missing connector state by uid")
+	expectCode(t, r.UpdateConnectorTaskByID(ctx, "missing", owner, ""), codes.NotFound, "update missing connector task")
+}
+
+func testTransaction(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	create(t, r, newConnector(owner, "existing"))
+
+	errRollback := errors.New("rollback")
+	err := r.Transaction(ctx, func(tx repository.Repository) error {
+		create(t, tx, newConnector(owner, "rolled-back"))
+		if err := tx.DeleteConnector(ctx, "existing", owner); err != nil {
+			t.Fatalf("delete connector in transaction: %v", err)
+		}
+		if got := fmt.Sprint(ids(listAll(t, tx, owner, ""))); got != "[rolled-back]" {
+			t.Fatalf("list connectors in transaction: got %s, want [rolled-back]", got)
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("transaction: expected the error of fn, got %v", err)
+	}
+	if got := fmt.Sprint(ids(listAll(t, r, owner, ""))); got != "[existing]" {
+		t.Fatalf("list connectors after rollback: got %s, want [existing]", got)
+	}
+
+	if err := r.Transaction(ctx, func(tx repository.Repository) error {
+		create(t, tx, newConnector(owner, "committed"))
+		return nil
+	}); err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+	if got := fmt.Sprint(ids(listAll(t, r, owner, ""))); got != "[committed existing]" {
+		t.Fatalf("list connectors after commit: got %s, want [committed existing]", got)
+	}
+}
+
+func testDefinitionPolicy(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	connDefUID := uuid.Must(uuid.NewV4())
+
+	_, err := r.GetConnectorDefinitionPolicy(ctx, connDefUID)
+	expectCode(t, err, codes.NotFound, "get missing connector definition policy")
+
+	if err := r.UpsertConnectorDefinitionPolicy(ctx, &datamodel.ConnectorDefinitionPolicy{
+		ConnectorDefinitionUID: connDefUID,
+		ConnectorDefinitionID:  "def",
+		State:                  datamodel.DefinitionPolicyDeprecated,
+		Message:                "use another definition",
+	}); err != nil {
+		t.Fatalf("create connector definition policy: %v", err)
+	}
+	created, err := r.GetConnectorDefinitionPolicy(ctx, connDefUID)
+	if err != nil {
+		t.Fatalf("get connector definition policy: %v", err)
+	}
+	if created.State != datamodel.DefinitionPolicyDeprecated || created.Message != "use another definition" {
+		t.Fatalf("get connector definition policy: got %+v", created)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := r.UpsertConnectorDefinitionPolicy(ctx, &datamodel.ConnectorDefinitionPolicy{
+		ConnectorDefinitionUID: connDefUID,
+		ConnectorDefinitionID:  "def",
+		State:                  datamodel.DefinitionPolicyHidden,
+	}); err != nil {
+		t.Fatalf("update connector definition policy: %v", err)
+	}
+	policies, err := r.ListConnectorDefinitionPolicies(ctx)
+	if err != nil {
+		t.Fatalf("list connector definition policies: %v", err)
+	}
+	if len(policies) != 1 || policies[0].State != datamodel.DefinitionPolicyHidden || policies[0].Message != "" {
+		t.Fatalf("list connector definition policies: got %+v", policies)
+	}
+	if !policies[0].CreateTime.Equal(created.CreateTime) || !policies[0].UpdateTime.After(created.UpdateTime) {
+		t.Fatalf("update connector definition policy: create time %s and update time %s, was %s and %s",
+			policies[0].CreateTime, policies[0].UpdateTime, created.CreateTime, created.UpdateTime)
+	}
+}
+
+func testBuiltinConnectorDefinition(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+
+	first := &datamodel.BuiltinConnectorDefinition{
+		UID:  uuid.Must(uuid.NewV4()),
+		ID:   "first",
+		Spec: []byte(`{"id": "first"}`),
+	}
+	second := &datamodel.BuiltinConnectorDefinition{
+		UID:  uuid.Must(uuid.NewV4()),
+		ID:   "second",
+		Spec: []byte(`{"id": "second"}`),
+	}
+	for _, def := range []*datamodel.BuiltinConnectorDefinition{first, second} {
+		if err := r.UpsertBuiltinConnectorDefinition(ctx, def); err != nil {
+			t.Fatalf("create builtin connector definition %s: %v", def.ID, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := r.UpsertBuiltinConnectorDefinition(ctx, &datamodel.BuiltinConnectorDefinition{
+		UID:  first.UID,
+		ID:   "first",
+		Spec: []byte(`{"id": "first", "title": "First"}`),
+	}); err != nil {
+		t.Fatalf("update builtin connector definition: %v", err)
+	}
+	defs, err := r.ListBuiltinConnectorDefinitions(ctx)
+	if err != nil {
+		t.Fatalf("list builtin connector definitions: %v", err)
+	}
+	if len(defs) != 2 || defs[0].ID != "first" || defs[1].ID != "second" {
+		t.Fatalf("list builtin connector definitions: got %+v", defs)
+	}
+	if !equalJSON(defs[0].Spec, []byte(`{"id": "first", "title": "First"}`)) || !defs[0].UpdateTime.After(defs[0].CreateTime) {
+		t.Fatalf("update builtin connector definition: got %+v", defs[0])
+	}
+
+	connector := newConnector(owner, "builtin")
+	connector.ConnectorDefinitionUID = first.UID
+	create(t, r, connector)
+	for _, c := range []struct {
+		uid   uuid.UUID
+		count int64
+	}{{first.UID, 1}, {second.UID, 0}} {
+		count, err := r.CountConnectorsByDefinitionUID(ctx, c.uid)
+		if err != nil {
+			t.Fatalf("count connectors by definition uid: %v", err)
+		}
+		if count != c.count {
+			t.Fatalf("count connectors by definition uid: got %d, want %d", count, c.count)
+		}
+	}
+
+	if err := r.DeleteBuiltinConnectorDefinition(ctx, second.UID); err != nil {
+		t.Fatalf("delete builtin connector definition: %v", err)
+	}
+	expectCode(t, r.DeleteBuiltinConnectorDefinition(ctx, second.UID), codes.NotFound, "delete missing builtin connector definition")
+	if defs, err := r.ListBuiltinConnectorDefinitions(ctx); err != nil || len(defs) != 1 {
+		t.Fatalf("list builtin connector definitions after delete: got %+v, %v", defs, err)
+	}
+}
+
+func testConnectorUsage(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	connDefUID := uuid.Must(uuid.NewV4())
+	for idx, c := range []struct {
+		owner string
+		state connectorPB.Connector_State
+	}{
+		{owner, connectorPB.Connector_STATE_CONNECTED},
+		{owner, connectorPB.Connector_STATE_CONNECTED},
+		{owner, connectorPB.Connector_STATE_ERROR},
+		{otherOwner, connectorPB.Connector_STATE_CONNECTED},
+	} {
+		connector := newConnector(c.owner, fmt.Sprintf("conn-%d", idx))
+		connector.ConnectorDefinitionUID = connDefUID
+		connector.State = datamodel.ConnectorState(c.state)
+		create(t, r, connector)
+	}
+	if err := r.DeleteConnector(ctx, "conn-0", owner); err != nil {
+		t.Fatalf("delete connector: %v", err)
+	}
+
+	counts, err := r.AggregateConnectorUsage(ctx)
+	if err != nil {
+		t.Fatalf("aggregate connector usage: %v", err)
+	}
+	got := map[string]int64{}
+	for _, count := range counts {
+		if count.ConnectorDefinitionUID != connDefUID ||
+			count.ConnectorType != datamodel.ConnectorType(connectorPB.ConnectorType_CONNECTOR_TYPE_DESTINATION) ||
+			count.Visibility != datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PRIVATE) {
+			t.Fatalf("aggregate connector usage: unexpected group %+v", count)
+		}
+		got[fmt.Sprintf("%s %s", count.Owner, connectorPB.Connector_State(count.State))] = count.Count
+	}
+	want := map[string]int64{
+		owner + " STATE_CONNECTED":      1,
+		owner + " STATE_ERROR":          1,
+		otherOwner + " STATE_CONNECTED": 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("aggregate connector usage: got %v, want %v", got, want)
+	}
+}
+
+func testExecutionMetering(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	connUID := uuid.Must(uuid.NewV4())
+	connDefUID := uuid.Must(uuid.NewV4())
+	window := time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	for _, m := range []*datamodel.ExecutionMetering{
+		{Owner: owner, ConnectorUID: connUID, ConnectorDefinitionUID: connDefUID, WindowStart: window.Add(-time.Hour), Executions: 1, InputItems: 1, PayloadBytes: 10},
+		{Owner: owner, ConnectorUID: connUID, ConnectorDefinitionUID: connDefUID, WindowStart: window, Executions: 1, InputItems: 2, PayloadBytes: 20},
+		{Owner: owner, ConnectorUID: connUID, ConnectorDefinitionUID: connDefUID, WindowStart: window, Executions: 1, InputItems: 3, PayloadBytes: 30},
+		{Owner: otherOwner, ConnectorUID: connUID, ConnectorDefinitionUID: connDefUID, WindowStart: window, Executions: 1, InputItems: 1, PayloadBytes: 10},
+	} {
+		if err := r.IncrementExecutionMetering(ctx, m); err != nil {
+			t.Fatalf("increment execution metering: %v", err)
+		}
+	}
+
+	meterings, err := r.ListExecutionMetering(ctx, owner, window)
+	if err != nil {
+		t.Fatalf("list execution metering: %v", err)
+	}
+	if len(meterings) != 1 {
+		t.Fatalf("list execution metering: got %d windows, want 1", len(meterings))
+	}
+	if m := meterings[0]; !m.WindowStart.Equal(window) || m.ConnectorUID != connUID || m.ConnectorDefinitionUID != connDefUID ||
+		m.Executions != 2 || m.InputItems != 5 || m.PayloadBytes != 50 {
+		t.Fatalf("list execution metering: got %+v", m)
+	}
+	if meterings, err := r.ListExecutionMetering(ctx, owner, window.Add(-time.Hour)); err != nil || len(meterings) != 2 {
+		t.Fatalf("list execution metering since the previous window: got %+v, %v", meterings, err)
+	}
+}
+
+func testAuditEvents(t *testing.T, r repository.Repository) {
+	ctx := context.Background()
+	connUID := uuid.Must(uuid.NewV4())
+	start := time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		event := &datamodel.AuditEvent{
+			CreateTime: start.Add(time.Duration(i) * time.Minute),
+			EventName:  "UpdateConnector",
+			Actor:      owner,
+			Owner:      owner,
+			Outcome:    datamodel.AuditOutcomeSuccess,
+			Diff:       []byte(`{"description": {"before": "a", "after": "b"}}`),
+		}
+		if i%2 == 0 {
+			event.ConnectorUID = uuid.NullUUID{UUID: connUID, Valid: true}
+		}
+		if i == 4 {
+			event.Actor = otherOwner
+		}
+		if err := r.CreateAuditEvent(ctx, event); err != nil {
+			t.Fatalf("create audit event: %v", err)
+		}
+		if event.UID == uuid.Nil {
+			t.Fatalf("create audit event: the uid is not set")
+		}
+	}
+
+	// Pages from the newest event
+	var times []time.Time
+	pageToken := ""
+	for {
+		events, nextPageToken, err := r.ListAuditEvents(ctx, repository.AuditEventFilter{}, 2, pageToken)
+		if err != nil {
+			t.Fatalf("list audit events: %v", err)
+		}
+		for _, e := range events {
+			times = append(times, e.CreateTime.UTC())
+		}
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+	if len(times) != 5 || !times[0].Equal(start.Add(4*time.Minute)) || !times[4].Equal(start) {
+		t.Fatalf("list audit events: got %v", times)
+	}
+
+	for _, tt := range []struct {
+		filter repository.AuditEventFilter
+		want   int
+	}{
+		{repository.AuditEventFilter{Actor: owner}, 4},
+		{repository.AuditEventFilter{ConnectorUID: connUID}, 3},
+		{repository.AuditEventFilter{StartTime: start.Add(time.Minute), EndTime: start.Add(3 * time.Minute)}, 2},
+		{repository.AuditEventFilter{Actor: otherOwner, ConnectorUID: connUID}, 1},
+	} {
+		events, _, err := r.ListAuditEvents(ctx, tt.filter, repository.MaxPageSize, "")
+		if err != nil {
+			t.Fatalf("list audit events with %+v: %v", tt.filter, err)
+		}
+		if len(events) != tt.want {
+			t.Fatalf("list audit events with %+v: got %d events, want %d", tt.filter, len(events), tt.want)
+		}
+	}
+
+	if _, _, err := r.ListAuditEvents(ctx, repository.AuditEventFilter{}, 1, "invalid"); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("list audit events with an invalid page token: got %v, want InvalidArgument", err)
+	}
+}
+
+// BenchmarkConnectorUsage compares counting the connectors of every owner
+// with a listing per owner and connector type, as the usage reporter did,
+// against the aggregate query
+func BenchmarkConnectorUsage(b *testing.B, newRepository func(b *testing.B) repository.Repository) {
+	const owners = 200
+	const connectorsPerOwner = 10
+
+	r := newRepository(b)
+	ctx := context.Background()
+	connTypes := []connectorPB.ConnectorType{
+		connectorPB.ConnectorType_CONNECTOR_TYPE_SOURCE,
+		connectorPB.ConnectorType_CONNECTOR_TYPE_DESTINATION,
+		connectorPB.ConnectorType_CONNECTOR_TYPE_AI,
+	}
+	ownerPermalinks := make([]string, owners)
+	for o := range ownerPermalinks {
+		ownerPermalinks[o] = fmt.Sprintf("users/%s", uuid.Must(uuid.NewV4()))
+		for c := 0; c < connectorsPerOwner; c++ {
+			connector := newConnector(ownerPermalinks[o], fmt.Sprintf("conn-%d", c))
+			connector.ConnectorType = datamodel.ConnectorType(connTypes[c%len(connTypes)])
+			if c%2 == 0 {
+				connector.State = datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED)
+			}
+			create(b, r, connector)
+		}
+	}
+	filters := []filtering.Filter{
+		parseFilter(b, "connector_type=CONNECTOR_TYPE_SOURCE"),
+		parseFilter(b, "connector_type=CONNECTOR_TYPE_DESTINATION"),
+	}
+	b.ResetTimer()
+
+	b.Run("ListConnectorsPerOwner", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, ownerPermalink := range ownerPermalinks {
+				for _, filter := range filters {
+					pageToken := ""
+					for {
+						_, _, nextPageToken, err := r.ListConnectors(ctx, ownerPermalink, repository.MaxPageSize, pageToken, true, filter)
+						if err != nil {
+							b.Fatalf("list connectors: %v", err)
+						}
+						if nextPageToken == "" {
+							break
+						}
+						pageToken = nextPageToken
+					}
+				}
+			}
+		}
+	})
+
+	b.Run("AggregateConnectorUsage", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := r.AggregateConnectorUsage(ctx); err != nil {
+				b.Fatalf("aggregate connector usage: %v", err)
+			}
+		}
+	})
+}
@@ -150,7 +150,8 @@ func (t *Transpiler) transpileNotCallExpr(e *expr.Expr) (*clause.Expr, error) {
 		return nil, err
 	}
 	return &clause.Expr{
-		SQL:                fmt.Sprintf("NOT %s", rhsExpr.SQL),
+		SQL:                fmt.Sprintf("NOT (%s)", rhsExpr.SQL),
+		Vars:               rhsExpr.Vars,
 		WithoutParentheses: true,
 	}, nil
 }
@@ -226,14 +227,14 @@ func (t *Transpiler) transpileBinaryLogicalCallExpr(e *expr.Expr, op clause.Expr
 	var sql string
 	switch op.(type) {
 	case clause.AndConditions:
-		sql = fmt.Sprintf("%s AND %s", lhsExpr.SQL, rhsExpr.SQL)
+		sql = fmt.Sprintf("(%s) AND (%s)", lhsExpr.SQL, rhsExpr.SQL)
 	case clause.OrConditions:
-		sql = fmt.Sprintf("%s OR %s", lhsExpr.SQL, rhsExpr.SQL)
+		sql = fmt.Sprintf("(%s) OR (%s)", lhsExpr.SQL, rhsExpr.SQL)
 	}
 
 	return &clause.Expr{
 		SQL:                sql,
-		Vars:               append(lhsExpr.Vars, rhsExpr.Vars),
+		Vars:               append(lhsExpr.Vars, rhsExpr.Vars...),
 		WithoutParentheses: true,
 	}, nil
 }
@@ -350,7 +351,7 @@ func (t *Transpiler) transpileTimestampCallExpr(e *expr.Expr) (*clause.Expr, err
 		return nil, fmt.Errorf("invalid string arg to %s: %w", callExpr.Function, err)
 	}
 	return &clause.Expr{
-		Vars:               []interface{}{timeArg},
+		Vars:               []interface{}{timeArg.UTC()},
 		WithoutParentheses: true,
 	}, nil
 }
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/instill-ai/connector-backend/pkg/audit"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/x/sterr"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+)
+
+// ListAuditEvents returns a page of the audit events matching filter, from
+// the newest. Only the admins can list them, and only if they are stored.
+func (s *service) ListAuditEvents(ctx context.Context, owner *mgmtPB.User, filter repository.AuditEventFilter, pageSize int64, pageToken string) ([]*datamodel.AuditEvent, string, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if !IsAdmin(owner) {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.PermissionDenied,
+			"[service] list audit events",
+			"audit-events",
+			"",
+			GenOwnerPermalink(owner),
+			"Permission denied",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, "", st.Err()
+	}
+
+	if !audit.IsStored() {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.FailedPrecondition,
+			"[service] list audit events",
+			"audit-events",
+			"",
+			GenOwnerPermalink(owner),
+			"The audit events are not stored in the database",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, "", st.Err()
+	}
+
+	return s.repository.ListAuditEvents(ctx, filter, pageSize, pageToken)
+}
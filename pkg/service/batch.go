@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// BatchResult is the outcome of a single item of a batch operation
+type BatchResult struct {
+	Connector *datamodel.Connector
+	Err       error
+}
+
+// BatchItemError prefixes err with the index of the batch item while keeping its status code
+func BatchItemError(idx int, err error) error {
+	st := status.Convert(err)
+	return status.Errorf(st.Code(), "[service] batch item %d: %s", idx, st.Message())
+}
+
+// firstBatchError returns the first failed item of a batch, if any
+func firstBatchError(results []*BatchResult) error {
+	for idx, result := range results {
+		if result.Err != nil {
+			return BatchItemError(idx, result.Err)
+		}
+	}
+	return nil
+}
+
+// checkBatchIDs marks every repeated connector id of a batch as invalid
+func checkBatchIDs(ids []string, results []*BatchResult) {
+	seen := make(map[string]bool, len(ids))
+	for idx, id := range ids {
+		if seen[id] {
+			results[idx].Err = status.Errorf(codes.InvalidArgument, "[service] batch: connector id %s is repeated", id)
+			continue
+		}
+		seen[id] = true
+	}
+}
+
+func newBatchResults(n int) []*BatchResult {
+	results := make([]*BatchResult, n)
+	for idx := range results {
+		results[idx] = &BatchResult{}
+	}
+	return results
+}
+
+func (s *service) BatchCreateConnectors(ctx context.Context, owner *mgmtPB.User, connectors []*datamodel.Connector, allOrNothing bool) ([]*BatchResult, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ownerPermalink := GenOwnerPermalink(owner)
+
+	results := newBatchResults(len(connectors))
+	ids := make([]string, len(connectors))
+	for idx, connector := range connectors {
+		ids[idx] = connector.ID
+	}
+	checkBatchIDs(ids, results)
+
+	// Validate all items up front
+	states := make([]connectorPB.Connector_State, len(connectors))
+	for idx, connector := range connectors {
+		if results[idx].Err != nil {
+			continue
+		}
+		connector.Owner = ownerPermalink
+		connDef, err := s.validateCreateConnector(ctx, owner, connector)
+		if err != nil {
+			results[idx].Err = err
+			continue
+		}
+		states[idx] = initialConnectorState(connDef)
+	}
+
+	// The connectors are created with their resource state in a transaction,
+	// so that the items reported as failed are never stored
+	if allOrNothing {
+		if err := firstBatchError(results); err != nil {
+			return nil, err
+		}
+		created := 0
+		if err := s.repository.Transaction(ctx, func(r repository.Repository) error {
+			for idx, connector := range connectors {
+				dbConnector, err := s.createConnectorWithState(ctx, r, connector, states[idx])
+				if err != nil {
+					return BatchItemError(idx, err)
+				}
+				results[idx].Connector = dbConnector
+				created++
+			}
+			return nil
+		}); err != nil {
+			// The connectors are rolled back, so a controller failure only
+			// leaves a stale resource state behind
+			for _, connector := range connectors[:created] {
+				if err := s.DeleteResourceState(ctx, connector.UID); err != nil {
+					logger.Warn(fmt.Sprintf("delete resource state of connector %s: %s", connector.UID, err.Error()))
+				}
+			}
+			return nil, err
+		}
+		return results, nil
+	}
+
+	for idx, connector := range connectors {
+		if results[idx].Err != nil {
+			continue
+		}
+		results[idx].Err = s.repository.Transaction(ctx, func(r repository.Repository) error {
+			var err error
+			results[idx].Connector, err = s.createConnectorWithState(ctx, r, connector, states[idx])
+			return err
+		})
+	}
+
+	return results, nil
+}
+
+func (s *service) BatchDeleteConnectors(ctx context.Context, owner *mgmtPB.User, ids []string, allOrNothing bool) ([]*BatchResult, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ownerPermalink := GenOwnerPermalink(owner)
+
+	results := newBatchResults(len(ids))
+	checkBatchIDs(ids, results)
+
+	// Validate all items up front
+	for idx, id := range ids {
+		if results[idx].Err != nil {
+			continue
+		}
+		dbConnector, err := s.repository.GetConnectorByID(ctx, id, ownerPermalink, true)
+		if err != nil {
+			results[idx].Err = err
+			continue
+		}
+		if err := s.checkConnectorNotInUse(ctx, owner, dbConnector); err != nil {
+			results[idx].Err = err
+			continue
+		}
+		results[idx].Connector = dbConnector
+	}
+
+	if allOrNothing {
+		if err := firstBatchError(results); err != nil {
+			return nil, err
+		}
+		if err := s.repository.Transaction(ctx, func(r repository.Repository) error {
+			for idx, id := range ids {
+				if err := r.DeleteConnector(ctx, id, ownerPermalink); err != nil {
+					return BatchItemError(idx, err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		// The connectors are gone once the transaction is committed, so a
+		// controller failure only leaves a stale resource state behind
+		for _, result := range results {
+			if err := s.DeleteResourceState(ctx, result.Connector.UID); err != nil {
+				logger.Warn(fmt.Sprintf("delete resource state of connector %s: %s", result.Connector.UID, err.Error()))
+			}
+		}
+		return results, nil
+	}
+
+	for idx, id := range ids {
+		if results[idx].Err != nil {
+			continue
+		}
+		if err := s.DeleteResourceState(ctx, results[idx].Connector.UID); err != nil {
+			results[idx].Err = err
+			continue
+		}
+		results[idx].Err = s.repository.DeleteConnector(ctx, id, ownerPermalink)
+	}
+
+	return results, nil
+}
+
+func (s *service) BatchUpdateConnectorState(ctx context.Context, owner *mgmtPB.User, ids []string, state datamodel.ConnectorState, allOrNothing bool) ([]*BatchResult, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ownerPermalink := GenOwnerPermalink(owner)
+
+	results := newBatchResults(len(ids))
+	checkBatchIDs(ids, results)
+
+	// Validate all items up front
+	changes := make([]*connectorStateChange, len(ids))
+	for idx, id := range ids {
+		if results[idx].Err != nil {
+			continue
+		}
+		conn, err := s.repository.GetConnectorByID(ctx, id, ownerPermalink, false)
+		if err != nil {
+			results[idx].Err = err
+			continue
+		}
+		if state == datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED) {
+			if connState, _ := s.CheckConnectorByUID(ctx, conn.UID); *connState != connectorPB.Connector_STATE_CONNECTED {
+				results[idx].Err = status.Errorf(codes.InvalidArgument, "[service] connect connector error: connection test of %s returned %s", id, connState)
+				continue
+			}
+		}
+		changes[idx], results[idx].Err = s.prepareConnectorState(ctx, ownerPermalink, conn, state)
+	}
+
+	// The states are stored and sent to the controller in a transaction, so
+	// that the items reported as failed are never changed
+	update := func(r repository.Repository, idx int) error {
+		if err := applyConnectorState(ctx, r, changes[idx]); err != nil {
+			return err
+		}
+		if err := s.applyResourceState(ctx, changes[idx]); err != nil {
+			return err
+		}
+		var err error
+		results[idx].Connector, err = r.GetConnectorByID(ctx, ids[idx], ownerPermalink, false)
+		return err
+	}
+
+	if allOrNothing {
+		if err := firstBatchError(results); err != nil {
+			return nil, err
+		}
+		updated := 0
+		if err := s.repository.Transaction(ctx, func(r repository.Repository) error {
+			for idx := range changes {
+				if err := update(r, idx); err != nil {
+					return BatchItemError(idx, err)
+				}
+				updated++
+			}
+			return nil
+		}); err != nil {
+			// The states are rolled back, the controller is sent them back
+			for _, change := range changes[:updated] {
+				if change.noop {
+					continue
+				}
+				if err := s.UpdateResourceState(ctx, change.conn.UID, connectorPB.Connector_State(change.conn.State), nil); err != nil {
+					logger.Warn(fmt.Sprintf("restore resource state of connector %s: %s", change.conn.UID, err.Error()))
+				}
+			}
+			return nil, err
+		}
+		return results, nil
+	}
+
+	for idx := range changes {
+		if results[idx].Err != nil {
+			continue
+		}
+		results[idx].Err = s.repository.Transaction(ctx, func(r repository.Repository) error {
+			return update(r, idx)
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	"github.com/instill-ai/connector-backend/pkg/connector/builtin"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/x/sterr"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// LoadBuiltinConnectorDefinitions registers the stored builtin connector
+// definitions and unregisters the ones which are no longer stored, so that
+// the registry follows the changes made through the other replicas. A stored
+// spec which no longer compiles is skipped with an error log, so that the
+// other definitions are still served.
+func (s *service) LoadBuiltinConnectorDefinitions(ctx context.Context) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	dbDefs, err := s.repository.ListBuiltinConnectorDefinitions(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := map[string]bool{}
+	for _, dbDef := range dbDefs {
+		stored[dbDef.ID] = true
+		spec := &builtin.Spec{}
+		if err := json.Unmarshal(dbDef.Spec, spec); err != nil {
+			logger.Error(fmt.Sprintf("builtin connector definition %s: %s", dbDef.ID, err.Error()))
+			continue
+		}
+		if current, err := s.builtinConnector.GetSpec(spec.ID); err == nil && reflect.DeepEqual(current, spec) {
+			continue
+		}
+		if _, err := s.builtinConnector.Register(spec); err != nil {
+			logger.Error(fmt.Sprintf("builtin connector definition %s: %s", dbDef.ID, err.Error()))
+			continue
+		}
+		logger.Info(fmt.Sprintf("builtin connector definition %s loaded", spec.ID))
+	}
+
+	for _, spec := range s.builtinConnector.ListSpecs() {
+		if stored[spec.ID] {
+			continue
+		}
+		if err := s.builtinConnector.Unregister(spec.ID); err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("builtin connector definition %s unloaded", spec.ID))
+	}
+
+	return nil
+}
+
+// WatchBuiltinConnectorDefinitions reloads the builtin connector definitions
+// at every interval until ctx is cancelled
+func (s *service) WatchBuiltinConnectorDefinitions(ctx context.Context, interval time.Duration) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+			if err := s.LoadBuiltinConnectorDefinitions(ctx); err != nil {
+				logger.Error(fmt.Sprintf("load builtin connector definitions: %s", err.Error()))
+			}
+		}
+	}
+}
+
+func (s *service) ListBuiltinConnectorDefinitions(ctx context.Context, owner *mgmtPB.User) ([]*builtin.Spec, error) {
+
+	if err := s.checkBuiltinAdmin(ctx, owner, "list", ""); err != nil {
+		return nil, err
+	}
+
+	if err := s.LoadBuiltinConnectorDefinitions(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.builtinConnector.ListSpecs(), nil
+}
+
+// RegisterBuiltinConnectorDefinition stores and registers the spec, which
+// replaces the builtin definition with the same id, if any
+func (s *service) RegisterBuiltinConnectorDefinition(ctx context.Context, owner *mgmtPB.User, spec *builtin.Spec) (*connectorPB.ConnectorDefinition, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if err := s.checkBuiltinAdmin(ctx, owner, "register", spec.ID); err != nil {
+		return nil, err
+	}
+
+	uid := spec.UID()
+	if _, err := s.connectorAll.GetConnectorDefinitionById(spec.ID); err == nil && !s.builtinConnector.HasUid(uid) {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.AlreadyExists,
+			"[service] register builtin connector definition",
+			"connector-definitions",
+			fmt.Sprintf("id %s", spec.ID),
+			GenOwnerPermalink(owner),
+			"The id is used by a compiled-in connector definition",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	// The spec is stored before it is registered, so that a concurrent reload
+	// does not unregister it
+	if err := spec.Validate(); err != nil {
+		return nil, builtinSpecError(ctx, err)
+	}
+
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.UpsertBuiltinConnectorDefinition(ctx, &datamodel.BuiltinConnectorDefinition{
+		UID:  uid,
+		ID:   spec.ID,
+		Spec: b,
+	}); err != nil {
+		return nil, err
+	}
+
+	connDef, err := s.builtinConnector.Register(spec)
+	if err != nil {
+		return nil, builtinSpecError(ctx, err)
+	}
+
+	logger.Info(fmt.Sprintf("builtin connector definition %s registered by %s", spec.ID, owner.GetId()))
+
+	return connDef, nil
+}
+
+// DeleteBuiltinConnectorDefinition removes a builtin definition which has no
+// connector left
+func (s *service) DeleteBuiltinConnectorDefinition(ctx context.Context, owner *mgmtPB.User, id string) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if err := s.checkBuiltinAdmin(ctx, owner, "delete", id); err != nil {
+		return err
+	}
+
+	if err := s.LoadBuiltinConnectorDefinitions(ctx); err != nil {
+		return err
+	}
+
+	spec, err := s.builtinConnector.GetSpec(id)
+	if err != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			"[service] delete builtin connector definition",
+			"connector-definitions",
+			fmt.Sprintf("id %s", id),
+			GenOwnerPermalink(owner),
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	if err := s.repository.Transaction(ctx, func(r repository.Repository) error {
+		count, err := r.CountConnectorsByDefinitionUID(ctx, spec.UID())
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			st, err := sterr.CreateErrorPreconditionFailure(
+				"[service] delete builtin connector definition",
+				[]*errdetails.PreconditionFailure_Violation{
+					{
+						Type:        "DELETE",
+						Subject:     fmt.Sprintf("connector-definitions/%s", id),
+						Description: fmt.Sprintf("The connector definition is used by %d connectors", count),
+					},
+				})
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return st.Err()
+		}
+		return r.DeleteBuiltinConnectorDefinition(ctx, spec.UID())
+	}); err != nil {
+		return err
+	}
+
+	// The definition may already be unloaded by a concurrent reload
+	_ = s.builtinConnector.Unregister(id)
+
+	logger.Info(fmt.Sprintf("builtin connector definition %s deleted by %s", id, owner.GetId()))
+
+	return nil
+}
+
+func (s *service) checkBuiltinAdmin(ctx context.Context, owner *mgmtPB.User, operation string, id string) error {
+
+	if IsAdmin(owner) {
+		return nil
+	}
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	st, err := sterr.CreateErrorResourceInfo(
+		codes.PermissionDenied,
+		fmt.Sprintf("[service] %s builtin connector definition", operation),
+		"connector-definitions",
+		fmt.Sprintf("id %s", id),
+		GenOwnerPermalink(owner),
+		"Permission denied",
+	)
+	if err != nil {
+		logger.Error(err.Error())
+	}
+	return st.Err()
+}
+
+// builtinSpecError turns a spec validation error into a bad request
+func builtinSpecError(ctx context.Context, err error) error {
+
+	var specErr *builtin.SpecError
+	if !errors.As(err, &specErr) {
+		return err
+	}
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	st, e := sterr.CreateErrorBadRequest(
+		"[service] register builtin connector definition",
+		[]*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       specErr.Field,
+				Description: specErr.Description,
+			},
+		},
+	)
+	if e != nil {
+		logger.Error(e.Error())
+	}
+	return st.Err()
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/instill-ai/connector-backend/pkg/connector/builtin"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+)
+
+func TestLoadBuiltinConnectorDefinitions(t *testing.T) {
+	ctx := context.Background()
+	r := repository.NewMemoryRepository()
+	s := &service{repository: r, builtinConnector: builtin.Init(zap.NewNop())}
+
+	spec := &builtin.Spec{
+		ID:                      "builtin-reload",
+		Title:                   "Reload",
+		Task:                    "TASK_UNSPECIFIED",
+		ConnectionSpecification: map[string]interface{}{"type": "object"},
+		Request:                 builtin.Request{URL: "http://localhost/{{.config.id}}"},
+	}
+	store := func(spec *builtin.Spec) {
+		t.Helper()
+		b, err := json.Marshal(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.UpsertBuiltinConnectorDefinition(ctx, &datamodel.BuiltinConnectorDefinition{UID: spec.UID(), ID: spec.ID, Spec: b}); err != nil {
+			t.Fatalf("store builtin connector definition: %v", err)
+		}
+	}
+
+	// Stored through another replica
+	store(spec)
+	if err := s.LoadBuiltinConnectorDefinitions(ctx); err != nil {
+		t.Fatalf("load builtin connector definitions: %v", err)
+	}
+	if _, err := s.builtinConnector.GetConnectorDefinitionById(spec.ID); err != nil {
+		t.Fatalf("created builtin connector definition: %v", err)
+	}
+
+	updated := *spec
+	updated.Title = "Reloaded"
+	store(&updated)
+	if err := s.LoadBuiltinConnectorDefinitions(ctx); err != nil {
+		t.Fatalf("load builtin connector definitions: %v", err)
+	}
+	if connDef, err := s.builtinConnector.GetConnectorDefinitionById(spec.ID); err != nil || connDef.GetTitle() != "Reloaded" {
+		t.Fatalf("updated builtin connector definition: got %v, %v", connDef, err)
+	}
+
+	if err := r.DeleteBuiltinConnectorDefinition(ctx, spec.UID()); err != nil {
+		t.Fatalf("delete builtin connector definition: %v", err)
+	}
+	if err := s.LoadBuiltinConnectorDefinitions(ctx); err != nil {
+		t.Fatalf("load builtin connector definitions: %v", err)
+	}
+	if s.builtinConnector.HasUid(spec.UID()) {
+		t.Fatalf("deleted builtin connector definition is still registered")
+	}
+}
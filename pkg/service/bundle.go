@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/pkg/bundle"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/x/checkfield"
+	"github.com/instill-ai/x/sterr"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// ImportResult is the outcome of importing a bundle entry
+type ImportResult struct {
+	// ID is the connector ID in the bundle
+	ID string
+	// ConnectorID is the ID of the imported connector, which differs from ID when renamed
+	ConnectorID string
+	Action      bundle.Action
+	// Conflict is true if a connector with the same ID already exists
+	Conflict  bool
+	Connector *datamodel.Connector
+	Err       error
+}
+
+// importEntry is a validated bundle entry ready to be written
+type importEntry struct {
+	connDef       *connectorPB.ConnectorDefinition
+	configuration []byte
+	visibility    datamodel.ConnectorVisibility
+}
+
+func (s *service) ExportConnectors(ctx context.Context, owner *mgmtPB.User, credentials bundle.CredentialMode) (*bundle.Bundle, error) {
+
+	dbConnectors, err := s.listOwnedConnectors(ctx, GenOwnerPermalink(owner))
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bundle.Bundle{
+		Version:    bundle.Version,
+		Connectors: make([]*bundle.Connector, 0, len(dbConnectors)),
+	}
+
+	for _, dbConnector := range dbConnectors {
+		connDef, err := s.connectorAll.GetConnectorDefinitionByUid(dbConnector.ConnectorDefinitionUID)
+		if err != nil {
+			return nil, err
+		}
+
+		configuration := map[string]interface{}{}
+		if dbConnector.Configuration != nil {
+			if err := json.Unmarshal(dbConnector.Configuration, &configuration); err != nil {
+				return nil, err
+			}
+		}
+		s.exportCredentialFields(connDef.GetId(), dbConnector.ID, configuration, "", credentials)
+
+		b.Connectors = append(b.Connectors, &bundle.Connector{
+			ConnectorDefinition: connDef.GetId(),
+			ID:                  dbConnector.ID,
+			Description:         dbConnector.Description.String,
+			Visibility:          connectorPB.Connector_Visibility(dbConnector.Visibility).String(),
+			State:               connectorPB.Connector_State(dbConnector.State).String(),
+			Configuration:       configuration,
+		})
+	}
+
+	sort.Slice(b.Connectors, func(i, j int) bool {
+		return b.Connectors[i].ID < b.Connectors[j].ID
+	})
+
+	return b, nil
+}
+
+// listOwnedConnectors returns all the connectors owned by ownerPermalink,
+// leaving out the public connectors of other owners
+func (s *service) listOwnedConnectors(ctx context.Context, ownerPermalink string) ([]*datamodel.Connector, error) {
+	var owned []*datamodel.Connector
+	pageToken := ""
+	for {
+		dbConnectors, _, nextPageToken, err := s.repository.ListConnectors(ctx, ownerPermalink, repository.MaxPageSize, pageToken, false, filtering.Filter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, dbConnector := range dbConnectors {
+			if dbConnector.Owner == ownerPermalink {
+				owned = append(owned, dbConnector)
+			}
+		}
+		if nextPageToken == "" {
+			return owned, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+func (s *service) exportCredentialFields(defID string, connID string, configuration map[string]interface{}, prefix string, credentials bundle.CredentialMode) {
+	for k, v := range configuration {
+		key := prefix + k
+		if s.connectorAll.IsCredentialField(defID, key) {
+			if credentials == bundle.CredentialPlaceholder {
+				configuration[k] = bundle.Placeholder(connID, key)
+			} else {
+				delete(configuration, k)
+			}
+			continue
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			s.exportCredentialFields(defID, connID, nested, key+".", credentials)
+		}
+	}
+}
+
+// fillCredentialFields completes the omitted and placeholder credential
+// fields of an imported configuration with the values of the existing one
+func (s *service) fillCredentialFields(defID string, configuration map[string]interface{}, existing map[string]interface{}, prefix string) {
+	for k, v := range existing {
+		key := prefix + k
+		if s.connectorAll.IsCredentialField(defID, key) {
+			if current, ok := configuration[k]; !ok {
+				configuration[k] = v
+			} else if _, ok := bundle.PlaceholderName(current); ok {
+				configuration[k] = v
+			}
+			continue
+		}
+		existingNested, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if nested, ok := configuration[k].(map[string]interface{}); ok {
+			s.fillCredentialFields(defID, nested, existingNested, key+".")
+		}
+	}
+}
+
+// findPlaceholder returns the path of the first unresolved placeholder of a configuration
+func findPlaceholder(configuration map[string]interface{}, prefix string) (string, string, bool) {
+	keys := make([]string, 0, len(configuration))
+	for k := range configuration {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if name, ok := bundle.PlaceholderName(configuration[k]); ok {
+			return prefix + k, name, true
+		}
+		if nested, ok := configuration[k].(map[string]interface{}); ok {
+			if path, name, ok := findPlaceholder(nested, prefix+k+"."); ok {
+				return path, name, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func (s *service) ImportConnectors(ctx context.Context, owner *mgmtPB.User, b *bundle.Bundle, strategy bundle.ConflictStrategy, dryRun bool) ([]*ImportResult, bool, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ownerPermalink := GenOwnerPermalink(owner)
+
+	if b.Version != bundle.Version {
+		return nil, false, status.Errorf(codes.InvalidArgument, "[service] import connectors: unsupported bundle version %q", b.Version)
+	}
+
+	results := make([]*ImportResult, len(b.Connectors))
+	entries := make([]*importEntry, len(b.Connectors))
+
+	// IDs taken by the bundle itself, so that renamed connectors do not collide with them
+	taken := map[string]bool{}
+	for _, c := range b.Connectors {
+		taken[c.ID] = true
+	}
+
+	// Validate every entry up front
+	seen := map[string]bool{}
+	for idx, c := range b.Connectors {
+		result := &ImportResult{ID: c.ID, ConnectorID: c.ID, Action: bundle.ActionCreate}
+		results[idx] = result
+
+		if seen[c.ID] {
+			result.Err = status.Errorf(codes.InvalidArgument, "[service] import connectors: connector id %s is repeated", c.ID)
+			continue
+		}
+		seen[c.ID] = true
+
+		existing, err := s.getOwnedConnector(ctx, ownerPermalink, c.ID, false)
+		if err != nil {
+			result.Err = err
+			continue
+		}
+		if existing != nil {
+			result.Conflict = true
+			switch strategy {
+			case bundle.ConflictSkip:
+				result.Action = bundle.ActionSkip
+				continue
+			case bundle.ConflictOverwrite:
+				result.Action = bundle.ActionOverwrite
+			case bundle.ConflictRename:
+				result.Action = bundle.ActionRename
+				result.ConnectorID, result.Err = s.freeConnectorID(ctx, ownerPermalink, c.ID, taken)
+				if result.Err != nil {
+					continue
+				}
+				taken[result.ConnectorID] = true
+				existing = nil
+			default:
+				st, err := sterr.CreateErrorResourceInfo(
+					codes.AlreadyExists,
+					"[service] import connectors",
+					"connectors",
+					fmt.Sprintf("Connector id %s", c.ID),
+					ownerPermalink,
+					"Already exists",
+				)
+				if err != nil {
+					logger.Error(err.Error())
+				}
+				result.Err = st.Err()
+				continue
+			}
+		}
+
+		entries[idx], result.Err = s.validateImportEntry(ctx, idx, c, result.ConnectorID, existing)
+		if result.Err == nil && existing == nil {
+			result.Err = s.checkConnectorDefinitionPolicy(ctx, entries[idx].connDef)
+		}
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			return results, false, nil
+		}
+	}
+	if dryRun {
+		return results, false, nil
+	}
+
+	// Write all the entries in a single transaction
+	if err := s.repository.Transaction(ctx, func(r repository.Repository) error {
+		for idx, c := range b.Connectors {
+			result, entry := results[idx], entries[idx]
+			switch result.Action {
+			case bundle.ActionCreate, bundle.ActionRename:
+				connDefUID, err := uuid.FromString(entry.connDef.GetUid())
+				if err != nil {
+					return BatchItemError(idx, err)
+				}
+				if err := r.CreateConnector(ctx, &datamodel.Connector{
+					ID:                     result.ConnectorID,
+					Owner:                  ownerPermalink,
+					ConnectorDefinitionUID: connDefUID,
+					Description:            sql.NullString{String: c.Description, Valid: len(c.Description) > 0},
+					Tombstone:              false,
+					Configuration:          entry.configuration,
+					ConnectorType:          datamodel.ConnectorType(entry.connDef.GetConnectorType()),
+					State:                  datamodel.ConnectorState(initialConnectorState(entry.connDef)),
+					Visibility:             entry.visibility,
+					Task:                   "TASK_UNSPECIFIED",
+				}); err != nil {
+					return BatchItemError(idx, err)
+				}
+			case bundle.ActionOverwrite:
+				// As with an update, the overwritten connector has to be connected again
+				if err := r.UpdateConnector(ctx, result.ConnectorID, ownerPermalink, &datamodel.Connector{
+					Description:   sql.NullString{String: c.Description, Valid: len(c.Description) > 0},
+					Configuration: entry.configuration,
+					State:         datamodel.ConnectorState(initialConnectorState(entry.connDef)),
+					Visibility:    entry.visibility,
+				}); err != nil {
+					return BatchItemError(idx, err)
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, false, err
+	}
+
+	for idx, result := range results {
+		if result.Action == bundle.ActionSkip {
+			continue
+		}
+		result.Connector, result.Err = s.repository.GetConnectorByID(ctx, result.ConnectorID, ownerPermalink, false)
+		if result.Err != nil {
+			continue
+		}
+		result.Err = s.UpdateResourceState(ctx, result.Connector.UID, initialConnectorState(entries[idx].connDef), nil)
+	}
+
+	return results, true, nil
+}
+
+// validateImportEntry checks a bundle entry against its connector definition.
+// The credentials of an overwritten connector are kept when the entry omits
+// them or carries placeholders.
+func (s *service) validateImportEntry(ctx context.Context, idx int, c *bundle.Connector, connID string, existing *datamodel.Connector) (*importEntry, error) {
+
+	badRequest := func(field string, description string) error {
+		st, err := sterr.CreateErrorBadRequest(
+			"[service] import connectors",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       fmt.Sprintf("connectors[%d].%s", idx, field),
+					Description: description,
+				},
+			},
+		)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, description)
+		}
+		return st.Err()
+	}
+
+	if strings.HasPrefix(connID, "instill-") {
+		return nil, badRequest("id", "the id can not start with instill-")
+	}
+	if err := checkfield.CheckResourceID(connID); err != nil {
+		return nil, badRequest("id", err.Error())
+	}
+
+	connDef, err := s.connectorAll.GetConnectorDefinitionById(c.ConnectorDefinition)
+	if err != nil {
+		return nil, badRequest("connector_definition", fmt.Sprintf("connector definition %s not found", c.ConnectorDefinition))
+	}
+
+	if existing != nil && existing.ConnectorDefinitionUID.String() != connDef.GetUid() {
+		return nil, badRequest("connector_definition", "the connector definition of an existing connector can not be changed")
+	}
+
+	visibility := connectorPB.Connector_VISIBILITY_PRIVATE
+	if c.Visibility != "" {
+		v, ok := connectorPB.Connector_Visibility_value[c.Visibility]
+		if !ok || v == int32(connectorPB.Connector_VISIBILITY_UNSPECIFIED) {
+			return nil, badRequest("visibility", fmt.Sprintf("invalid visibility %s", c.Visibility))
+		}
+		visibility = connectorPB.Connector_Visibility(v)
+	}
+
+	configuration := c.Configuration
+	if configuration == nil {
+		configuration = map[string]interface{}{}
+	}
+
+	// HTTP and gRPC connectors have a fixed id and no configuration
+	if strings.Contains(connDef.GetId(), "http") || strings.Contains(connDef.GetId(), "grpc") {
+		if connID != connDef.GetId() {
+			return nil, badRequest("id", fmt.Sprintf("Connector id must be %s", connDef.GetId()))
+		}
+		if len(configuration) > 0 {
+			return nil, badRequest("configuration", fmt.Sprintf("%s connector configuration must be an empty JSON", connDef.GetId()))
+		}
+	}
+
+	if existing != nil && existing.Configuration != nil {
+		existingConfiguration := map[string]interface{}{}
+		if err := json.Unmarshal(existing.Configuration, &existingConfiguration); err == nil {
+			s.fillCredentialFields(connDef.GetId(), configuration, existingConfiguration, "")
+		}
+	}
+
+	if path, name, ok := findPlaceholder(configuration, ""); ok {
+		return nil, badRequest("configuration."+path, fmt.Sprintf("unresolved credential placeholder ${%s}", name))
+	}
+
+	b, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, badRequest("configuration", err.Error())
+	}
+	if b, err = s.validateConfiguration(ctx, "import connectors", connDef, b, fmt.Sprintf("connectors[%d].configuration", idx)); err != nil {
+		return nil, err
+	}
+
+	return &importEntry{
+		connDef:       connDef,
+		configuration: b,
+		visibility:    datamodel.ConnectorVisibility(visibility),
+	}, nil
+}
+
+// freeConnectorID returns the first ID of the form {id}-{n} which is neither
+// used by a connector of the owner nor taken
+func (s *service) freeConnectorID(ctx context.Context, ownerPermalink string, id string, taken map[string]bool) (string, error) {
+	for n := 1; n <= 100; n++ {
+		newID := fmt.Sprintf("%s-%d", id, n)
+		if taken[newID] {
+			continue
+		}
+		existing, err := s.getOwnedConnector(ctx, ownerPermalink, newID, true)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return newID, nil
+		}
+	}
+	return "", status.Errorf(codes.AlreadyExists, "[service] import connectors: no free id to rename connector %s", id)
+}
+
+// getOwnedConnector returns the connector id of ownerPermalink, nil if the
+// owner has none. The public connectors of other owners do not conflict with
+// the imported ones.
+func (s *service) getOwnedConnector(ctx context.Context, ownerPermalink string, id string, isBasicView bool) (*datamodel.Connector, error) {
+	existing, err := s.repository.GetConnectorByID(ctx, id, ownerPermalink, isBasicView)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if existing.Owner != ownerPermalink {
+		return nil, nil
+	}
+	return existing, nil
+}
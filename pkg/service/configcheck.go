@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/grpc/codes"
+
+	"github.com/instill-ai/connector-backend/pkg/connector"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/x/sterr"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// ConfigurationOutcome is the outcome of the configuration check of a connector
+type ConfigurationOutcome string
+
+const (
+	// ConfigurationMigrated configurations are valid after the migrations of their definition
+	ConfigurationMigrated ConfigurationOutcome = "migrated"
+	// ConfigurationIncompatible configurations are invalid, their connectors are moved to STATE_ERROR
+	ConfigurationIncompatible ConfigurationOutcome = "incompatible"
+)
+
+// ConfigurationReport reports a connector whose configuration does not
+// validate against the current connection specification of its definition
+type ConfigurationReport struct {
+	ConnectorUID          uuid.UUID
+	ConnectorID           string
+	Owner                 string
+	ConnectorDefinitionID string
+	Outcome               ConfigurationOutcome
+	// Migrations are the names of the migrations which changed the configuration
+	Migrations []string
+	// Reason is the validation error of an incompatible configuration
+	Reason string
+}
+
+// CheckConnectorConfigurationsAdmin runs CheckConnectorConfigurations for an admin
+func (s *service) CheckConnectorConfigurationsAdmin(ctx context.Context, owner *mgmtPB.User, dryRun bool) ([]*ConfigurationReport, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if !IsAdmin(owner) {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.PermissionDenied,
+			"[service] check connector configurations",
+			"connectors",
+			"",
+			GenOwnerPermalink(owner),
+			"Permission denied",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	return s.CheckConnectorConfigurations(ctx, dryRun)
+}
+
+// CheckConnectorConfigurations validates the configuration of every connector
+// against the current connection specification of its definition. An invalid
+// configuration is rewritten by the migrations registered for the definition
+// and stored if it then validates. The connectors left with an invalid
+// configuration are moved to STATE_ERROR. With dryRun, nothing is written.
+func (s *service) CheckConnectorConfigurations(ctx context.Context, dryRun bool) ([]*ConfigurationReport, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	reports := []*ConfigurationReport{}
+	checked := 0
+	pageToken := ""
+	for {
+		dbConnectors, _, nextPageToken, err := s.repository.ListConnectorsAdmin(ctx, repository.MaxPageSize, pageToken, false, filtering.Filter{})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dbConnector := range dbConnectors {
+			checked++
+			report := s.checkConnectorConfiguration(dbConnector)
+			if report == nil {
+				continue
+			}
+			reports = append(reports, report)
+
+			switch report.Outcome {
+			case ConfigurationMigrated:
+				logger.Info(fmt.Sprintf("connector %s of %s migrated: %v", report.ConnectorID, report.Owner, report.Migrations))
+			case ConfigurationIncompatible:
+				logger.Warn(fmt.Sprintf("connector %s of %s has an incompatible configuration: %s", report.ConnectorID, report.Owner, report.Reason))
+			}
+			if dryRun {
+				continue
+			}
+			if err := s.applyConfigurationReport(ctx, dbConnector, report); err != nil {
+				return nil, err
+			}
+		}
+
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
+	logger.Info(fmt.Sprintf("%d connector configurations checked, %d reported", checked, len(reports)))
+
+	return reports, nil
+}
+
+// checkConnectorConfiguration returns the report of a connector, nil if its
+// configuration is valid. The configuration of a migrated connector is
+// replaced with the migrated one.
+func (s *service) checkConnectorConfiguration(dbConnector *datamodel.Connector) *ConfigurationReport {
+
+	report := &ConfigurationReport{
+		ConnectorUID: dbConnector.UID,
+		ConnectorID:  dbConnector.ID,
+		Owner:        dbConnector.Owner,
+		Outcome:      ConfigurationIncompatible,
+	}
+
+	connDef, err := s.connectorAll.GetConnectorDefinitionByUid(dbConnector.ConnectorDefinitionUID)
+	if err != nil {
+		report.Reason = fmt.Sprintf("connector definition %s not found", dbConnector.ConnectorDefinitionUID)
+		return report
+	}
+	report.ConnectorDefinitionID = connDef.GetId()
+
+	configuration := map[string]interface{}{}
+	if dbConnector.Configuration != nil {
+		if err := json.Unmarshal(dbConnector.Configuration, &configuration); err != nil {
+			report.Reason = err.Error()
+			return report
+		}
+	}
+
+	validate := func() error {
+		violations, err := connector.ValidateConfiguration(connDef, configuration)
+		if err != nil {
+			return err
+		}
+		if len(violations) > 0 {
+			reasons := make([]string, len(violations))
+			for idx, violation := range violations {
+				reasons[idx] = violation.Error()
+			}
+			return errors.New(strings.Join(reasons, "; "))
+		}
+		return nil
+	}
+
+	err = validate()
+	if err == nil {
+		return nil
+	}
+
+	for _, migration := range connector.ListConfigurationMigrations(connDef.GetId()) {
+		changed, migrateErr := migration.Migrate(configuration)
+		if migrateErr != nil {
+			report.Reason = fmt.Sprintf("migration %q: %s", migration.Name, migrateErr.Error())
+			return report
+		}
+		if changed {
+			report.Migrations = append(report.Migrations, migration.Name)
+		}
+	}
+	if len(report.Migrations) > 0 {
+		err = validate()
+	}
+	if err != nil {
+		report.Reason = err.Error()
+		return report
+	}
+
+	b, err := json.Marshal(configuration)
+	if err != nil {
+		report.Reason = err.Error()
+		return report
+	}
+	dbConnector.Configuration = b
+	report.Outcome = ConfigurationMigrated
+
+	return report
+}
+
+func (s *service) applyConfigurationReport(ctx context.Context, dbConnector *datamodel.Connector, report *ConfigurationReport) error {
+
+	switch report.Outcome {
+	case ConfigurationMigrated:
+		return s.repository.UpdateConnector(ctx, dbConnector.ID, dbConnector.Owner, &datamodel.Connector{
+			Configuration: dbConnector.Configuration,
+		})
+	case ConfigurationIncompatible:
+		// The reason is saved with the connector, so that it is returned when the connector is read
+		state := connectorPB.Connector_STATE_ERROR
+		if dbConnector.State != datamodel.ConnectorState(state) || dbConnector.StateReason.String != report.Reason {
+			if err := s.repository.UpdateConnectorStateErrorByUID(ctx, dbConnector.UID, dbConnector.Owner, report.Reason); err != nil {
+				return err
+			}
+		}
+		return s.UpdateResourceState(ctx, dbConnector.UID, state, nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func TestCheckConnectorConfigurationsSavesReason(t *testing.T) {
+	ctx := context.Background()
+	s, connDef, controller := newTestService(t)
+
+	spec, err := structpb.NewStruct(map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"host"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	connDef.Spec = &connectorPB.Spec{ConnectionSpecification: spec}
+
+	ownerPermalink := GenOwnerPermalink(newTestUser(ownerUID))
+	conn := newTestConnector(ownerPermalink, "incompatible", connectorPB.Connector_VISIBILITY_PRIVATE)
+	conn.ConnectorDefinitionUID = uuid.FromStringOrNil(connDef.GetUid())
+	conn.State = datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED)
+	conn.Configuration = []byte(`{}`)
+	if err := s.repository.CreateConnector(ctx, conn); err != nil {
+		t.Fatalf("create connector: %v", err)
+	}
+
+	// A dry run changes nothing
+	reports, err := s.CheckConnectorConfigurations(ctx, true)
+	if err != nil {
+		t.Fatalf("check connector configurations: %v", err)
+	}
+	if len(reports) != 1 || reports[0].Outcome != ConfigurationIncompatible || reports[0].Reason == "" {
+		t.Fatalf("reports: got %+v, want an incompatible configuration", reports)
+	}
+	dbConnector, err := s.repository.GetConnectorByID(ctx, conn.ID, ownerPermalink, true)
+	if err != nil {
+		t.Fatalf("get connector: %v", err)
+	}
+	if dbConnector.State != conn.State || dbConnector.StateReason.Valid {
+		t.Fatalf("dry run: got state %v and reason %q", dbConnector.State, dbConnector.StateReason.String)
+	}
+
+	reports, err = s.CheckConnectorConfigurations(ctx, false)
+	if err != nil {
+		t.Fatalf("check connector configurations: %v", err)
+	}
+	dbConnector, err = s.repository.GetConnectorByID(ctx, conn.ID, ownerPermalink, true)
+	if err != nil {
+		t.Fatalf("get connector: %v", err)
+	}
+	if dbConnector.State != datamodel.ConnectorState(connectorPB.Connector_STATE_ERROR) || dbConnector.StateReason.String != reports[0].Reason {
+		t.Fatalf("incompatible connector: got state %v and reason %q, want STATE_ERROR and %q", dbConnector.State, dbConnector.StateReason.String, reports[0].Reason)
+	}
+	if got := controller.states[utils.ConvertConnectorToResourceName(dbConnector.UID.String())]; got != connectorPB.Connector_STATE_ERROR {
+		t.Fatalf("controller state: got %v, want STATE_ERROR", got)
+	}
+}
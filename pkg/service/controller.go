@@ -10,8 +10,11 @@ import (
 	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
 )
 
-func (s *service) GetResourceState(connectorUID uuid.UUID) (*connectorPB.Connector_State, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// controllerTimeout bounds the controller calls of the callers without deadline
+const controllerTimeout = 10 * time.Second
+
+func (s *service) GetResourceState(ctx context.Context, connectorUID uuid.UUID) (*connectorPB.Connector_State, error) {
+	ctx, cancel := utils.WithDefaultTimeout(ctx, controllerTimeout)
 	defer cancel()
 
 	resourcePermalink := utils.ConvertConnectorToResourceName(connectorUID.String())
@@ -27,8 +30,8 @@ func (s *service) GetResourceState(connectorUID uuid.UUID) (*connectorPB.Connect
 	return resp.Resource.GetConnectorState().Enum(), nil
 }
 
-func (s *service) UpdateResourceState(connectorUID uuid.UUID, state connectorPB.Connector_State, progress *int32) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *service) UpdateResourceState(ctx context.Context, connectorUID uuid.UUID, state connectorPB.Connector_State, progress *int32) error {
+	ctx, cancel := utils.WithDefaultTimeout(ctx, controllerTimeout)
 	defer cancel()
 
 	resourcePermalink := utils.ConvertConnectorToResourceName(connectorUID.String())
@@ -51,8 +54,8 @@ func (s *service) UpdateResourceState(connectorUID uuid.UUID, state connectorPB.
 	return nil
 }
 
-func (s *service) DeleteResourceState(connectorUID uuid.UUID) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *service) DeleteResourceState(ctx context.Context, connectorUID uuid.UUID) error {
+	ctx, cancel := utils.WithDefaultTimeout(ctx, controllerTimeout)
 	defer cancel()
 
 	resourcePermalink := utils.ConvertConnectorToResourceName(connectorUID.String())
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/x/sterr"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func (s *service) ListConnectorDefinitionPolicies(ctx context.Context) (map[uuid.UUID]*datamodel.ConnectorDefinitionPolicy, error) {
+
+	policies, err := s.repository.ListConnectorDefinitionPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policyMap := make(map[uuid.UUID]*datamodel.ConnectorDefinitionPolicy, len(policies))
+	for _, policy := range policies {
+		policyMap[policy.ConnectorDefinitionUID] = policy
+	}
+
+	return policyMap, nil
+}
+
+func (s *service) GetConnectorDefinitionPolicy(ctx context.Context, connDefUID uuid.UUID) (*datamodel.ConnectorDefinitionPolicy, error) {
+
+	connDef, err := s.connectorAll.GetConnectorDefinitionByUid(connDefUID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.repository.GetConnectorDefinitionPolicy(ctx, connDefUID)
+	if status.Code(err) == codes.NotFound {
+		return &datamodel.ConnectorDefinitionPolicy{
+			ConnectorDefinitionUID: connDefUID,
+			ConnectorDefinitionID:  connDef.GetId(),
+			State:                  datamodel.DefinitionPolicyEnabled,
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *service) UpdateConnectorDefinitionPolicy(ctx context.Context, owner *mgmtPB.User, policy *datamodel.ConnectorDefinitionPolicy) (*datamodel.ConnectorDefinitionPolicy, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if !IsAdmin(owner) {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.PermissionDenied,
+			"[service] update connector definition policy",
+			"connector-definitions",
+			fmt.Sprintf("id %s", policy.ConnectorDefinitionID),
+			GenOwnerPermalink(owner),
+			"Permission denied",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	connDef, err := s.connectorAll.GetConnectorDefinitionByUid(policy.ConnectorDefinitionUID)
+	if err != nil {
+		return nil, err
+	}
+	policy.ConnectorDefinitionID = connDef.GetId()
+
+	if err := s.applyConnectorDefinitionPolicy(ctx, s.repository, policy); err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("connector definition %s set to %s by %s", policy.ConnectorDefinitionID, policy.State, owner.GetId()))
+
+	return s.repository.GetConnectorDefinitionPolicy(ctx, policy.ConnectorDefinitionUID)
+}
+
+// SeedConnectorDefinitionPolicies stores the policies of the configuration
+// whose definitions have no policy yet, so that the policies updated at
+// runtime survive a restart
+func (s *service) SeedConnectorDefinitionPolicies(ctx context.Context, policies []config.DefinitionPolicyConfig) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	for _, p := range policies {
+		connDef, err := s.connectorAll.GetConnectorDefinitionById(p.ID)
+		if err != nil {
+			return fmt.Errorf("connector definition policy %s: %w", p.ID, err)
+		}
+		connDefUID, err := uuid.FromString(connDef.GetUid())
+		if err != nil {
+			return err
+		}
+
+		policy := &datamodel.ConnectorDefinitionPolicy{
+			ConnectorDefinitionUID: connDefUID,
+			ConnectorDefinitionID:  connDef.GetId(),
+			State:                  datamodel.DefinitionPolicyState(p.State),
+			Message:                p.Message,
+		}
+
+		if err := s.repository.Transaction(ctx, func(r repository.Repository) error {
+			if _, err := r.GetConnectorDefinitionPolicy(ctx, connDefUID); status.Code(err) != codes.NotFound {
+				return err
+			}
+			return s.applyConnectorDefinitionPolicy(ctx, r, policy)
+		}); err != nil {
+			return err
+		}
+
+		logger.Info(fmt.Sprintf("connector definition %s seeded as %s", policy.ConnectorDefinitionID, policy.State))
+	}
+
+	return nil
+}
+
+// applyConnectorDefinitionPolicy validates and stores the policy
+func (s *service) applyConnectorDefinitionPolicy(ctx context.Context, r repository.Repository, policy *datamodel.ConnectorDefinitionPolicy) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if !policy.State.IsValid() {
+		st, err := sterr.CreateErrorBadRequest(
+			"[service] update connector definition policy",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "state",
+					Description: fmt.Sprintf("Invalid state %q, must be one of enabled, deprecated or hidden", policy.State),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	return r.UpsertConnectorDefinitionPolicy(ctx, policy)
+}
+
+// checkConnectorDefinitionPolicy returns a precondition failure if the
+// policy of the definition rejects new connectors
+func (s *service) checkConnectorDefinitionPolicy(ctx context.Context, connDef *connectorPB.ConnectorDefinition) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	connDefUID, err := uuid.FromString(connDef.GetUid())
+	if err != nil {
+		return err
+	}
+
+	policy, err := s.GetConnectorDefinitionPolicy(ctx, connDefUID)
+	if err != nil {
+		return err
+	}
+
+	if policy.State.AcceptsConnectors() {
+		if policy.State == datamodel.DefinitionPolicyDeprecated {
+			logger.Warn(fmt.Sprintf("connector created with the deprecated connector definition %s: %s", connDef.GetId(), policy.Message))
+		}
+		return nil
+	}
+
+	description := fmt.Sprintf("Connector definition %s is %s", connDef.GetId(), policy.State)
+	if policy.Message != "" {
+		description = fmt.Sprintf("%s: %s", description, policy.Message)
+	}
+	st, err := sterr.CreateErrorPreconditionFailure(
+		"[service] create connector",
+		[]*errdetails.PreconditionFailure_Violation{
+			{
+				Type:        "CREATE",
+				Subject:     fmt.Sprintf("connector-definitions/%s", connDef.GetId()),
+				Description: description,
+			},
+		})
+	if err != nil {
+		logger.Error(err.Error())
+	}
+	return st.Err()
+}
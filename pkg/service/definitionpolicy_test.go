@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func newTestAdmin(t *testing.T) *mgmtPB.User {
+	t.Helper()
+	admins := config.Config.Server.Admins
+	config.Config.Server.Admins = []string{"admin"}
+	t.Cleanup(func() { config.Config.Server.Admins = admins })
+	admin := newTestUser(ownerUID)
+	admin.Id = "admin"
+	return admin
+}
+
+func TestUpdateConnectorDefinitionPolicy(t *testing.T) {
+	ctx := context.Background()
+	s, connDef, _ := newTestService(t)
+	admin := newTestAdmin(t)
+	connDefUID := uuid.FromStringOrNil(connDef.GetUid())
+
+	ownerPermalink := GenOwnerPermalink(admin)
+	tombstoned := newTestConnector(ownerPermalink, "tombstoned", connectorPB.Connector_VISIBILITY_PRIVATE)
+	tombstoned.ConnectorDefinitionUID = connDefUID
+	tombstoned.Tombstone = true
+	kept := newTestConnector(ownerPermalink, "kept", connectorPB.Connector_VISIBILITY_PRIVATE)
+	kept.ConnectorDefinitionUID = connDefUID
+	for _, conn := range []*datamodel.Connector{tombstoned, kept} {
+		if err := s.repository.CreateConnector(ctx, conn); err != nil {
+			t.Fatalf("create connector %s: %v", conn.ID, err)
+		}
+	}
+
+	policy := func(state datamodel.DefinitionPolicyState) *datamodel.ConnectorDefinitionPolicy {
+		return &datamodel.ConnectorDefinitionPolicy{ConnectorDefinitionUID: connDefUID, State: state}
+	}
+
+	if _, err := s.UpdateConnectorDefinitionPolicy(ctx, newTestUser(otherOwnerUID), policy(datamodel.DefinitionPolicyHidden)); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("update the policy as a user: got %v, want PermissionDenied", err)
+	}
+	if _, err := s.UpdateConnectorDefinitionPolicy(ctx, admin, policy("disabled")); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("update the policy to an unknown state: got %v, want InvalidArgument", err)
+	}
+
+	for _, c := range []struct {
+		state   datamodel.DefinitionPolicyState
+		created codes.Code
+	}{
+		{datamodel.DefinitionPolicyHidden, codes.FailedPrecondition},
+		{datamodel.DefinitionPolicyDeprecated, codes.OK},
+		{datamodel.DefinitionPolicyEnabled, codes.OK},
+	} {
+		updated, err := s.UpdateConnectorDefinitionPolicy(ctx, admin, policy(c.state))
+		if err != nil {
+			t.Fatalf("update the policy to %s: %v", c.state, err)
+		}
+		if updated.State != c.state || updated.ConnectorDefinitionID != connDef.GetId() {
+			t.Fatalf("update the policy to %s: got %+v", c.state, updated)
+		}
+		if err := s.checkConnectorDefinitionPolicy(ctx, connDef); status.Code(err) != c.created {
+			t.Fatalf("create a connector of a %s definition: got %v, want %s", c.state, err, c.created)
+		}
+	}
+
+	// The policy never rewrites the stored connectors
+	for _, want := range []*datamodel.Connector{tombstoned, kept} {
+		got, err := s.repository.GetConnectorByID(ctx, want.ID, ownerPermalink, true)
+		if err != nil {
+			t.Fatalf("get connector %s: %v", want.ID, err)
+		}
+		if got.Tombstone != want.Tombstone {
+			t.Fatalf("connector %s: got tombstone %v, want %v", want.ID, got.Tombstone, want.Tombstone)
+		}
+	}
+}
+
+func TestGetConnectorDefinitionPolicyDefault(t *testing.T) {
+	s, connDef, _ := newTestService(t)
+	policy, err := s.GetConnectorDefinitionPolicy(context.Background(), uuid.FromStringOrNil(connDef.GetUid()))
+	if err != nil {
+		t.Fatalf("get connector definition policy: %v", err)
+	}
+	if policy.State != datamodel.DefinitionPolicyEnabled || policy.ConnectorDefinitionID != connDef.GetId() {
+		t.Fatalf("get connector definition policy without a policy: got %+v", policy)
+	}
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+const (
+	// defaultMeteringWindow is the window of the counters if none is configured
+	defaultMeteringWindow = time.Hour
+	// defaultQuotaPeriod is the period of the plans which set none
+	defaultQuotaPeriod = 24 * time.Hour
+)
+
+// ExecutionUsage counts connector executions, the input items and the size
+// in bytes of the input payloads
+type ExecutionUsage struct {
+	Executions   int64
+	InputItems   int64
+	PayloadBytes int64
+}
+
+func (u *ExecutionUsage) add(m *datamodel.ExecutionMetering) {
+	u.Executions += m.Executions
+	u.InputItems += m.InputItems
+	u.PayloadBytes += m.PayloadBytes
+}
+
+// ExecutionQuota is the execution quota of an owner over a period, a limit
+// of 0 is unlimited
+type ExecutionQuota struct {
+	Plan   string
+	Period time.Duration
+	Limits ExecutionUsage
+}
+
+// ConnectorExecutionUsage is the execution usage of a connector
+type ConnectorExecutionUsage struct {
+	ConnectorUID           uuid.UUID
+	ConnectorDefinitionUID uuid.UUID
+	ExecutionUsage
+}
+
+// WindowExecutionUsage is the execution usage over a metering window
+type WindowExecutionUsage struct {
+	WindowStart time.Time
+	ExecutionUsage
+}
+
+// OwnerExecutionUsage is the execution usage of an owner over the current
+// quota period
+type OwnerExecutionUsage struct {
+	Owner       string
+	Quota       *ExecutionQuota
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Total       ExecutionUsage
+	// Connectors are ordered by connector uid
+	Connectors []*ConnectorExecutionUsage
+	// Windows are the metering windows of the period with executions, in order
+	Windows []*WindowExecutionUsage
+}
+
+// ExecutionQuotaOf returns the quota of the owner, set by the plan assigned
+// to the owner, or by the default plan, with the limits overridden for the owner
+func ExecutionQuotaOf(owner *mgmtPB.User) *ExecutionQuota {
+
+	meteringConfig := config.Current().Server.Metering
+
+	planName := meteringConfig.DefaultPlan
+	var ownerConfig *config.OwnerQuotaConfig
+	for idx := range meteringConfig.Owners {
+		if meteringConfig.Owners[idx].ID == owner.GetId() {
+			ownerConfig = &meteringConfig.Owners[idx]
+			if ownerConfig.Plan != "" {
+				planName = ownerConfig.Plan
+			}
+			break
+		}
+	}
+
+	quota := &ExecutionQuota{Plan: planName, Period: defaultQuotaPeriod}
+	for _, plan := range meteringConfig.Plans {
+		if plan.Name == planName {
+			if plan.Period > 0 {
+				quota.Period = plan.Period
+			}
+			quota.Limits = ExecutionUsage{
+				Executions:   plan.Executions,
+				InputItems:   plan.InputItems,
+				PayloadBytes: plan.PayloadBytes,
+			}
+			break
+		}
+	}
+
+	if ownerConfig != nil {
+		override := func(limit *int64, v int64) {
+			if v != 0 {
+				*limit = v
+			}
+		}
+		override(&quota.Limits.Executions, ownerConfig.Executions)
+		override(&quota.Limits.InputItems, ownerConfig.InputItems)
+		override(&quota.Limits.PayloadBytes, ownerConfig.PayloadBytes)
+	}
+	for _, limit := range []*int64{&quota.Limits.Executions, &quota.Limits.InputItems, &quota.Limits.PayloadBytes} {
+		if *limit < 0 {
+			*limit = 0
+		}
+	}
+
+	return quota
+}
+
+// periodStart returns the start of the quota period including now. The
+// periods are aligned on the zero time, so that a 24h period starts at
+// midnight UTC.
+func (q *ExecutionQuota) periodStart(now time.Time) time.Time {
+	return now.UTC().Truncate(q.Period)
+}
+
+func (q *ExecutionQuota) unlimited() bool {
+	return q.Limits.Executions <= 0 && q.Limits.InputItems <= 0 && q.Limits.PayloadBytes <= 0
+}
+
+// GetExecutionUsage returns the execution usage of target over the current
+// quota period. Only the target itself and the admins can read it.
+func (s *service) GetExecutionUsage(ctx context.Context, owner *mgmtPB.User, target *mgmtPB.User) (*OwnerExecutionUsage, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	targetPermalink := GenOwnerPermalink(target)
+
+	if !CanWriteOwner(owner, targetPermalink) {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.PermissionDenied,
+			"[service] get execution usage",
+			"usage",
+			"",
+			targetPermalink,
+			"Permission denied",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	if !config.Current().Server.Metering.Enabled {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.FailedPrecondition,
+			"[service] get execution usage",
+			"usage",
+			"",
+			targetPermalink,
+			"Metering is disabled",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	quota := ExecutionQuotaOf(target)
+	start := quota.periodStart(time.Now())
+
+	meterings, err := s.repository.ListExecutionMetering(ctx, targetPermalink, start)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &OwnerExecutionUsage{
+		Owner:       targetPermalink,
+		Quota:       quota,
+		PeriodStart: start,
+		PeriodEnd:   start.Add(quota.Period),
+		Connectors:  []*ConnectorExecutionUsage{},
+		Windows:     []*WindowExecutionUsage{},
+	}
+	connectors := map[uuid.UUID]*ConnectorExecutionUsage{}
+	for _, m := range meterings {
+		usage.Total.add(m)
+
+		c, ok := connectors[m.ConnectorUID]
+		if !ok {
+			c = &ConnectorExecutionUsage{ConnectorUID: m.ConnectorUID, ConnectorDefinitionUID: m.ConnectorDefinitionUID}
+			connectors[m.ConnectorUID] = c
+			usage.Connectors = append(usage.Connectors, c)
+		}
+		c.add(m)
+
+		// The meterings are ordered by window
+		if n := len(usage.Windows); n == 0 || !usage.Windows[n-1].WindowStart.Equal(m.WindowStart) {
+			usage.Windows = append(usage.Windows, &WindowExecutionUsage{WindowStart: m.WindowStart.UTC()})
+		}
+		usage.Windows[len(usage.Windows)-1].add(m)
+	}
+	sort.Slice(usage.Connectors, func(i, j int) bool {
+		return usage.Connectors[i].ConnectorUID.String() < usage.Connectors[j].ConnectorUID.String()
+	})
+
+	return usage, nil
+}
+
+// checkExecutionQuota returns a ResourceExhausted error with a QuotaFailure
+// detail if executing the connector with the inputs exceeds the quota of the
+// owner. Concurrent executions are checked against the same usage, so that
+// they may exceed the quota by the executions in flight.
+func (s *service) checkExecutionQuota(ctx context.Context, owner *mgmtPB.User, execution ExecutionUsage) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	quota := ExecutionQuotaOf(owner)
+	if quota.unlimited() {
+		return nil
+	}
+
+	ownerPermalink := GenOwnerPermalink(owner)
+	meterings, err := s.repository.ListExecutionMetering(ctx, ownerPermalink, quota.periodStart(time.Now()))
+	if err != nil {
+		return err
+	}
+	used := ExecutionUsage{}
+	for _, m := range meterings {
+		used.add(m)
+	}
+
+	violations := []*errdetails.QuotaFailure_Violation{}
+	for _, c := range []struct {
+		name  string
+		used  int64
+		more  int64
+		limit int64
+	}{
+		{"executions", used.Executions, execution.Executions, quota.Limits.Executions},
+		{"input items", used.InputItems, execution.InputItems, quota.Limits.InputItems},
+		{"payload bytes", used.PayloadBytes, execution.PayloadBytes, quota.Limits.PayloadBytes},
+	} {
+		if c.limit > 0 && c.used+c.more > c.limit {
+			violations = append(violations, &errdetails.QuotaFailure_Violation{
+				Subject:     ownerPermalink,
+				Description: fmt.Sprintf("%s quota of plan %s exceeded: %d used and %d requested of %d per %s", c.name, quota.Plan, c.used, c.more, c.limit, quota.Period),
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	st, err := status.New(codes.ResourceExhausted, "[service] execute connector: quota exceeded").WithDetails(&errdetails.QuotaFailure{
+		Violations: violations,
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		return status.Error(codes.ResourceExhausted, "[service] execute connector: quota exceeded")
+	}
+	return st.Err()
+}
+
+// meterExecution adds the execution to the counters of the owner executing
+// the connector in the current metering window
+func (s *service) meterExecution(ctx context.Context, ownerPermalink string, conn *datamodel.Connector, execution ExecutionUsage) error {
+
+	window := config.Config.Server.Metering.Window
+	if window <= 0 {
+		window = defaultMeteringWindow
+	}
+
+	return s.repository.IncrementExecutionMetering(ctx, &datamodel.ExecutionMetering{
+		Owner:                  ownerPermalink,
+		ConnectorUID:           conn.UID,
+		ConnectorDefinitionUID: conn.ConnectorDefinitionUID,
+		WindowStart:            time.Now().UTC().Truncate(window),
+		Executions:             execution.Executions,
+		InputItems:             execution.InputItems,
+		PayloadBytes:           execution.PayloadBytes,
+	})
+}
+
+// executionOf returns the usage of a single execution with the inputs
+func executionOf(inputs []*connectorPB.DataPayload) ExecutionUsage {
+	execution := ExecutionUsage{Executions: 1, InputItems: int64(len(inputs))}
+	for _, input := range inputs {
+		execution.PayloadBytes += int64(proto.Size(input))
+	}
+	return execution
+}
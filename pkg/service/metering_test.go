@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func setTestMetering(t *testing.T) {
+	t.Helper()
+	metering := config.Config.Server.Metering
+	t.Cleanup(func() { config.Config.Server.Metering = metering })
+
+	config.Config.Server.Metering.Enabled = true
+	// The windows are the free plan periods, so that the executions of a test are counted in a single window
+	config.Config.Server.Metering.Window = defaultQuotaPeriod
+	config.Config.Server.Metering.DefaultPlan = "free"
+	config.Config.Server.Metering.Plans = []config.QuotaPlanConfig{
+		{Name: "free", Executions: 2, PayloadBytes: 100},
+		{Name: "pro", Period: time.Hour},
+	}
+	config.Config.Server.Metering.Owners = []config.OwnerQuotaConfig{
+		{ID: "pro-user", Plan: "pro"},
+		{ID: "bulk-user", Executions: -1, InputItems: 5},
+	}
+}
+
+func newTestUserWithID(uid string, id string) *mgmtPB.User {
+	user := newTestUser(uid)
+	user.Id = id
+	return user
+}
+
+func TestExecutionQuotaOf(t *testing.T) {
+	setTestMetering(t)
+
+	for _, c := range []struct {
+		id   string
+		want ExecutionQuota
+	}{
+		{"free-user", ExecutionQuota{Plan: "free", Period: defaultQuotaPeriod, Limits: ExecutionUsage{Executions: 2, PayloadBytes: 100}}},
+		{"pro-user", ExecutionQuota{Plan: "pro", Period: time.Hour}},
+		{"bulk-user", ExecutionQuota{Plan: "free", Period: defaultQuotaPeriod, Limits: ExecutionUsage{InputItems: 5, PayloadBytes: 100}}},
+	} {
+		if got := ExecutionQuotaOf(newTestUserWithID(ownerUID, c.id)); *got != c.want {
+			t.Errorf("quota of %s: got %+v, want %+v", c.id, *got, c.want)
+		}
+	}
+}
+
+func TestCheckExecutionQuota(t *testing.T) {
+	ctx := context.Background()
+	setTestMetering(t)
+	s, _, _ := newTestService(t)
+
+	owner := newTestUserWithID(ownerUID, "free-user")
+	ownerPermalink := GenOwnerPermalink(owner)
+	conn := newTestConnector(ownerPermalink, "metered", connectorPB.Connector_VISIBILITY_PRIVATE)
+	conn.UID = uuid.Must(uuid.NewV4())
+
+	// The executions of the previous periods are not counted
+	if err := s.repository.IncrementExecutionMetering(ctx, &datamodel.ExecutionMetering{
+		Owner:                  ownerPermalink,
+		ConnectorUID:           conn.UID,
+		ConnectorDefinitionUID: conn.ConnectorDefinitionUID,
+		WindowStart:            time.Now().UTC().Add(-2 * defaultQuotaPeriod).Truncate(defaultQuotaPeriod),
+		Executions:             10,
+	}); err != nil {
+		t.Fatalf("meter a previous execution: %v", err)
+	}
+
+	execution := ExecutionUsage{Executions: 1, InputItems: 1, PayloadBytes: 40}
+	for i := 0; i < 2; i++ {
+		if err := s.checkExecutionQuota(ctx, owner, execution); err != nil {
+			t.Fatalf("check execution %d: %v", i, err)
+		}
+		if err := s.meterExecution(ctx, ownerPermalink, conn, execution); err != nil {
+			t.Fatalf("meter execution %d: %v", i, err)
+		}
+	}
+
+	err := s.checkExecutionQuota(ctx, owner, execution)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("check an execution above the quota: got %v, want ResourceExhausted", err)
+	}
+	var violations []*errdetails.QuotaFailure_Violation
+	for _, detail := range status.Convert(err).Details() {
+		if quotaFailure, ok := detail.(*errdetails.QuotaFailure); ok {
+			violations = append(violations, quotaFailure.GetViolations()...)
+		}
+	}
+	// Both the executions and the payload bytes are exceeded
+	if len(violations) != 2 || violations[0].GetSubject() != ownerPermalink {
+		t.Fatalf("quota violations: got %v, want the executions and the payload bytes of %s", violations, ownerPermalink)
+	}
+
+	// The usage of the other owners and the unlimited plans are not checked
+	for _, other := range []*mgmtPB.User{newTestUserWithID(otherOwnerUID, "free-user"), newTestUserWithID(ownerUID, "pro-user")} {
+		if err := s.checkExecutionQuota(ctx, other, execution); err != nil {
+			t.Fatalf("check an execution of %s %s: %v", other.GetUid(), other.GetId(), err)
+		}
+	}
+
+	usage, err := s.GetExecutionUsage(ctx, owner, owner)
+	if err != nil {
+		t.Fatalf("get execution usage: %v", err)
+	}
+	if usage.Total != (ExecutionUsage{Executions: 2, InputItems: 2, PayloadBytes: 80}) || len(usage.Connectors) != 1 || len(usage.Windows) != 1 {
+		t.Fatalf("execution usage: got %+v", usage)
+	}
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"go.einride.tech/aip/filtering"
@@ -11,12 +13,16 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/bundle"
 	"github.com/instill-ai/connector-backend/pkg/connector"
+	"github.com/instill-ai/connector-backend/pkg/connector/builtin"
 	"github.com/instill-ai/connector-backend/pkg/datamodel"
 	"github.com/instill-ai/connector-backend/pkg/logger"
 	"github.com/instill-ai/connector-backend/pkg/repository"
 	"github.com/instill-ai/x/sterr"
 
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
 	connectorBase "github.com/instill-ai/connector/pkg/base"
 	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
 	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
@@ -37,20 +43,51 @@ type Service interface {
 	UpdateConnectorID(ctx context.Context, id string, owner *mgmtPB.User, newID string) (*datamodel.Connector, error)
 	UpdateConnectorState(ctx context.Context, id string, ownerPermalink string, state datamodel.ConnectorState) (*datamodel.Connector, error)
 	DeleteConnector(ctx context.Context, id string, owner *mgmtPB.User) error
+	CloneConnector(ctx context.Context, id string, owner *mgmtPB.User, newID string, targetOwner *mgmtPB.User, visibility datamodel.ConnectorVisibility) (*datamodel.Connector, error)
+
+	// Connector batch operations, all-or-nothing in a single transaction or best-effort with per-item results
+	BatchCreateConnectors(ctx context.Context, owner *mgmtPB.User, connectors []*datamodel.Connector, allOrNothing bool) ([]*BatchResult, error)
+	BatchDeleteConnectors(ctx context.Context, owner *mgmtPB.User, ids []string, allOrNothing bool) ([]*BatchResult, error)
+	BatchUpdateConnectorState(ctx context.Context, owner *mgmtPB.User, ids []string, state datamodel.ConnectorState, allOrNothing bool) ([]*BatchResult, error)
+
+	// Connector bundles
+	ExportConnectors(ctx context.Context, owner *mgmtPB.User, credentials bundle.CredentialMode) (*bundle.Bundle, error)
+	ImportConnectors(ctx context.Context, owner *mgmtPB.User, b *bundle.Bundle, strategy bundle.ConflictStrategy, dryRun bool) ([]*ImportResult, bool, error)
+
+	// Connector definition policies set by the admins
+	ListConnectorDefinitionPolicies(ctx context.Context) (map[uuid.UUID]*datamodel.ConnectorDefinitionPolicy, error)
+	GetConnectorDefinitionPolicy(ctx context.Context, connDefUID uuid.UUID) (*datamodel.ConnectorDefinitionPolicy, error)
+	UpdateConnectorDefinitionPolicy(ctx context.Context, owner *mgmtPB.User, policy *datamodel.ConnectorDefinitionPolicy) (*datamodel.ConnectorDefinitionPolicy, error)
+	SeedConnectorDefinitionPolicies(ctx context.Context, policies []config.DefinitionPolicyConfig) error
+
+	// Builtin connector definitions uploaded by the admins
+	LoadBuiltinConnectorDefinitions(ctx context.Context) error
+	WatchBuiltinConnectorDefinitions(ctx context.Context, interval time.Duration)
+	ListBuiltinConnectorDefinitions(ctx context.Context, owner *mgmtPB.User) ([]*builtin.Spec, error)
+	RegisterBuiltinConnectorDefinition(ctx context.Context, owner *mgmtPB.User, spec *builtin.Spec) (*connectorPB.ConnectorDefinition, error)
+	DeleteBuiltinConnectorDefinition(ctx context.Context, owner *mgmtPB.User, id string) error
+
+	// Validation and migration of the stored configurations against the current definitions
+	CheckConnectorConfigurations(ctx context.Context, dryRun bool) ([]*ConfigurationReport, error)
+	CheckConnectorConfigurationsAdmin(ctx context.Context, owner *mgmtPB.User, dryRun bool) ([]*ConfigurationReport, error)
 
 	ListConnectorsAdmin(ctx context.Context, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter) ([]*datamodel.Connector, int64, string, error)
 	GetConnectorByUIDAdmin(ctx context.Context, uid uuid.UUID, isBasicView bool) (*datamodel.Connector, error)
 
-	// Execute connector
+	// Execute connector, within the execution quota of the owner
 	Execute(ctx context.Context, id string, owner *mgmtPB.User, inputs []*connectorPB.DataPayload) ([]*connectorPB.DataPayload, error)
+	GetExecutionUsage(ctx context.Context, owner *mgmtPB.User, target *mgmtPB.User) (*OwnerExecutionUsage, error)
+
+	// Audit events of the connector changes and executions, listed by the admins
+	ListAuditEvents(ctx context.Context, owner *mgmtPB.User, filter repository.AuditEventFilter, pageSize int64, pageToken string) ([]*datamodel.AuditEvent, string, error)
 
 	// Shared public/private method for checking connector's connection
 	CheckConnectorByUID(ctx context.Context, connUID uuid.UUID) (*connectorPB.Connector_State, error)
 
 	// Controller custom service
-	GetResourceState(uid uuid.UUID) (*connectorPB.Connector_State, error)
-	UpdateResourceState(uid uuid.UUID, state connectorPB.Connector_State, progress *int32) error
-	DeleteResourceState(uid uuid.UUID) error
+	GetResourceState(ctx context.Context, uid uuid.UUID) (*connectorPB.Connector_State, error)
+	UpdateResourceState(ctx context.Context, uid uuid.UUID, state connectorPB.Connector_State, progress *int32) error
+	DeleteResourceState(ctx context.Context, uid uuid.UUID) error
 }
 
 type service struct {
@@ -59,6 +96,7 @@ type service struct {
 	pipelinePublicServiceClient pipelinePB.PipelinePublicServiceClient
 	controllerClient            controllerPB.ControllerPrivateServiceClient
 	connectorAll                connectorBase.IConnector
+	builtinConnector            *builtin.Connector
 }
 
 // NewService initiates a service instance
@@ -76,6 +114,7 @@ func NewService(
 		pipelinePublicServiceClient: p,
 		controllerClient:            c,
 		connectorAll:                connector.InitConnectorAll(logger),
+		builtinConnector:            builtin.Init(logger),
 	}
 }
 
@@ -86,17 +125,52 @@ func (s *service) GetMgmtPrivateServiceClient() mgmtPB.MgmtPrivateServiceClient
 
 func (s *service) CreateConnector(ctx context.Context, owner *mgmtPB.User, connector *datamodel.Connector) (*datamodel.Connector, error) {
 
-	logger, _ := logger.GetZapLogger(ctx)
-
 	ownerPermalink := GenOwnerPermalink(owner)
 
 	connector.Owner = ownerPermalink
 
+	connDef, err := s.validateCreateConnector(ctx, owner, connector)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.CreateConnector(ctx, connector); err != nil {
+		return nil, err
+	}
+
+	// User desire state = CONNECTED for HTTP and gRPC connectors, DISCONNECTED otherwise
+	state := initialConnectorState(connDef)
+	if err := s.repository.UpdateConnectorStateByID(ctx, connector.ID, connector.Owner, datamodel.ConnectorState(state)); err != nil {
+		return nil, err
+	}
+	if err := s.UpdateResourceState(ctx, connector.UID, state, nil); err != nil {
+		return nil, err
+	}
+
+	dbConnector, err := s.repository.GetConnectorByID(ctx, connector.ID, ownerPermalink, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbConnector, nil
+
+}
+
+// validateCreateConnector checks a connector to be created by the owner and
+// returns its connector definition
+func (s *service) validateCreateConnector(ctx context.Context, owner *mgmtPB.User, connector *datamodel.Connector) (*connectorPB.ConnectorDefinition, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
 	connDef, err := s.connectorAll.GetConnectorDefinitionByUid(connector.ConnectorDefinitionUID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.checkConnectorDefinitionPolicy(ctx, connDef); err != nil {
+		return nil, err
+	}
+
 	// Validation: HTTP and gRPC connector
 	if strings.Contains(connDef.GetId(), "http") || strings.Contains(connDef.GetId(), "grpc") {
 		if connector.ID != connDef.GetId() {
@@ -147,35 +221,65 @@ func (s *service) CreateConnector(ctx context.Context, owner *mgmtPB.User, conne
 		}
 	}
 
-	if err := s.repository.CreateConnector(ctx, connector); err != nil {
+	if connector.Configuration, err = s.validateConfiguration(ctx, "create connector", connDef, connector.Configuration, "connector.configuration"); err != nil {
 		return nil, err
 	}
 
-	if strings.Contains(connDef.GetId(), "http") || strings.Contains(connDef.GetId(), "grpc") {
-		// User desire state = CONNECTED
-		if err := s.repository.UpdateConnectorStateByID(ctx, connector.ID, connector.Owner, datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED)); err != nil {
-			return nil, err
-		}
-		if err := s.UpdateResourceState(connector.UID, connectorPB.Connector_STATE_CONNECTED, nil); err != nil {
-			return nil, err
-		}
-	} else {
-		// User desire state = DISCONNECTED
-		if err := s.repository.UpdateConnectorStateByID(ctx, connector.ID, connector.Owner, datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED)); err != nil {
-			return nil, err
+	return connDef, nil
+}
+
+// validateConfiguration applies the defaults of the connection specification
+// of the definition to the configuration and validates it. Every violation is
+// reported under field, e.g., connector.configuration.engine. The
+// configuration to store is returned.
+func (s *service) validateConfiguration(ctx context.Context, operation string, connDef *connectorPB.ConnectorDefinition, configuration []byte, field string) ([]byte, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	badRequest := func(violations []*errdetails.BadRequest_FieldViolation) error {
+		st, err := sterr.CreateErrorBadRequest(
+			fmt.Sprintf("[service] %s", operation),
+			violations,
+		)
+		if err != nil {
+			logger.Error(err.Error())
 		}
-		if err := s.UpdateResourceState(connector.UID, connectorPB.Connector_STATE_DISCONNECTED, nil); err != nil {
-			return nil, err
+		return st.Err()
+	}
+
+	values := map[string]interface{}{}
+	if len(configuration) > 0 {
+		if err := json.Unmarshal(configuration, &values); err != nil {
+			return nil, badRequest([]*errdetails.BadRequest_FieldViolation{{Field: field, Description: err.Error()}})
 		}
 	}
 
-	dbConnector, err := s.repository.GetConnectorByID(ctx, connector.ID, ownerPermalink, false)
+	connector.ApplyConfigurationDefaults(connDef, values)
+
+	violations, err := connector.ValidateConfiguration(connDef, values)
 	if err != nil {
 		return nil, err
 	}
+	if len(violations) > 0 {
+		fieldViolations := make([]*errdetails.BadRequest_FieldViolation, len(violations))
+		for idx, violation := range violations {
+			fieldViolations[idx] = &errdetails.BadRequest_FieldViolation{
+				Field:       strings.TrimSuffix(fmt.Sprintf("%s.%s", field, connector.FieldPath(violation.Pointer)), "."),
+				Description: violation.Description,
+			}
+		}
+		return nil, badRequest(fieldViolations)
+	}
 
-	return dbConnector, nil
+	return json.Marshal(values)
+}
 
+// initialConnectorState returns the state of a newly created connector
+func initialConnectorState(connDef *connectorPB.ConnectorDefinition) connectorPB.Connector_State {
+	if strings.Contains(connDef.GetId(), "http") || strings.Contains(connDef.GetId(), "grpc") {
+		return connectorPB.Connector_STATE_CONNECTED
+	}
+	return connectorPB.Connector_STATE_DISCONNECTED
 }
 
 func (s *service) ListConnectors(ctx context.Context, owner *mgmtPB.User, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter) ([]*datamodel.Connector, int64, string, error) {
@@ -269,12 +373,18 @@ func (s *service) UpdateConnector(ctx context.Context, id string, owner *mgmtPB.
 		return nil, st.Err()
 	}
 
+	if updatedConnector.Configuration != nil {
+		if updatedConnector.Configuration, err = s.validateConfiguration(ctx, "update connector", def, updatedConnector.Configuration, "connector.configuration"); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.repository.UpdateConnector(ctx, id, ownerPermalink, updatedConnector); err != nil {
 		return nil, err
 	}
 
 	// Check connector state
-	if err := s.UpdateResourceState(updatedConnector.UID, connectorPB.Connector_STATE_DISCONNECTED, nil); err != nil {
+	if err := s.UpdateResourceState(ctx, updatedConnector.UID, connectorPB.Connector_STATE_DISCONNECTED, nil); err != nil {
 		return nil, err
 	}
 
@@ -287,7 +397,6 @@ func (s *service) UpdateConnector(ctx context.Context, id string, owner *mgmtPB.
 }
 
 func (s *service) DeleteConnector(ctx context.Context, id string, owner *mgmtPB.User) error {
-	logger, _ := logger.GetZapLogger(ctx)
 
 	ownerPermalink := GenOwnerPermalink(owner)
 
@@ -296,9 +405,26 @@ func (s *service) DeleteConnector(ctx context.Context, id string, owner *mgmtPB.
 		return err
 	}
 
+	if err := s.checkConnectorNotInUse(ctx, owner, dbConnector); err != nil {
+		return err
+	}
+
+	if err := s.DeleteResourceState(ctx, dbConnector.UID); err != nil {
+		return err
+	}
+
+	return s.repository.DeleteConnector(ctx, id, ownerPermalink)
+}
+
+// checkConnectorNotInUse returns a precondition failure if any pipeline of
+// the owner still uses the connector
+func (s *service) checkConnectorNotInUse(ctx context.Context, owner *mgmtPB.User, dbConnector *datamodel.Connector) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
 	filter := fmt.Sprintf("recipe.components.resource_name:\"connectors/%s\"", dbConnector.UID)
 
-	pipeResp, err := s.pipelinePublicServiceClient.ListPipelines(InjectOwnerToContext(context.Background(), owner), &pipelinePB.ListPipelinesRequest{
+	pipeResp, err := s.pipelinePublicServiceClient.ListPipelines(InjectOwnerToContext(ctx, owner), &pipelinePB.ListPipelinesRequest{
 		Filter: &filter,
 	})
 	if err != nil {
@@ -315,7 +441,7 @@ func (s *service) DeleteConnector(ctx context.Context, id string, owner *mgmtPB.
 			[]*errdetails.PreconditionFailure_Violation{
 				{
 					Type:        "DELETE",
-					Subject:     fmt.Sprintf("id %s", id),
+					Subject:     fmt.Sprintf("id %s", dbConnector.ID),
 					Description: fmt.Sprintf("The connector is still in use by pipeline: %s", strings.Join(pipeIDs, " ")),
 				},
 			})
@@ -325,31 +451,80 @@ func (s *service) DeleteConnector(ctx context.Context, id string, owner *mgmtPB.
 		return st.Err()
 	}
 
-	if err := s.DeleteResourceState(dbConnector.UID); err != nil {
-		return err
-	}
-
-	return s.repository.DeleteConnector(ctx, id, ownerPermalink)
+	return nil
 }
 
 func (s *service) UpdateConnectorState(ctx context.Context, id string, ownerPermalink string, state datamodel.ConnectorState) (*datamodel.Connector, error) {
 
-	logger, _ := logger.GetZapLogger(ctx)
-
-	// Validation: HTTP and gRPC connector cannot be disconnected
 	conn, err := s.repository.GetConnectorByID(ctx, id, ownerPermalink, false)
 	if err != nil {
 		return nil, err
 	}
 
+	change, err := s.prepareConnectorState(ctx, ownerPermalink, conn, state)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyConnectorState(ctx, s.repository, change); err != nil {
+		return nil, err
+	}
+	if err := s.applyResourceState(ctx, change); err != nil {
+		return nil, err
+	}
+
+	dbConnector, err := s.repository.GetConnectorByID(ctx, id, ownerPermalink, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbConnector, nil
+}
+
+// connectorStateChange is a validated connector state transition
+type connectorStateChange struct {
+	ownerPermalink string
+	conn           *datamodel.Connector
+	state          datamodel.ConnectorState
+	taskName       string
+	noop           bool
+}
+
+// prepareConnectorState validates the state transition of a connector of
+// ownerPermalink and, when connecting, establishes the connection to fetch the
+// connector task
+func (s *service) prepareConnectorState(ctx context.Context, ownerPermalink string, conn *datamodel.Connector, state datamodel.ConnectorState) (*connectorStateChange, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	// The public connectors of other owners are visible but not writable
+	if conn.Owner != ownerPermalink {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.PermissionDenied,
+			"[service] update connector state",
+			"connectors",
+			fmt.Sprintf("id %s", conn.ID),
+			conn.Owner,
+			"Permission denied",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
 	connDef, err := s.connectorAll.GetConnectorDefinitionByUid(conn.ConnectorDefinitionUID)
 	if err != nil {
 		return nil, err
 	}
 
+	change := &connectorStateChange{ownerPermalink: ownerPermalink, conn: conn, state: state}
+
 	switch state {
 	case datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED):
+		// HTTP and gRPC connectors are always connected
 		if strings.Contains(connDef.GetId(), "http") || strings.Contains(connDef.GetId(), "grpc") {
+			change.noop = true
 			break
 		}
 
@@ -366,37 +541,21 @@ func (s *service) UpdateConnectorState(ctx context.Context, id string, ownerPerm
 		}()
 
 		con, err := s.connectorAll.CreateConnection(conn.ConnectorDefinitionUID, configuration, logger)
-
 		if err != nil {
 			return nil, err
 		}
 
-		taskName, _ := con.GetTaskName()
-		if err != nil {
-			return nil, err
-		}
-
-		// Set connector state to user desire state
-		if err := s.repository.UpdateConnectorStateByID(ctx, id, ownerPermalink, datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED)); err != nil {
-			return nil, err
-		}
-
-		if err := s.repository.UpdateConnectorTaskByID(ctx, id, ownerPermalink, taskName); err != nil {
-			return nil, err
-		}
-		if err := s.UpdateResourceState(conn.UID, connectorPB.Connector_STATE_CONNECTED, nil); err != nil {
-			return nil, err
-		}
+		change.taskName, _ = con.GetTaskName()
 
 	case datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED):
-
+		// Validation: HTTP and gRPC connector cannot be disconnected
 		if strings.Contains(connDef.GetId(), "http") || strings.Contains(connDef.GetId(), "grpc") {
 			st, err := sterr.CreateErrorPreconditionFailure(
 				"[service] update connector state",
 				[]*errdetails.PreconditionFailure_Violation{
 					{
 						Type:        "STATE",
-						Subject:     fmt.Sprintf("id %s", id),
+						Subject:     fmt.Sprintf("id %s", conn.ID),
 						Description: fmt.Sprintf("Cannot disconnect a %s connector", connDef.GetId()),
 					},
 				})
@@ -406,20 +565,39 @@ func (s *service) UpdateConnectorState(ctx context.Context, id string, ownerPerm
 			return nil, st.Err()
 		}
 
-		if err := s.repository.UpdateConnectorStateByID(ctx, id, ownerPermalink, datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED)); err != nil {
-			return nil, err
-		}
-		if err := s.UpdateResourceState(conn.UID, connectorPB.Connector_State(state), nil); err != nil {
-			return nil, err
-		}
+	default:
+		change.noop = true
 	}
 
-	dbConnector, err := s.repository.GetConnectorByID(ctx, id, ownerPermalink, false)
-	if err != nil {
-		return nil, err
+	return change, nil
+}
+
+// applyConnectorState writes a prepared state transition to the repository
+func applyConnectorState(ctx context.Context, r repository.Repository, change *connectorStateChange) error {
+	if change.noop {
+		return nil
 	}
 
-	return dbConnector, nil
+	// Set connector state to user desire state
+	if err := r.UpdateConnectorStateByID(ctx, change.conn.ID, change.ownerPermalink, change.state); err != nil {
+		return err
+	}
+
+	if change.state == datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED) {
+		if err := r.UpdateConnectorTaskByID(ctx, change.conn.ID, change.ownerPermalink, change.taskName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyResourceState sends a prepared state transition to the controller
+func (s *service) applyResourceState(ctx context.Context, change *connectorStateChange) error {
+	if change.noop {
+		return nil
+	}
+	return s.UpdateResourceState(ctx, change.conn.UID, connectorPB.Connector_State(change.state), nil)
 }
 
 func (s *service) UpdateConnectorID(ctx context.Context, id string, owner *mgmtPB.User, newID string) (*datamodel.Connector, error) {
@@ -475,6 +653,129 @@ func (s *service) UpdateConnectorID(ctx context.Context, id string, owner *mgmtP
 	return dbConnector, nil
 }
 
+// CloneConnector copies the connector id to newID of targetOwner. The clone
+// keeps the visibility of the connector if visibility is unspecified.
+func (s *service) CloneConnector(ctx context.Context, id string, owner *mgmtPB.User, newID string, targetOwner *mgmtPB.User, visibility datamodel.ConnectorVisibility) (*datamodel.Connector, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ownerPermalink := GenOwnerPermalink(owner)
+	targetOwnerPermalink := GenOwnerPermalink(targetOwner)
+
+	if !CanWriteOwner(owner, targetOwnerPermalink) {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.PermissionDenied,
+			"[service] clone connector",
+			"connectors",
+			fmt.Sprintf("id %s", newID),
+			targetOwnerPermalink,
+			"Permission denied",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	srcConnector, err := s.repository.GetConnectorByID(ctx, id, ownerPermalink, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stored credentials are copied as well, so only the owner is allowed
+	// to clone a connector, even if it is visible to others
+	if srcConnector.Owner != ownerPermalink && !IsAdmin(owner) {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.PermissionDenied,
+			"[service] clone connector",
+			"connectors",
+			fmt.Sprintf("id %s", id),
+			srcConnector.Owner,
+			"Permission denied",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	connDef, err := s.connectorAll.GetConnectorDefinitionByUid(srcConnector.ConnectorDefinitionUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validation: HTTP and gRPC connectors cannot be cloned
+	if strings.Contains(connDef.GetId(), "http") || strings.Contains(connDef.GetId(), "grpc") {
+		st, err := sterr.CreateErrorPreconditionFailure(
+			"[service] clone connector",
+			[]*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "CLONE",
+					Subject:     fmt.Sprintf("id %s", id),
+					Description: fmt.Sprintf("Cannot clone a %s connector", connDef.GetId()),
+				},
+			})
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	if err := s.checkConnectorDefinitionPolicy(ctx, connDef); err != nil {
+		return nil, err
+	}
+
+	configuration, err := s.validateConfiguration(ctx, "clone connector", connDef, srcConnector.Configuration, "connector.configuration")
+	if err != nil {
+		return nil, err
+	}
+
+	if visibility == datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_UNSPECIFIED) {
+		visibility = srcConnector.Visibility
+	}
+
+	clonedConnector := &datamodel.Connector{
+		ID:                     newID,
+		Owner:                  targetOwnerPermalink,
+		ConnectorDefinitionUID: srcConnector.ConnectorDefinitionUID,
+		Description:            srcConnector.Description,
+		Tombstone:              false,
+		Configuration:          configuration,
+		ConnectorType:          srcConnector.ConnectorType,
+		Visibility:             visibility,
+		Task:                   srcConnector.Task,
+	}
+
+	// A cloned connector always starts disconnected
+	var dbConnector *datamodel.Connector
+	if err := s.repository.Transaction(ctx, func(r repository.Repository) error {
+		dbConnector, err = s.createConnectorWithState(ctx, r, clonedConnector, connectorPB.Connector_STATE_DISCONNECTED)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return dbConnector, nil
+}
+
+// createConnectorWithState stores the connector in r with the state and sends
+// the state to the controller. It runs in a transaction of r, so that the
+// connector is rolled back if the controller fails.
+func (s *service) createConnectorWithState(ctx context.Context, r repository.Repository, connector *datamodel.Connector, state connectorPB.Connector_State) (*datamodel.Connector, error) {
+
+	if err := r.CreateConnector(ctx, connector); err != nil {
+		return nil, err
+	}
+	if err := r.UpdateConnectorStateByID(ctx, connector.ID, connector.Owner, datamodel.ConnectorState(state)); err != nil {
+		return nil, err
+	}
+	if err := s.UpdateResourceState(ctx, connector.UID, state, nil); err != nil {
+		return nil, err
+	}
+
+	return r.GetConnectorByID(ctx, connector.ID, connector.Owner, false)
+}
+
 func (s *service) Execute(ctx context.Context, id string, owner *mgmtPB.User, inputs []*connectorPB.DataPayload) ([]*connectorPB.DataPayload, error) {
 
 	logger, _ := logger.GetZapLogger(ctx)
@@ -503,17 +804,50 @@ func (s *service) Execute(ctx context.Context, id string, owner *mgmtPB.User, in
 		return nil, err
 	}
 
-	return con.Execute(inputs)
+	metering := config.Current().Server.Metering.Enabled
+	execution := executionOf(inputs)
+	if metering {
+		if err := s.checkExecutionQuota(ctx, owner, execution); err != nil {
+			return nil, err
+		}
+	}
+
+	connDefID := conn.ConnectorDefinitionUID.String()
+	if connDef, err := s.connectorAll.GetConnectorDefinitionByUid(conn.ConnectorDefinitionUID); err == nil {
+		connDefID = connDef.GetId()
+	}
+
+	start := time.Now()
+	outputs, err := con.Execute(inputs)
+	custom_otel.RecordConnectorExecution(ctx, connDefID, conn.Task, len(inputs), time.Since(start), err)
+
+	// Failed executions are metered as well, they reach the connector all the same
+	if metering {
+		if meterErr := s.meterExecution(ctx, ownerPermalink, conn, execution); meterErr != nil {
+			logger.Error(fmt.Sprintf("meter execution of connector %s: %s", conn.ID, meterErr.Error()))
+		}
+	}
+
+	return outputs, err
 }
 
-func (s *service) CheckConnectorByUID(ctx context.Context, connUID uuid.UUID) (*connectorPB.Connector_State, error) {
+func (s *service) CheckConnectorByUID(ctx context.Context, connUID uuid.UUID) (checked *connectorPB.Connector_State, err error) {
 
 	logger, _ := logger.GetZapLogger(ctx)
 
+	connDefID := ""
+	defer func() {
+		custom_otel.RecordConnectorCheck(ctx, connDefID, checked.String())
+	}()
+
 	dbConnector, err := s.repository.GetConnectorByUIDAdmin(ctx, connUID, false)
 	if err != nil {
 		return connectorPB.Connector_STATE_UNSPECIFIED.Enum(), nil
 	}
+	connDefID = dbConnector.ConnectorDefinitionUID.String()
+	if connDef, err := s.connectorAll.GetConnectorDefinitionByUid(dbConnector.ConnectorDefinitionUID); err == nil {
+		connDefID = connDef.GetId()
+	}
 
 	configuration := func() *structpb.Struct {
 		if dbConnector.Configuration != nil {
@@ -541,17 +875,17 @@ func (s *service) CheckConnectorByUID(ctx context.Context, connUID uuid.UUID) (*
 
 	switch state {
 	case connectorPB.Connector_STATE_CONNECTED:
-		if err := s.UpdateResourceState(dbConnector.UID, connectorPB.Connector_STATE_CONNECTED, nil); err != nil {
+		if err := s.UpdateResourceState(ctx, dbConnector.UID, connectorPB.Connector_STATE_CONNECTED, nil); err != nil {
 			return connectorPB.Connector_STATE_UNSPECIFIED.Enum(), nil
 		}
 		return connectorPB.Connector_STATE_CONNECTED.Enum(), nil
 	case connectorPB.Connector_STATE_ERROR:
-		if err := s.UpdateResourceState(dbConnector.UID, connectorPB.Connector_STATE_ERROR, nil); err != nil {
+		if err := s.UpdateResourceState(ctx, dbConnector.UID, connectorPB.Connector_STATE_ERROR, nil); err != nil {
 			return connectorPB.Connector_STATE_UNSPECIFIED.Enum(), nil
 		}
 		return connectorPB.Connector_STATE_ERROR.Enum(), nil
 	default:
-		if err := s.UpdateResourceState(dbConnector.UID, connectorPB.Connector_STATE_ERROR, nil); err != nil {
+		if err := s.UpdateResourceState(ctx, dbConnector.UID, connectorPB.Connector_STATE_ERROR, nil); err != nil {
 			return connectorPB.Connector_STATE_UNSPECIFIED.Enum(), nil
 		}
 		return connectorPB.Connector_STATE_ERROR.Enum(), nil
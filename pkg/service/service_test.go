@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/pkg/bundle"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+
+	connectorBase "github.com/instill-ai/connector/pkg/base"
+	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
+)
+
+const (
+	ownerUID      = "2a06c2f7-8da9-4046-91ea-240f88a5d000"
+	otherOwnerUID = "2a06c2f7-8da9-4046-91ea-240f88a5d001"
+)
+
+func newTestUser(uid string) *mgmtPB.User {
+	return &mgmtPB.User{Uid: &uid}
+}
+
+func newTestConnector(ownerPermalink string, id string, visibility connectorPB.Connector_Visibility) *datamodel.Connector {
+	return &datamodel.Connector{
+		ID:                     id,
+		Owner:                  ownerPermalink,
+		ConnectorDefinitionUID: uuid.Must(uuid.NewV4()),
+		Configuration:          []byte(`{}`),
+		ConnectorType:          datamodel.ConnectorType(connectorPB.ConnectorType_CONNECTOR_TYPE_DESTINATION),
+		State:                  datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED),
+		Visibility:             datamodel.ConnectorVisibility(visibility),
+		Task:                   "TASK_UNSPECIFIED",
+	}
+}
+
+func TestUpdateConnectorStateOfOtherOwner(t *testing.T) {
+	ctx := context.Background()
+	r := repository.NewMemoryRepository()
+	s := &service{repository: r}
+
+	otherOwnerPermalink := GenOwnerPermalink(newTestUser(otherOwnerUID))
+	if err := r.CreateConnector(ctx, newTestConnector(otherOwnerPermalink, "public", connectorPB.Connector_VISIBILITY_PUBLIC)); err != nil {
+		t.Fatalf("create connector: %v", err)
+	}
+
+	ownerPermalink := GenOwnerPermalink(newTestUser(ownerUID))
+	disconnected := datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED)
+	if _, err := s.UpdateConnectorState(ctx, "public", ownerPermalink, disconnected); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("disconnect the public connector of another owner: got %v, want PermissionDenied", err)
+	}
+	results, err := s.BatchUpdateConnectorState(ctx, newTestUser(ownerUID), []string{"public"}, disconnected, false)
+	if err != nil {
+		t.Fatalf("batch disconnect: %v", err)
+	}
+	if status.Code(results[0].Err) != codes.PermissionDenied {
+		t.Fatalf("batch disconnect the public connector of another owner: got %v, want PermissionDenied", results[0].Err)
+	}
+
+	conn, err := r.GetConnectorByID(ctx, "public", otherOwnerPermalink, false)
+	if err != nil {
+		t.Fatalf("get connector: %v", err)
+	}
+	if conn.State != datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED) {
+		t.Fatalf("state of the public connector of another owner: got %v, want STATE_CONNECTED", conn.State)
+	}
+}
+
+// fakeConnectors serves a fixed set of connector definitions
+type fakeConnectors struct {
+	connectorBase.IConnector
+	definitions []*connectorPB.ConnectorDefinition
+	credentials map[string]bool
+}
+
+func (c *fakeConnectors) GetConnectorDefinitionByUid(defUID uuid.UUID) (*connectorPB.ConnectorDefinition, error) {
+	for _, def := range c.definitions {
+		if def.GetUid() == defUID.String() {
+			return def, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "connector definition %s not found", defUID)
+}
+
+func (c *fakeConnectors) GetConnectorDefinitionById(defID string) (*connectorPB.ConnectorDefinition, error) {
+	for _, def := range c.definitions {
+		if def.GetId() == defID {
+			return def, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "connector definition %s not found", defID)
+}
+
+func (c *fakeConnectors) IsCredentialField(defID string, target string) bool {
+	return c.credentials[target]
+}
+
+// fakeController records the connector states sent to the controller, or
+// fails with err if set
+type fakeController struct {
+	controllerPB.ControllerPrivateServiceClient
+	states map[string]connectorPB.Connector_State
+	err    error
+}
+
+func (c *fakeController) UpdateResource(ctx context.Context, in *controllerPB.UpdateResourceRequest, opts ...grpc.CallOption) (*controllerPB.UpdateResourceResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.states[in.GetResource().GetResourcePermalink()] = in.GetResource().GetConnectorState()
+	return &controllerPB.UpdateResourceResponse{}, nil
+}
+
+func (c *fakeController) DeleteResource(ctx context.Context, in *controllerPB.DeleteResourceRequest, opts ...grpc.CallOption) (*controllerPB.DeleteResourceResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	delete(c.states, in.GetResourcePermalink())
+	return &controllerPB.DeleteResourceResponse{}, nil
+}
+
+func newTestService(t *testing.T) (*service, *connectorPB.ConnectorDefinition, *fakeController) {
+	t.Helper()
+	connDef := &connectorPB.ConnectorDefinition{
+		Uid:           uuid.Must(uuid.NewV4()).String(),
+		Id:            "destination-test",
+		ConnectorType: connectorPB.ConnectorType_CONNECTOR_TYPE_DESTINATION,
+	}
+	controller := &fakeController{states: map[string]connectorPB.Connector_State{}}
+	return &service{
+		repository:       repository.NewMemoryRepository(),
+		controllerClient: controller,
+		connectorAll: &fakeConnectors{
+			definitions: []*connectorPB.ConnectorDefinition{connDef},
+			credentials: map[string]bool{"api_key": true},
+		},
+	}, connDef, controller
+}
+
+func TestImportConnectorsDryRunOfEmptyBundle(t *testing.T) {
+	s, _, _ := newTestService(t)
+	results, applied, err := s.ImportConnectors(context.Background(), newTestUser(ownerUID), &bundle.Bundle{Version: bundle.Version}, bundle.ConflictFail, true)
+	if err != nil {
+		t.Fatalf("import connectors: %v", err)
+	}
+	if applied || len(results) != 0 {
+		t.Fatalf("dry run of an empty bundle: got applied=%v and %d results, want nothing applied", applied, len(results))
+	}
+}
+
+func TestImportConnectorsIgnoresPublicConnectorsOfOtherOwners(t *testing.T) {
+	ctx := context.Background()
+	s, connDef, _ := newTestService(t)
+
+	otherOwnerPermalink := GenOwnerPermalink(newTestUser(otherOwnerUID))
+	public := newTestConnector(otherOwnerPermalink, "shared", connectorPB.Connector_VISIBILITY_PUBLIC)
+	public.ConnectorDefinitionUID = uuid.FromStringOrNil(connDef.GetUid())
+	public.Configuration = []byte(`{"api_key": "secret of the other owner"}`)
+	if err := s.repository.CreateConnector(ctx, public); err != nil {
+		t.Fatalf("create connector: %v", err)
+	}
+
+	b := &bundle.Bundle{
+		Version: bundle.Version,
+		Connectors: []*bundle.Connector{{
+			ConnectorDefinition: connDef.GetId(),
+			ID:                  "shared",
+			Configuration:       map[string]interface{}{},
+		}},
+	}
+	for _, strategy := range []bundle.ConflictStrategy{bundle.ConflictFail, bundle.ConflictOverwrite, bundle.ConflictRename} {
+		results, _, err := s.ImportConnectors(ctx, newTestUser(ownerUID), b, strategy, true)
+		if err != nil {
+			t.Fatalf("import connectors with strategy %v: %v", strategy, err)
+		}
+		result := results[0]
+		if result.Err != nil || result.Conflict || result.Action != bundle.ActionCreate || result.ConnectorID != "shared" {
+			t.Fatalf("import with strategy %v: got action %v, conflict %v, id %s and error %v, want a creation without conflict",
+				strategy, result.Action, result.Conflict, result.ConnectorID, result.Err)
+		}
+	}
+
+	results, applied, err := s.ImportConnectors(ctx, newTestUser(ownerUID), b, bundle.ConflictOverwrite, false)
+	if err != nil || !applied || results[0].Err != nil {
+		t.Fatalf("import connectors: got applied=%v, %v and %v", applied, err, results[0].Err)
+	}
+	if string(results[0].Connector.Configuration) != `{}` {
+		t.Fatalf("configuration of the imported connector: got %s, want {}", results[0].Connector.Configuration)
+	}
+}
+
+func TestImportConnectorsOverwriteDisconnects(t *testing.T) {
+	ctx := context.Background()
+	s, connDef, controller := newTestService(t)
+
+	ownerPermalink := GenOwnerPermalink(newTestUser(ownerUID))
+	existing := newTestConnector(ownerPermalink, "conn", connectorPB.Connector_VISIBILITY_PRIVATE)
+	existing.ConnectorDefinitionUID = uuid.FromStringOrNil(connDef.GetUid())
+	if err := s.repository.CreateConnector(ctx, existing); err != nil {
+		t.Fatalf("create connector: %v", err)
+	}
+
+	b := &bundle.Bundle{
+		Version: bundle.Version,
+		Connectors: []*bundle.Connector{{
+			ConnectorDefinition: connDef.GetId(),
+			ID:                  "conn",
+			Configuration:       map[string]interface{}{},
+		}},
+	}
+	results, applied, err := s.ImportConnectors(ctx, newTestUser(ownerUID), b, bundle.ConflictOverwrite, false)
+	if err != nil || !applied || results[0].Err != nil {
+		t.Fatalf("import connectors: got applied=%v, %v and %v", applied, err, results[0].Err)
+	}
+	if results[0].Action != bundle.ActionOverwrite {
+		t.Fatalf("action: got %v, want %v", results[0].Action, bundle.ActionOverwrite)
+	}
+	if results[0].Connector.State != datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED) {
+		t.Fatalf("state of the overwritten connector: got %v, want STATE_DISCONNECTED", results[0].Connector.State)
+	}
+	if state := controller.states[utils.ConvertConnectorToResourceName(existing.UID.String())]; state != connectorPB.Connector_STATE_DISCONNECTED {
+		t.Fatalf("controller state of the overwritten connector: got %v, want STATE_DISCONNECTED", state)
+	}
+}
+
+// unavailableRepository fails the connector lookups
+type unavailableRepository struct {
+	repository.Repository
+}
+
+func (r *unavailableRepository) GetConnectorByID(ctx context.Context, id string, ownerPermalink string, isBasicView bool) (*datamodel.Connector, error) {
+	return nil, status.Error(codes.Unavailable, "database unavailable")
+}
+
+func TestImportConnectorsLookupError(t *testing.T) {
+	s, connDef, _ := newTestService(t)
+	s.repository = &unavailableRepository{Repository: s.repository}
+
+	b := &bundle.Bundle{
+		Version:    bundle.Version,
+		Connectors: []*bundle.Connector{{ConnectorDefinition: connDef.GetId(), ID: "conn"}},
+	}
+	results, applied, err := s.ImportConnectors(context.Background(), newTestUser(ownerUID), b, bundle.ConflictFail, false)
+	if err != nil {
+		t.Fatalf("import connectors: %v", err)
+	}
+	if applied || status.Code(results[0].Err) != codes.Unavailable {
+		t.Fatalf("import with a failed lookup: got applied=%v and %v, want Unavailable", applied, results[0].Err)
+	}
+}
+
+func TestCloneConnectorVisibility(t *testing.T) {
+	ctx := context.Background()
+	s, connDef, _ := newTestService(t)
+
+	owner := newTestUser(ownerUID)
+	src := newTestConnector(GenOwnerPermalink(owner), "src", connectorPB.Connector_VISIBILITY_PUBLIC)
+	src.ConnectorDefinitionUID = uuid.FromStringOrNil(connDef.GetUid())
+	if err := s.repository.CreateConnector(ctx, src); err != nil {
+		t.Fatalf("create connector: %v", err)
+	}
+
+	tests := []struct {
+		id         string
+		visibility connectorPB.Connector_Visibility
+		want       connectorPB.Connector_Visibility
+	}{
+		{"same-visibility", connectorPB.Connector_VISIBILITY_UNSPECIFIED, connectorPB.Connector_VISIBILITY_PUBLIC},
+		{"private", connectorPB.Connector_VISIBILITY_PRIVATE, connectorPB.Connector_VISIBILITY_PRIVATE},
+	}
+	for _, tt := range tests {
+		clone, err := s.CloneConnector(ctx, "src", owner, tt.id, owner, datamodel.ConnectorVisibility(tt.visibility))
+		if err != nil {
+			t.Fatalf("clone connector to %s: %v", tt.id, err)
+		}
+		if clone.Visibility != datamodel.ConnectorVisibility(tt.want) {
+			t.Errorf("visibility of %s: got %v, want %v", tt.id, clone.Visibility, tt.want)
+		}
+		if clone.State != datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED) {
+			t.Errorf("state of %s: got %v, want STATE_DISCONNECTED", tt.id, clone.State)
+		}
+	}
+}
+
+func TestCloneConnectorControllerFailure(t *testing.T) {
+	ctx := context.Background()
+	s, connDef, controller := newTestService(t)
+
+	owner := newTestUser(ownerUID)
+	ownerPermalink := GenOwnerPermalink(owner)
+	src := newTestConnector(ownerPermalink, "src", connectorPB.Connector_VISIBILITY_PRIVATE)
+	src.ConnectorDefinitionUID = uuid.FromStringOrNil(connDef.GetUid())
+	if err := s.repository.CreateConnector(ctx, src); err != nil {
+		t.Fatalf("create connector: %v", err)
+	}
+
+	controller.err = status.Error(codes.Unavailable, "controller is down")
+	if _, err := s.CloneConnector(ctx, "src", owner, "clone", owner, datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_UNSPECIFIED)); status.Code(err) != codes.Unavailable {
+		t.Fatalf("clone connector with the controller down: got %v, want Unavailable", err)
+	}
+	// The clone is rolled back
+	if _, err := s.repository.GetConnectorByID(ctx, "clone", ownerPermalink, true); status.Code(err) != codes.NotFound {
+		t.Fatalf("get the clone of a failed clone: got %v, want NotFound", err)
+	}
+}
+
+func TestBatchCreateConnectorsControllerFailure(t *testing.T) {
+	ctx := context.Background()
+	s, connDef, controller := newTestService(t)
+
+	owner := newTestUser(ownerUID)
+	ownerPermalink := GenOwnerPermalink(owner)
+	newConnectors := func() []*datamodel.Connector {
+		connectors := []*datamodel.Connector{}
+		for _, id := range []string{"first", "second"} {
+			conn := newTestConnector(ownerPermalink, id, connectorPB.Connector_VISIBILITY_PRIVATE)
+			conn.ConnectorDefinitionUID = uuid.FromStringOrNil(connDef.GetUid())
+			connectors = append(connectors, conn)
+		}
+		return connectors
+	}
+
+	controller.err = status.Error(codes.Unavailable, "controller is down")
+	if _, err := s.BatchCreateConnectors(ctx, owner, newConnectors(), true); status.Code(err) != codes.Unavailable {
+		t.Fatalf("create all or nothing with the controller down: got %v, want Unavailable", err)
+	}
+	results, err := s.BatchCreateConnectors(ctx, owner, newConnectors(), false)
+	if err != nil {
+		t.Fatalf("create with the controller down: %v", err)
+	}
+	for idx, result := range results {
+		if status.Code(result.Err) != codes.Unavailable {
+			t.Fatalf("create item %d with the controller down: got %v, want Unavailable", idx, result.Err)
+		}
+	}
+	// The failed items are never stored
+	for _, id := range []string{"first", "second"} {
+		if _, err := s.repository.GetConnectorByID(ctx, id, ownerPermalink, true); status.Code(err) != codes.NotFound {
+			t.Fatalf("get connector %s of a failed batch: got %v, want NotFound", id, err)
+		}
+	}
+
+	controller.err = nil
+	results, err = s.BatchCreateConnectors(ctx, owner, newConnectors(), true)
+	if err != nil {
+		t.Fatalf("create all or nothing: %v", err)
+	}
+	for idx, result := range results {
+		if result.Err != nil || result.Connector.State != datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED) {
+			t.Fatalf("create item %d: got %+v", idx, result)
+		}
+		if state := controller.states[utils.ConvertConnectorToResourceName(result.Connector.UID.String())]; state != connectorPB.Connector_STATE_DISCONNECTED {
+			t.Fatalf("resource state of item %d: got %s, want STATE_DISCONNECTED", idx, state)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 
+	"github.com/instill-ai/connector-backend/config"
 	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
 	"google.golang.org/grpc/metadata"
 )
@@ -15,3 +16,18 @@ func InjectOwnerToContext(ctx context.Context, owner *mgmtPB.User) context.Conte
 	ctx = metadata.AppendToOutgoingContext(ctx, "Jwt-Sub", owner.GetUid())
 	return ctx
 }
+
+// IsAdmin returns true if the user is one of the configured server admins
+func IsAdmin(user *mgmtPB.User) bool {
+	for _, id := range config.Current().Server.Admins {
+		if user.GetId() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// CanWriteOwner returns true if the user is allowed to create or modify connectors owned by ownerPermalink
+func CanWriteOwner(user *mgmtPB.User, ownerPermalink string) bool {
+	return GenOwnerPermalink(user) == ownerPermalink || IsAdmin(user)
+}
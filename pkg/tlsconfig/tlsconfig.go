@@ -0,0 +1,172 @@
+// Package tlsconfig builds the TLS configurations of the outbound
+// connections. The CA bundle and the client certificate are reloaded when
+// their files change, e.g., when a mounted secret is rotated.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/instill-ai/connector-backend/config"
+)
+
+// New returns the TLS configuration of a connection to host, nil if the TLS
+// is disabled. The server certificate is verified against the CA bundle, or
+// the system roots if none, for the server name, or host if none.
+func New(cfg config.TLSConfig, host string) (*tls.Config, error) {
+
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	minVersion, err := parseVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	serverName := cfg.ServerName
+	if serverName == "" {
+		serverName = host
+	}
+
+	f := &files{certFile: cfg.Cert, keyFile: cfg.Key, caFile: cfg.CA}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: minVersion,
+		ServerName: serverName,
+	}
+	if f.certFile != "" {
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := f.current()
+			return cert, nil
+		}
+	}
+	if f.caFile != "" {
+		// The default verification uses a fixed pool, the server certificate
+		// is verified against the current CA bundle instead
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			_, roots := f.current()
+			return verify(cs, roots, serverName)
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+func parseVersion(v string) (uint16, error) {
+	switch v {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %s, expected 1.2 or 1.3", v)
+	}
+}
+
+func verify(cs tls.ConnectionState, roots *x509.CertPool, serverName string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("no server certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       serverName,
+	})
+	return err
+}
+
+// files holds the certificates loaded from the files, which are reloaded
+// when a file is modified
+type files struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu      sync.Mutex
+	modTime time.Time
+	cert    *tls.Certificate
+	roots   *x509.CertPool
+}
+
+// lastModTime returns the time the files were last modified
+func (f *files) lastModTime() (time.Time, error) {
+	var modTime time.Time
+	for _, name := range []string{f.certFile, f.keyFile, f.caFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	return modTime, nil
+}
+
+func (f *files) load() error {
+
+	modTime, err := f.lastModTime()
+	if err != nil {
+		return err
+	}
+
+	var cert *tls.Certificate
+	if f.certFile != "" {
+		c, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+		if err != nil {
+			return err
+		}
+		cert = &c
+	}
+
+	var roots *x509.CertPool
+	if f.caFile != "" {
+		b, err := os.ReadFile(f.caFile)
+		if err != nil {
+			return err
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(b) {
+			return fmt.Errorf("no certificate in the CA bundle %s", f.caFile)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.modTime = modTime
+	f.cert = cert
+	f.roots = roots
+	return nil
+}
+
+// current returns the client certificate and the CA bundle, reloaded if a
+// file was modified since the last load. The certificates loaded last are
+// kept if the files cannot be reloaded, e.g., while they are being rotated.
+func (f *files) current() (*tls.Certificate, *x509.CertPool) {
+	f.mu.Lock()
+	loaded := f.modTime
+	f.mu.Unlock()
+
+	if modTime, err := f.lastModTime(); err == nil && modTime.After(loaded) {
+		_ = f.load()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cert, f.roots
+}
@@ -0,0 +1,248 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/instill-ai/connector-backend/config"
+)
+
+// issuer signs the test certificates
+type issuer struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newIssuer(t *testing.T, name string) *issuer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &issuer{cert: cert, key: key}
+}
+
+// issue returns a certificate for the DNS name signed by the issuer
+func (i *issuer) issue(t *testing.T, dnsName string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, i.cert, &key.PublicKey, i.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func (i *issuer) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(i.cert)
+	return pool
+}
+
+// writePEM writes the PEM blocks to the file, whose modification time is
+// moved forward so that the reload does not depend on the file system
+// timestamp resolution
+func writePEM(t *testing.T, name string, blocks ...*pem.Block) {
+	t.Helper()
+	var b []byte
+	for _, block := range blocks {
+		b = append(b, pem.EncodeToMemory(block)...)
+	}
+	var modTime time.Time
+	if info, err := os.Stat(name); err == nil {
+		modTime = info.ModTime()
+	}
+	if err := os.WriteFile(name, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !modTime.IsZero() {
+		if err := os.Chtimes(name, modTime.Add(time.Second), modTime.Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func certBlock(cert *x509.Certificate) *pem.Block {
+	return &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}
+}
+
+// serve accepts TLS connections with the current server certificate and
+// writes a byte to the connections whose handshake succeeds
+func serve(t *testing.T, cert *atomic.Value, clientCAs *x509.CertPool) string {
+	t.Helper()
+	serverConfig := &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			c := cert.Load().(tls.Certificate)
+			return &c, nil
+		},
+	}
+	if clientCAs != nil {
+		serverConfig.ClientCAs = clientCAs
+		serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if err := conn.(*tls.Conn).Handshake(); err == nil {
+					_, _ = conn.Write([]byte{1})
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func dial(addr string, tlsConfig *tls.Config) error {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", addr, tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// The client certificate is verified by the server after the client
+	// handshake completes, the server only writes once it is accepted
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	return err
+}
+
+func TestNewDisabled(t *testing.T) {
+	tlsConfig, err := New(config.TLSConfig{}, "localhost")
+	if err != nil || tlsConfig != nil {
+		t.Fatalf("new disabled TLS config: got %v, %v, want nil", tlsConfig, err)
+	}
+}
+
+func TestNewInvalidMinVersion(t *testing.T) {
+	if _, err := New(config.TLSConfig{Enabled: true, MinVersion: "1.1"}, "localhost"); err == nil {
+		t.Fatalf("new TLS config with min version 1.1: got no error")
+	}
+}
+
+func TestCAReload(t *testing.T) {
+	dir := t.TempDir()
+	first, second := newIssuer(t, "first"), newIssuer(t, "second")
+
+	var serverCert atomic.Value
+	serverCert.Store(first.issue(t, "connector.test", x509.ExtKeyUsageServerAuth))
+	addr := serve(t, &serverCert, nil)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	writePEM(t, caFile, certBlock(first.cert))
+
+	tlsConfig, err := New(config.TLSConfig{Enabled: true, CA: caFile}, "connector.test")
+	if err != nil {
+		t.Fatalf("new TLS config: %v", err)
+	}
+	if err := dial(addr, tlsConfig); err != nil {
+		t.Fatalf("dial with the first CA: %v", err)
+	}
+
+	// The server certificate is rotated before the CA bundle
+	serverCert.Store(second.issue(t, "connector.test", x509.ExtKeyUsageServerAuth))
+	if err := dial(addr, tlsConfig); err == nil {
+		t.Fatalf("dial a server certificate of another CA: got no error")
+	}
+
+	writePEM(t, caFile, certBlock(first.cert), certBlock(second.cert))
+	if err := dial(addr, tlsConfig); err != nil {
+		t.Fatalf("dial with the reloaded CA bundle: %v", err)
+	}
+
+	// The server name is verified
+	other, err := New(config.TLSConfig{Enabled: true, CA: caFile}, "other.test")
+	if err != nil {
+		t.Fatalf("new TLS config: %v", err)
+	}
+	if err := dial(addr, other); err == nil {
+		t.Fatalf("dial with another server name: got no error")
+	}
+}
+
+func TestClientCertificateReload(t *testing.T) {
+	dir := t.TempDir()
+	serverCA, clientCA := newIssuer(t, "server"), newIssuer(t, "client")
+
+	var serverCert atomic.Value
+	serverCert.Store(serverCA.issue(t, "connector.test", x509.ExtKeyUsageServerAuth))
+	addr := serve(t, &serverCert, clientCA.pool())
+
+	caFile := filepath.Join(dir, "ca.pem")
+	writePEM(t, caFile, certBlock(serverCA.cert))
+
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	writeClient := func(cert tls.Certificate) {
+		t.Helper()
+		key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writePEM(t, keyFile, &pem.Block{Type: "PRIVATE KEY", Bytes: key})
+		writePEM(t, certFile, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	}
+
+	// A client certificate of an unknown CA is rejected by the server
+	writeClient(serverCA.issue(t, "client.test", x509.ExtKeyUsageClientAuth))
+	tlsConfig, err := New(config.TLSConfig{Enabled: true, CA: caFile, Cert: certFile, Key: keyFile}, "connector.test")
+	if err != nil {
+		t.Fatalf("new TLS config: %v", err)
+	}
+	if err := dial(addr, tlsConfig); err == nil {
+		t.Fatalf("dial with a client certificate of an unknown CA: got no error")
+	}
+
+	writeClient(clientCA.issue(t, "client.test", x509.ExtKeyUsageClientAuth))
+	if err := dial(addr, tlsConfig); err != nil {
+		t.Fatalf("dial with the reloaded client certificate: %v", err)
+	}
+}
@@ -0,0 +1,332 @@
+package usage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	spoolSegmentSuffix = ".spool"
+	spoolCursorFile    = "cursor"
+	// spoolRejectedFile keeps the reports which cannot be decoded, to be
+	// inspected, instead of blocking the reports behind them
+	spoolRejectedFile = "rejected"
+)
+
+// spoolRecord is a usage report waiting in the spool
+type spoolRecord struct {
+	CreateTime time.Time       `json:"create_time"`
+	UsageData  json.RawMessage `json:"usage_data"`
+}
+
+// spoolCursor is the position of the first record not yet sent
+type spoolCursor struct {
+	Segment int64 `json:"segment"`
+	Offset  int64 `json:"offset"`
+}
+
+// spool is an append-only queue of usage reports on disk. The reports are
+// appended as JSON lines to numbered segment files, a new segment is started
+// once the last one reaches segmentSize, and the oldest segments are dropped
+// when the spool exceeds maxSize. A cursor file records the position of the
+// first report not yet sent, so that the reports are sent once and in order
+// across restarts.
+type spool struct {
+	mu          sync.Mutex
+	dir         string
+	maxSize     int64
+	segmentSize int64
+	// segments are the sequence numbers of the segment files, in order
+	segments []int64
+	sizes    map[int64]int64
+	cursor   spoolCursor
+	queued   int64
+	dropped  int64
+}
+
+// openSpool opens the spool in dir, which is created if needed. A report
+// partially written by a crash is discarded.
+func openSpool(dir string, maxSize int64, segmentSize int64) (*spool, error) {
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &spool{
+		dir:         dir,
+		maxSize:     maxSize,
+		segmentSize: segmentSize,
+		sizes:       map[int64]int64{},
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, spoolSegmentSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseInt(strings.TrimSuffix(name, spoolSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		s.segments = append(s.segments, seq)
+	}
+	sort.Slice(s.segments, func(i, j int) bool {
+		return s.segments[i] < s.segments[j]
+	})
+
+	if b, err := os.ReadFile(filepath.Join(dir, spoolCursorFile)); err == nil {
+		if err := json.Unmarshal(b, &s.cursor); err != nil {
+			return nil, fmt.Errorf("spool cursor: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	// The segments before the cursor are already sent
+	for len(s.segments) > 0 && s.segments[0] < s.cursor.Segment {
+		if err := os.Remove(s.segmentPath(s.segments[0])); err != nil {
+			return nil, err
+		}
+		s.segments = s.segments[1:]
+	}
+	if len(s.segments) == 0 || s.segments[0] != s.cursor.Segment {
+		s.cursor = spoolCursor{}
+		if len(s.segments) > 0 {
+			s.cursor.Segment = s.segments[0]
+		}
+	}
+
+	for idx, seq := range s.segments {
+		b, err := os.ReadFile(s.segmentPath(seq))
+		if err != nil {
+			return nil, err
+		}
+		if idx == len(s.segments)-1 && len(b) > 0 && b[len(b)-1] != '\n' {
+			b = b[:bytes.LastIndexByte(b, '\n')+1]
+			if err := os.Truncate(s.segmentPath(seq), int64(len(b))); err != nil {
+				return nil, err
+			}
+		}
+		s.sizes[seq] = int64(len(b))
+		if seq == s.cursor.Segment {
+			if s.cursor.Offset > int64(len(b)) {
+				s.cursor.Offset = int64(len(b))
+			}
+			b = b[s.cursor.Offset:]
+		}
+		s.queued += int64(bytes.Count(b, []byte{'\n'}))
+	}
+
+	return s, nil
+}
+
+func (s *spool) segmentPath(seq int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, spoolSegmentSuffix))
+}
+
+// append writes the record at the end of the spool and drops the oldest
+// segments if the spool is then larger than maxSize
+func (s *spool) append(record *spoolRecord) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	if len(s.segments) == 0 || s.sizes[s.segments[len(s.segments)-1]] >= s.segmentSize {
+		seq := int64(1)
+		if len(s.segments) > 0 {
+			seq = s.segments[len(s.segments)-1] + 1
+		}
+		s.segments = append(s.segments, seq)
+		s.sizes[seq] = 0
+		if len(s.segments) == 1 {
+			s.cursor = spoolCursor{Segment: seq}
+		}
+	}
+	seq := s.segments[len(s.segments)-1]
+
+	f, err := os.OpenFile(s.segmentPath(seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	s.sizes[seq] += int64(len(b))
+	s.queued++
+
+	return s.enforceMaxSize()
+}
+
+// enforceMaxSize drops the oldest segments, but the one being appended to,
+// while the spool is larger than maxSize
+func (s *spool) enforceMaxSize() error {
+	total := int64(0)
+	for _, size := range s.sizes {
+		total += size
+	}
+	for total > s.maxSize && len(s.segments) > 1 {
+		seq := s.segments[0]
+		b, err := os.ReadFile(s.segmentPath(seq))
+		if err != nil {
+			return err
+		}
+		if seq == s.cursor.Segment {
+			b = b[s.cursor.Offset:]
+		}
+		n := int64(bytes.Count(b, []byte{'\n'}))
+		if err := os.Remove(s.segmentPath(seq)); err != nil {
+			return err
+		}
+		total -= s.sizes[seq]
+		delete(s.sizes, seq)
+		s.segments = s.segments[1:]
+		s.queued -= n
+		s.dropped += n
+		if err := s.saveCursor(spoolCursor{Segment: s.segments[0]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// peek returns the line of the first record not yet sent and the cursor
+// past it, nil if the spool is empty
+func (s *spool) peek() ([]byte, spoolCursor, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.segments) > 0 {
+		seq := s.segments[0]
+		if s.cursor.Offset < s.sizes[seq] {
+			f, err := os.Open(s.segmentPath(seq))
+			if err != nil {
+				return nil, s.cursor, err
+			}
+			defer f.Close()
+			if _, err := f.Seek(s.cursor.Offset, io.SeekStart); err != nil {
+				return nil, s.cursor, err
+			}
+			line, err := bufio.NewReader(f).ReadBytes('\n')
+			if err != nil {
+				return nil, s.cursor, err
+			}
+			return line, spoolCursor{Segment: seq, Offset: s.cursor.Offset + int64(len(line))}, nil
+		}
+		// The first segment is sent, it is removed unless appended to
+		if len(s.segments) == 1 {
+			break
+		}
+		if err := os.Remove(s.segmentPath(seq)); err != nil {
+			return nil, s.cursor, err
+		}
+		delete(s.sizes, seq)
+		s.segments = s.segments[1:]
+		if err := s.saveCursor(spoolCursor{Segment: s.segments[0]}); err != nil {
+			return nil, s.cursor, err
+		}
+	}
+
+	return nil, s.cursor, nil
+}
+
+// commit moves the cursor past the record returned by peek once it is sent
+func (s *spool) commit(cursor spoolCursor) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The segment of the record may have been dropped since
+	if cursor.Segment != s.cursor.Segment || cursor.Offset <= s.cursor.Offset {
+		return nil
+	}
+	if err := s.saveCursor(cursor); err != nil {
+		return err
+	}
+	s.queued--
+	return nil
+}
+
+// reject appends the line of a record returned by peek which cannot be
+// decoded to the rejected file, and moves the cursor past it. The record is
+// counted as dropped.
+func (s *spool) reject(line []byte, cursor spoolCursor) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cursor.Segment != s.cursor.Segment || cursor.Offset <= s.cursor.Offset {
+		return nil
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.dir, spoolRejectedFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := s.saveCursor(cursor); err != nil {
+		return err
+	}
+	s.queued--
+	s.dropped++
+	return nil
+}
+
+// saveCursor replaces the cursor file atomically
+func (s *spool) saveCursor(cursor spoolCursor) error {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(s.dir, spoolCursorFile+".tmp")
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, spoolCursorFile)); err != nil {
+		return err
+	}
+	s.cursor = cursor
+	return nil
+}
+
+// stats returns the number of reports queued and dropped since the spool was opened
+func (s *spool) stats() (queued int64, dropped int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queued, s.dropped
+}
@@ -0,0 +1,124 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	usagePB "github.com/instill-ai/protogen-go/base/usage/v1alpha"
+	usageReporter "github.com/instill-ai/usage-client/reporter"
+)
+
+// fakeReporter records the users of the reports sent
+type fakeReporter struct {
+	usageReporter.Reporter
+	users []string
+}
+
+func (r *fakeReporter) SingleReport(ctx context.Context, service usagePB.Session_Service, edition, version string, usageData interface{}) error {
+	for _, u := range usageData.(*usagePB.SessionReport_ConnectorUsageData).ConnectorUsageData.GetUsages() {
+		r.users = append(r.users, u.GetUserUid())
+	}
+	return nil
+}
+
+func appendReport(t *testing.T, s *spool, userUID string) {
+	t.Helper()
+	b, err := protojson.Marshal(&usagePB.ConnectorUsageData{
+		Usages: []*usagePB.ConnectorUsageData_UserUsageData{{UserUid: userUID}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.append(&spoolRecord{CreateTime: time.Now().UTC(), UsageData: b}); err != nil {
+		t.Fatalf("append report of %s: %v", userUID, err)
+	}
+}
+
+func TestFlushRejectsUndecodableReports(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, 1<<20, 1<<20)
+	if err != nil {
+		t.Fatalf("open spool: %v", err)
+	}
+	appendReport(t, s, "first")
+	// The usage data is not a connector usage
+	if err := s.append(&spoolRecord{CreateTime: time.Now().UTC(), UsageData: []byte(`{"unknown": 1}`)}); err != nil {
+		t.Fatalf("append report: %v", err)
+	}
+
+	// A line which is not a record, e.g., written by another version
+	f, err := os.OpenFile(s.segmentPath(s.segments[0]), os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("not a record\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if s, err = openSpool(dir, 1<<20, 1<<20); err != nil {
+		t.Fatalf("reopen spool: %v", err)
+	}
+	appendReport(t, s, "second")
+
+	reporter := &fakeReporter{}
+	u := &usage{spool: s, reporter: reporter}
+	if err := u.flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	if len(reporter.users) != 2 || reporter.users[0] != "first" || reporter.users[1] != "second" {
+		t.Fatalf("reports sent: got %v, want [first second]", reporter.users)
+	}
+	if queued, dropped := s.stats(); queued != 0 || dropped != 2 {
+		t.Fatalf("spool stats: got %d queued and %d dropped, want 0 and 2", queued, dropped)
+	}
+	rejected, err := os.ReadFile(filepath.Join(dir, spoolRejectedFile))
+	if err != nil {
+		t.Fatalf("read rejected reports: %v", err)
+	}
+	if lines := bytes.Split(bytes.TrimSuffix(rejected, []byte{'\n'}), []byte{'\n'}); len(lines) != 2 || string(lines[1]) != "not a record" {
+		t.Fatalf("rejected reports: got %q", rejected)
+	}
+}
+
+func TestSpoolCursorSurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(dir, 1<<20, 1<<20)
+	if err != nil {
+		t.Fatalf("open spool: %v", err)
+	}
+	appendReport(t, s, "sent")
+	appendReport(t, s, "queued")
+
+	line, next, err := s.peek()
+	if err != nil || line == nil {
+		t.Fatalf("peek: got %q, %v", line, err)
+	}
+	if err := s.commit(next); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	if s, err = openSpool(dir, 1<<20, 1<<20); err != nil {
+		t.Fatalf("reopen spool: %v", err)
+	}
+	if queued, _ := s.stats(); queued != 1 {
+		t.Fatalf("queued reports after reopen: got %d, want 1", queued)
+	}
+	line, _, err = s.peek()
+	if err != nil {
+		t.Fatalf("peek: %v", err)
+	}
+	usageData, err := decodeSpoolRecord(line)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got := usageData.GetUsages()[0].GetUserUid(); got != "queued" {
+		t.Fatalf("first report after reopen: got %s, want queued", got)
+	}
+}
@@ -2,19 +2,21 @@ package usage
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/gofrs/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+
 	"github.com/instill-ai/connector-backend/config"
-	"github.com/instill-ai/connector-backend/pkg/connector"
 	"github.com/instill-ai/connector-backend/pkg/datamodel"
 	"github.com/instill-ai/connector-backend/pkg/logger"
 	"github.com/instill-ai/connector-backend/pkg/repository"
 	"github.com/instill-ai/x/repo"
-	"go.einride.tech/aip/filtering"
 
-	connectorDestination "github.com/instill-ai/connector-destination/pkg"
-	connectorSource "github.com/instill-ai/connector-source/pkg"
 	connectorBase "github.com/instill-ai/connector/pkg/base"
 	mgmtPB "github.com/instill-ai/protogen-go/base/mgmt/v1alpha"
 	usagePB "github.com/instill-ai/protogen-go/base/usage/v1alpha"
@@ -23,24 +25,69 @@ import (
 	usageReporter "github.com/instill-ai/usage-client/reporter"
 )
 
+const (
+	// reportFrequency is the frequency of the usage reports, as in the usage client
+	reportFrequency = 10 * time.Minute
+	// reportTimeout bounds the calls to the usage server
+	reportTimeout = 30 * time.Second
+)
+
 // Usage interface
 type Usage interface {
 	RetrieveUsageData() interface{}
-	StartReporter(ctx context.Context)
+	RetrieveConnectorUsage(ctx context.Context) ([]*UserConnectorUsage, error)
+	RunReporter(ctx context.Context)
 	TriggerSingleReporter(ctx context.Context)
+	GetReporterStatus() *ReporterStatus
+}
+
+// ReporterStatus is the status of the usage reporter
+type ReporterStatus struct {
+	// LastSuccessTime is the time of the last report sent, zero if none
+	LastSuccessTime time.Time
+	// LastErrorTime is the time of the last failure, zero if none
+	LastErrorTime time.Time
+	// LastError is the error of the last failure
+	LastError string
+	// QueuedReports is the number of reports in the spool waiting to be sent
+	QueuedReports int64
+	// DroppedReports is the number of reports dropped from the full spool since the start
+	DroppedReports int64
+}
+
+// ConnectorTypeUsage counts the connectors of a user of a connector type
+type ConnectorTypeUsage struct {
+	// States counts the connectors by state
+	States map[connectorPB.Connector_State]int64
+	// Definitions counts the connectors by connector definition id
+	Definitions map[string]int64
+}
+
+// UserConnectorUsage counts the connectors of a user by connector type
+type UserConnectorUsage struct {
+	UserUID string
+	Types   map[connectorPB.ConnectorType]*ConnectorTypeUsage
 }
 
 type usage struct {
 	repository               repository.Repository
 	mgmtPrivateServiceClient mgmtPB.MgmtPrivateServiceClient
-	reporter                 usageReporter.Reporter
+	usageServiceClient       usagePB.UsageServiceClient
 	version                  string
-	connectorSource          connectorBase.IConnector
-	connectorDestination     connectorBase.IConnector
+	connectors               connectorBase.IConnector
+	spool                    *spool
+
+	// mu guards the reporter, created once the usage server is reachable,
+	// and the status, it serializes the flushes of the spool
+	mu       sync.Mutex
+	reporter usageReporter.Reporter
+	status   ReporterStatus
 }
 
-// NewUsage initiates a usage instance
-func NewUsage(ctx context.Context, r repository.Repository, ma mgmtPB.MgmtPrivateServiceClient, usc usagePB.UsageServiceClient) Usage {
+// NewUsage initiates a usage instance, the connectors of the reports are
+// resolved with the connector registry shared by the service. The reports are
+// spooled on disk and sent in order once the usage server is reachable.
+func NewUsage(ctx context.Context, r repository.Repository, ma mgmtPB.MgmtPrivateServiceClient, usc usagePB.UsageServiceClient, connectors connectorBase.IConnector) Usage {
 	logger, _ := logger.GetZapLogger(ctx)
 
 	version, err := repo.ReadReleaseManifest("release-please/manifest.json")
@@ -49,19 +96,20 @@ func NewUsage(ctx context.Context, r repository.Repository, ma mgmtPB.MgmtPrivat
 		return nil
 	}
 
-	reporter, err := usageClient.InitReporter(ctx, usc, usagePB.Session_SERVICE_CONNECTOR, config.Config.Server.Edition, version)
+	spoolConfig := config.Config.Server.Usage.Spool
+	spool, err := openSpool(spoolConfig.Dir, spoolConfig.MaxSize, spoolConfig.SegmentSize)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Error(fmt.Sprintf("unable to open the usage spool: %v", err))
 		return nil
 	}
 
 	return &usage{
 		repository:               r,
 		mgmtPrivateServiceClient: ma,
-		reporter:                 reporter,
+		usageServiceClient:       usc,
 		version:                  version,
-		connectorDestination:     connectorDestination.Init(logger, connector.GetConnectorDestinationOptions()),
-		connectorSource:          connectorSource.Init(logger),
+		connectors:               connectors,
+		spool:                    spool,
 	}
 }
 
@@ -72,204 +120,301 @@ func (u *usage) RetrieveUsageData() interface{} {
 
 	logger.Debug("Retrieve usage data...")
 
-	pbConnectorUsageData := []*usagePB.ConnectorUsageData_UserUsageData{}
-
-	// Roll over all users and update the metrics with the cached uuid
-	userPageToken := ""
-	userPageSizeMax := int64(repository.MaxPageSize)
-
-	var connType connectorPB.ConnectorType
-	declarations, err := filtering.NewDeclarations([]filtering.DeclarationOption{
-		filtering.DeclareStandardFunctions(),
-		filtering.DeclareEnumIdent("connector_type", connType.Type()),
-	}...)
+	userUsages, err := u.RetrieveConnectorUsage(ctx)
 	if err != nil {
 		logger.Error(fmt.Sprintf("%s", err))
 	}
 
-	var srcParser filtering.Parser
-	srcParser.Init("connector_type=CONNECTOR_TYPE_SOURCE")
-	srcParsedExpr, err := srcParser.Parse()
-	if err != nil {
-		logger.Error(fmt.Sprintf("%s", err))
+	// The usage payload has no field for the other connector types, which
+	// are logged when retrieved
+	pbConnectorUsageData := []*usagePB.ConnectorUsageData_UserUsageData{}
+	for _, userUsage := range userUsages {
+		src := userUsage.typeUsage(connectorPB.ConnectorType_CONNECTOR_TYPE_SOURCE)
+		dst := userUsage.typeUsage(connectorPB.ConnectorType_CONNECTOR_TYPE_DESTINATION)
+		pbConnectorUsageData = append(pbConnectorUsageData, &usagePB.ConnectorUsageData_UserUsageData{
+			UserUid:                                  userUsage.UserUID,
+			SourceConnectorConnectedStateNum:         src.States[connectorPB.Connector_STATE_CONNECTED],
+			SourceConnectorDisconnectedStateNum:      src.States[connectorPB.Connector_STATE_DISCONNECTED],
+			SourceConnectorDefinitionIds:             src.definitionIDs(),
+			DestinationConnectorConnectedStateNum:    dst.States[connectorPB.Connector_STATE_CONNECTED],
+			DestinationConnectorDisconnectedStateNum: dst.States[connectorPB.Connector_STATE_DISCONNECTED],
+			DestinationConnectorDefinitionIds:        dst.definitionIDs(),
+		})
 	}
-	var srcChecker filtering.Checker
-	srcChecker.Init(srcParsedExpr.Expr, srcParsedExpr.SourceInfo, declarations)
-	srcCheckedExpr, err := srcChecker.Check()
-	if err != nil {
-		logger.Error(fmt.Sprintf("%s", err))
+
+	logger.Debug("Send retrieved usage data...")
+
+	return &usagePB.SessionReport_ConnectorUsageData{
+		ConnectorUsageData: &usagePB.ConnectorUsageData{
+			Usages: pbConnectorUsageData,
+		},
 	}
+}
 
-	var dstParser filtering.Parser
-	dstParser.Init("connector_type=CONNECTOR_TYPE_DESTINATION")
-	dstParsedExpr, err := dstParser.Parse()
+// RetrieveConnectorUsage counts the connectors of every user by type, state
+// and definition, whatever the connector family. The counts come from a
+// single aggregate query joined with the users. As when listing the
+// connectors of a user, they include the public connectors of the others.
+func (u *usage) RetrieveConnectorUsage(ctx context.Context) ([]*UserConnectorUsage, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	counts, err := u.repository.AggregateConnectorUsage(ctx)
 	if err != nil {
-		logger.Error(fmt.Sprintf("%s", err))
+		return nil, err
 	}
-	var dstChecker filtering.Checker
-	dstChecker.Init(dstParsedExpr.Expr, dstParsedExpr.SourceInfo, declarations)
-	dstCheckedExpr, err := dstChecker.Check()
-	if err != nil {
-		logger.Error(fmt.Sprintf("%s", err))
+
+	byOwner := map[string][]*datamodel.ConnectorUsageCount{}
+	public := []*datamodel.ConnectorUsageCount{}
+	connDefIDs := map[uuid.UUID]string{}
+	totals := &UserConnectorUsage{
+		Types: map[connectorPB.ConnectorType]*ConnectorTypeUsage{},
+	}
+	for _, count := range counts {
+		byOwner[count.Owner] = append(byOwner[count.Owner], count)
+		if count.Visibility == datamodel.ConnectorVisibility(connectorPB.Connector_VISIBILITY_PUBLIC) {
+			public = append(public, count)
+		}
+		if _, ok := connDefIDs[count.ConnectorDefinitionUID]; !ok {
+			connDef, err := u.connectors.GetConnectorDefinitionByUid(count.ConnectorDefinitionUID)
+			if err != nil {
+				logger.Error(fmt.Sprintf("%s", err))
+			}
+			connDefIDs[count.ConnectorDefinitionUID] = connDef.GetId()
+		}
+		totals.count(connectorPB.ConnectorType(count.ConnectorType), connectorPB.Connector_State(count.State), connDefIDs[count.ConnectorDefinitionUID], count.Count)
+	}
+
+	for _, connType := range sortedConnectorTypes(totals.Types) {
+		total := totals.Types[connType]
+		logger.Info(fmt.Sprintf("%s usage: %d connected, %d disconnected, %d error, definitions %v",
+			connType,
+			total.States[connectorPB.Connector_STATE_CONNECTED],
+			total.States[connectorPB.Connector_STATE_DISCONNECTED],
+			total.States[connectorPB.Connector_STATE_ERROR],
+			total.Definitions,
+		))
 	}
 
+	userUsages := []*UserConnectorUsage{}
+
+	// Roll over all users
+	userPageToken := ""
+	userPageSizeMax := int64(repository.MaxPageSize)
+
 	for {
 		userResp, err := u.mgmtPrivateServiceClient.ListUsersAdmin(ctx, &mgmtPB.ListUsersAdminRequest{
 			PageSize:  &userPageSizeMax,
 			PageToken: &userPageToken,
 		})
 		if err != nil {
-			logger.Error(fmt.Sprintf("[mgmt-backend: ListUser] %s", err))
-			break
+			return userUsages, fmt.Errorf("[mgmt-backend: ListUser] %w", err)
 		}
 
-		// Roll all pipeline resources on a user
 		for _, user := range userResp.Users {
 
-			connPageToken := ""
-
-			srcConnConnectedStateNum := int64(0)
-			srcConnDisconnectedStateNum := int64(0)
-			srcConnDefSet := make(map[string]struct{})
-			for {
-				dbSrcConns, _, connNextPageToken, err := u.repository.ListConnectors(
-					ctx,
-					fmt.Sprintf("users/%s", user.GetUid()),
-					int64(repository.MaxPageSize),
-					connPageToken,
-					true,
-					filtering.Filter{
-						CheckedExpr: srcCheckedExpr,
-					},
-				)
-
-				if err != nil {
-					logger.Error(fmt.Sprintf("%s", err))
+			ownerPermalink := fmt.Sprintf("users/%s", user.GetUid())
+			userUsage := &UserConnectorUsage{
+				UserUID: user.GetUid(),
+				Types:   map[connectorPB.ConnectorType]*ConnectorTypeUsage{},
+			}
+			for _, count := range byOwner[ownerPermalink] {
+				userUsage.count(connectorPB.ConnectorType(count.ConnectorType), connectorPB.Connector_State(count.State), connDefIDs[count.ConnectorDefinitionUID], count.Count)
+			}
+			for _, count := range public {
+				if count.Owner != ownerPermalink {
+					userUsage.count(connectorPB.ConnectorType(count.ConnectorType), connectorPB.Connector_State(count.State), connDefIDs[count.ConnectorDefinitionUID], count.Count)
 				}
+			}
 
-				for _, conn := range dbSrcConns {
-					if conn.State == datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED) {
-						srcConnConnectedStateNum++
-					}
-					if conn.State == datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED) {
-						srcConnDisconnectedStateNum++
-					}
-					srcConnDef, err := u.connectorSource.GetConnectorDefinitionByUid(conn.ConnectorDefinitionUID)
-					if err != nil {
-						logger.Error(fmt.Sprintf("%s", err))
-					}
-					srcConnDefSet[srcConnDef.GetId()] = struct{}{}
-				}
+			userUsages = append(userUsages, userUsage)
+		}
 
-				if connNextPageToken == "" {
-					break
-				} else {
-					connPageToken = connNextPageToken
-				}
-			}
+		if userResp.NextPageToken == "" {
+			break
+		}
+		userPageToken = userResp.NextPageToken
+	}
 
-			srcConnDefs := make([]string, 0, len(srcConnDefSet))
-			for k := range srcConnDefSet {
-				srcConnDefs = append(srcConnDefs, k)
-			}
+	return userUsages, nil
+}
 
-			connPageToken = ""
-
-			dstConnConnectedStateNum := int64(0)
-			dstConnDisconnectedStateNum := int64(0)
-			dstConnDefSet := make(map[string]struct{})
-			for {
-				dbDstConns, _, connNextPageToken, err := u.repository.ListConnectors(
-					ctx,
-					fmt.Sprintf("users/%s", user.GetUid()),
-					int64(repository.MaxPageSize),
-					connPageToken,
-					true,
-					filtering.Filter{
-						CheckedExpr: dstCheckedExpr,
-					},
-				)
-
-				if err != nil {
-					logger.Error(fmt.Sprintf("%s", err))
-				}
+// typeUsage returns the usage of the connector type, empty if the user has none
+func (uu *UserConnectorUsage) typeUsage(connType connectorPB.ConnectorType) *ConnectorTypeUsage {
+	if typeUsage, ok := uu.Types[connType]; ok {
+		return typeUsage
+	}
+	return newConnectorTypeUsage()
+}
 
-				for _, conn := range dbDstConns {
-					if conn.State == datamodel.ConnectorState(connectorPB.Connector_STATE_CONNECTED) {
-						dstConnConnectedStateNum++
-					}
-					if conn.State == datamodel.ConnectorState(connectorPB.Connector_STATE_DISCONNECTED) {
-						dstConnDisconnectedStateNum++
-					}
-					dstConnDef, err := u.connectorDestination.GetConnectorDefinitionByUid(conn.ConnectorDefinitionUID)
-					if err != nil {
-						logger.Error(fmt.Sprintf("%s", err))
-					}
-					dstConnDefSet[dstConnDef.GetId()] = struct{}{}
-				}
+// count adds num connectors of the type, the state and the definition, whose
+// id is empty if unknown
+func (uu *UserConnectorUsage) count(connType connectorPB.ConnectorType, state connectorPB.Connector_State, connDefID string, num int64) {
+	typeUsage, ok := uu.Types[connType]
+	if !ok {
+		typeUsage = newConnectorTypeUsage()
+		uu.Types[connType] = typeUsage
+	}
+	typeUsage.States[state] += num
+	if connDefID != "" {
+		typeUsage.Definitions[connDefID] += num
+	}
+}
 
-				if connNextPageToken == "" {
-					break
-				} else {
-					connPageToken = connNextPageToken
-				}
-			}
+func newConnectorTypeUsage() *ConnectorTypeUsage {
+	return &ConnectorTypeUsage{
+		States:      map[connectorPB.Connector_State]int64{},
+		Definitions: map[string]int64{},
+	}
+}
 
-			dstConnDefs := make([]string, 0, len(dstConnDefSet))
-			for k := range dstConnDefSet {
-				dstConnDefs = append(dstConnDefs, k)
-			}
+// definitionIDs returns the ids of the definitions in use, sorted
+func (tu *ConnectorTypeUsage) definitionIDs() []string {
+	ids := make([]string, 0, len(tu.Definitions))
+	for id := range tu.Definitions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
 
-			pbConnectorUsageData = append(pbConnectorUsageData, &usagePB.ConnectorUsageData_UserUsageData{
-				UserUid:                                  user.GetUid(),
-				SourceConnectorConnectedStateNum:         srcConnConnectedStateNum,
-				SourceConnectorDisconnectedStateNum:      srcConnDisconnectedStateNum,
-				SourceConnectorDefinitionIds:             srcConnDefs,
-				DestinationConnectorConnectedStateNum:    dstConnConnectedStateNum,
-				DestinationConnectorDisconnectedStateNum: dstConnDisconnectedStateNum,
-				DestinationConnectorDefinitionIds:        dstConnDefs,
-			})
+func sortedConnectorTypes(usages map[connectorPB.ConnectorType]*ConnectorTypeUsage) []connectorPB.ConnectorType {
+	connTypes := make([]connectorPB.ConnectorType, 0, len(usages))
+	for connType := range usages {
+		connTypes = append(connTypes, connType)
+	}
+	sort.Slice(connTypes, func(i, j int) bool {
+		return connTypes[i] < connTypes[j]
+	})
+	return connTypes
+}
 
+// RunReporter spools a report at the report frequency and sends the
+// spooled reports until ctx is done
+func (u *usage) RunReporter(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(5 * time.Second):
+	}
+	for {
+		// The reporting is paused while it is disabled by a reload
+		if config.Current().Server.Usage.Enabled {
+			u.report(ctx)
 		}
-
-		if userResp.NextPageToken == "" {
-			break
-		} else {
-			userPageToken = userResp.NextPageToken
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reportFrequency):
 		}
 	}
+}
 
-	logger.Debug("Send retrieved usage data...")
+// TriggerSingleReporter spools a report and sends the spooled reports, the
+// reports left unsent are sent after the next start
+func (u *usage) TriggerSingleReporter(ctx context.Context) {
+	u.report(ctx)
+}
 
-	return &usagePB.SessionReport_ConnectorUsageData{
-		ConnectorUsageData: &usagePB.ConnectorUsageData{
-			Usages: pbConnectorUsageData,
-		},
-	}
+// GetReporterStatus returns the status of the reporter
+func (u *usage) GetReporterStatus() *ReporterStatus {
+	u.mu.Lock()
+	status := u.status
+	u.mu.Unlock()
+
+	status.QueuedReports, status.DroppedReports = u.spool.stats()
+	return &status
 }
 
-func (u *usage) StartReporter(ctx context.Context) {
-	if u.reporter == nil {
+func (u *usage) report(ctx context.Context) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	usageData, ok := u.RetrieveUsageData().(*usagePB.SessionReport_ConnectorUsageData)
+	if !ok {
 		return
 	}
+	b, err := protojson.Marshal(usageData.ConnectorUsageData)
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	if err := u.spool.append(&spoolRecord{CreateTime: time.Now().UTC(), UsageData: b}); err != nil {
+		logger.Error(fmt.Sprintf("unable to spool the usage report: %v", err))
+	}
+
+	if err := u.flush(ctx); err != nil {
+		logger.Warn(fmt.Sprintf("usage reports left in the spool: %v", err))
+	}
+}
+
+// flush sends the spooled reports in order and stops at the first failure,
+// the reports which cannot be decoded are moved aside
+func (u *usage) flush(ctx context.Context) error {
 
 	logger, _ := logger.GetZapLogger(ctx)
-	go func() {
-		time.Sleep(5 * time.Second)
-		err := usageClient.StartReporter(ctx, u.reporter, usagePB.Session_SERVICE_CONNECTOR, config.Config.Server.Edition, u.version, u.RetrieveUsageData)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.reporter == nil {
+		reporterCtx, cancel := context.WithTimeout(ctx, reportTimeout)
+		reporter, err := usageClient.InitReporter(reporterCtx, u.usageServiceClient, usagePB.Session_SERVICE_CONNECTOR, config.Config.Server.Edition, u.version)
+		cancel()
+		if err != nil {
+			return u.failed(err)
+		}
+		u.reporter = reporter
+	}
+
+	for {
+		line, next, err := u.spool.peek()
+		if err != nil {
+			return u.failed(err)
+		}
+		if line == nil {
+			return nil
+		}
+
+		usageData, err := decodeSpoolRecord(line)
+		if err != nil {
+			logger.Error(fmt.Sprintf("usage report moved to the rejected reports of the spool: %v", err))
+			if err := u.spool.reject(line, next); err != nil {
+				return u.failed(err)
+			}
+			continue
+		}
+		reportCtx, cancel := context.WithTimeout(ctx, reportTimeout)
+		err = usageClient.SingleReporter(reportCtx, u.reporter, usagePB.Session_SERVICE_CONNECTOR, config.Config.Server.Edition, u.version, &usagePB.SessionReport_ConnectorUsageData{
+			ConnectorUsageData: usageData,
+		})
+		cancel()
 		if err != nil {
-			logger.Error(fmt.Sprintf("unable to start reporter: %v\n", err))
+			return u.failed(err)
+		}
+		if err := u.spool.commit(next); err != nil {
+			return u.failed(err)
 		}
-	}()
+		u.status.LastSuccessTime = time.Now().UTC()
+	}
 }
 
-func (u *usage) TriggerSingleReporter(ctx context.Context) {
-	if u.reporter == nil {
-		return
+// decodeSpoolRecord decodes the usage data of a spooled report
+func decodeSpoolRecord(line []byte) (*usagePB.ConnectorUsageData, error) {
+	record := &spoolRecord{}
+	if err := json.Unmarshal(line, record); err != nil {
+		return nil, err
 	}
-	logger, _ := logger.GetZapLogger(ctx)
-	err := usageClient.SingleReporter(ctx, u.reporter, usagePB.Session_SERVICE_CONNECTOR, config.Config.Server.Edition, u.version, u.RetrieveUsageData())
-	if err != nil {
-		logger.Error(fmt.Sprintf("unable to trigger single reporter: %v\n", err))
+	usageData := &usagePB.ConnectorUsageData{}
+	if err := protojson.Unmarshal(record.UsageData, usageData); err != nil {
+		return nil, err
 	}
+	return usageData, nil
+}
+
+// failed records the error in the status, u.mu is held
+func (u *usage) failed(err error) error {
+	u.status.LastErrorTime = time.Now().UTC()
+	u.status.LastError = err.Error()
+	return err
 }
@@ -1,6 +1,10 @@
 package utils
 
-import "strings"
+import (
+	"context"
+	"strings"
+	"time"
+)
 
 const (
 	CreateEvent     string = "Create"
@@ -10,6 +14,7 @@ const (
 	DisconnectEvent string = "Disconnect"
 	RenameEvent     string = "Rename"
 	ExecuteEvent    string = "Execute"
+	CloneEvent      string = "Clone"
 )
 
 func IsAuditEvent(eventName string) bool {
@@ -19,10 +24,21 @@ func IsAuditEvent(eventName string) bool {
 		strings.HasPrefix(eventName, ConnectEvent) ||
 		strings.HasPrefix(eventName, DisconnectEvent) ||
 		strings.HasPrefix(eventName, RenameEvent) ||
-		strings.HasPrefix(eventName, ExecuteEvent)
+		strings.HasPrefix(eventName, ExecuteEvent) ||
+		strings.HasPrefix(eventName, CloneEvent)
 }
 
-// TODO: billable connectors TBD
+// IsBillableEvent returns true for the connector executions, which are
+// metered per owner
 func IsBillableEvent(eventName string) bool {
-	return false
+	return strings.HasPrefix(eventName, ExecuteEvent)
+}
+
+// WithDefaultTimeout returns ctx bounded by timeout, unless the caller
+// already set a deadline which is then honoured as is
+func WithDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
 }